@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/compare"
+	"github.com/dkorittki/loago/internal/pkg/instructor/resultfile"
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareJSON          bool
+	compareTTFBTolerance float64
+	compareErrTolerance  float64
+	compareAlpha         float64
+
+	// compareCmd represents the compare command
+	compareCmd = &cobra.Command{
+		Use:   "compare <old result file> <new result file>",
+		Short: "Compare two runs and report regressions",
+		Long: `Compare reads two result files written by 'loago instruct run' and compares
+the TTFB percentiles and error rates per endpoint and over all results.
+
+A change is flagged as regression, if it exceeds its tolerance and is statistically
+significant. TTFB distributions are compared with a Mann-Whitney U test, error rates
+with a two-proportion z-test. Loago exits with code 98, if any regression was found.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runCompare,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().BoolVar(&compareJSON, "json", false, "Print the comparison as JSON")
+	compareCmd.Flags().Float64Var(&compareTTFBTolerance, "ttfb-tolerance", compare.DefaultTolerances.TTFB*100,
+		"Allowed increase of TTFB percentiles in percent")
+	compareCmd.Flags().Float64Var(&compareErrTolerance, "error-tolerance", compare.DefaultTolerances.ErrorRate*100,
+		"Allowed increase of the error rate in percentage points")
+	compareCmd.Flags().Float64Var(&compareAlpha, "alpha", compare.DefaultTolerances.Alpha,
+		"Significance level, changes with a higher p-value are no regressions")
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	old, err := loadSummary(args[0])
+	if err != nil {
+		return err
+	}
+
+	new, err := loadSummary(args[1])
+	if err != nil {
+		return err
+	}
+
+	c := compare.Compare(old, new, compare.Tolerances{
+		TTFB:      compareTTFBTolerance / 100,
+		ErrorRate: compareErrTolerance / 100,
+		Alpha:     compareAlpha,
+	})
+
+	if compareJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(c)
+	} else {
+		err = c.Print(os.Stdout)
+	}
+
+	if err != nil {
+		logger.Error().Err(err).Msg("cannot print comparison")
+		return err
+	}
+
+	if c.Regressions > 0 {
+		exitCode = exitRegressions
+	}
+
+	return nil
+}
+
+// loadSummary reads the result file at path into a summary.
+func loadSummary(path string) (*stats.Summary, error) {
+	r, err := resultfile.Open(path)
+	if err != nil {
+		logger.Error().Err(err).Str("path", path).Msg("cannot open result file")
+		return nil, err
+	}
+	defer r.Close()
+
+	s, err := compare.Load(r)
+	if err != nil {
+		logger.Error().Err(err).Str("path", path).Msg("cannot read result file")
+		return nil, err
+	}
+
+	return s, nil
+}
@@ -45,7 +45,9 @@ func runPing(cmd *cobra.Command, args []string) {
 func preRunPing(cmd *cobra.Command, args []string) error {
 	logger.Info().Msg("Connecting to workers")
 
-	ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
 	err := instructor.Connect(ctx, &logger)
 
 	if err != nil {
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/report"
+	"github.com/dkorittki/loago/internal/pkg/instructor/resultfile"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportOutput string
+
+	// reportCmd represents the report command
+	reportCmd = &cobra.Command{
+		Use:   "report <result file>",
+		Short: "Generate a HTML report of a finished run",
+		Long: `Report reads a result file written by 'loago instruct run' and generates
+a single static HTML file from it.
+
+The report contains a TTFB-over-time chart, a latency histogram, per-endpoint tables,
+a per-worker breakdown and a status code timeline. It doesn't depend on any external
+assets, so it can be opened from anywhere, i.e. a shared drive.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runReport,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVarP(&reportOutput, "output", "o", "",
+		"Path to the generated HTML file (default is the result file path with extension .html)")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	in := args[0]
+	out := reportOutput
+	if out == "" {
+		out = strings.TrimSuffix(in, filepath.Ext(in)) + ".html"
+	}
+
+	r, err := resultfile.Open(in)
+	if err != nil {
+		logger.Error().Err(err).Str("path", in).Msg("cannot open result file")
+		return err
+	}
+	defer r.Close()
+
+	rep, err := report.Load(r)
+	if err != nil {
+		logger.Error().Err(err).Str("path", in).Msg("cannot read result file")
+		return err
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		logger.Error().Err(err).Str("path", out).Msg("cannot create report file")
+		return err
+	}
+	defer f.Close()
+
+	if err := rep.Render(f); err != nil {
+		logger.Error().Err(err).Str("path", out).Msg("cannot render report")
+		return err
+	}
+
+	logger.Info().Str("path", out).Msg("Report generated")
+
+	return nil
+}
@@ -10,7 +10,25 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// Exit codes of loago.
+const (
+	// exitError is used, if a command failed.
+	exitError = 1
+
+	// exitThresholdsFailed is used, if a run finished, but at least
+	// one of its thresholds failed. It differs from exitError, so CI
+	// pipelines can tell a failed loadtest from a broken one.
+	exitThresholdsFailed = 99
+
+	// exitRegressions is used, if a comparison of two runs found regressions.
+	exitRegressions = 98
+)
+
 var (
+	// exitCode is set by commands to exit with a non-zero exit code
+	// without returning an error.
+	exitCode int
+
 	cfgFile       string
 	instructorCfg *config.InstructorConfig
 	logger        = zerolog.New(
@@ -41,7 +59,11 @@ across one or more Loago instances in worker mode via network.`,
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitError)
+	}
+
+	if exitCode != 0 {
+		os.Exit(exitCode)
 	}
 }
 
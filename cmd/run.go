@@ -3,20 +3,41 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+	"github.com/dkorittki/loago/internal/pkg/instructor/resultfile"
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+	"github.com/dkorittki/loago/internal/pkg/instructor/threshold"
+	"github.com/dkorittki/loago/pkg/instructor/config"
 	"github.com/spf13/cobra"
 )
 
 // runCmd represents the run command
 var (
+	// durationGracePeriod is the time given to workers to finish
+	// a time-bounded run before the instructor stops on its own.
+	durationGracePeriod = 30 * time.Second
+
+	// thresholdInterval is the interval in which thresholds
+	// are evaluated while the run is in progress.
+	thresholdInterval = 5 * time.Second
+
+	resultPath          string
+	resultFormat        string
+	resultFlushInterval time.Duration
+
 	runCmd = &cobra.Command{
-		Use:      "run",
-		Short:    "Run benchmarks",
-		Long:     "Runs benchmarks on all configured workers and store the results on disk.",
+		Use:   "run",
+		Short: "Run benchmarks",
+		Long: `Runs benchmarks on all configured workers and store the results on disk.
+
+If thresholds are configured, they are evaluated at the end of the run.
+Loago exits with code 99, if at least one threshold failed.`,
 		Run:      runRun,
 		PreRunE:  preRunRun,
 		PostRunE: postRunRun,
@@ -26,23 +47,62 @@ var (
 func init() {
 	instructCmd.AddCommand(runCmd)
 
-	runCmd.Flags().String("result", "results.json", "Path to file in which the results will be stored")
+	runCmd.Flags().StringVar(&resultPath, "result", "results.jsonl", "Path to file in which the results will be stored")
+	runCmd.Flags().StringVar(&resultFormat, "result-format", "",
+		"Format of the result file, either 'jsonl' or 'csv' (default derived from the file extension)")
+	runCmd.Flags().DurationVar(&resultFlushInterval, "result-flush-interval", resultfile.DefaultFlushInterval,
+		"Interval in which received results are flushed to disk")
 }
 
 func runRun(cmd *cobra.Command, args []string) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	thresholds, err := threshold.NewSet(instructorCfg)
+	if err != nil {
+		logger.Error().Err(err).Msg("cannot parse thresholds")
+		exitCode = exitError
+		return
+	}
+
+	format := resultfile.Format(resultFormat)
+	if format == "" {
+		format = resultfile.FormatFromPath(resultPath)
+	}
+
+	header := &resultfile.Header{
+		Start:  time.Now(),
+		Config: instructorCfg.Redacted(),
+	}
+	for _, w := range instructor.Workers {
+		header.Workers = append(header.Workers, w.String())
+	}
+
+	resultWriter, err := resultfile.Create(resultPath, format, header, resultFlushInterval)
+	if err != nil {
+		logger.Error().Err(err).Str("path", resultPath).Msg("cannot create result file")
+		exitCode = exitError
+		return
+	}
+	defer closeResultWriter(resultWriter)
+
+	logger.Info().Str("path", resultPath).Str("format", string(format)).Msg("Writing results")
 	logger.Info().Msg("Starting run request")
 
-	results, err := instructor.Run(ctx, &logger, instructorCfg.Endpoints,
-		instructorCfg.Amount, instructorCfg.MinWait, instructorCfg.MaxWait)
+	results, err := instructor.Run(ctx, &logger, instructorCfg)
 
 	if err != nil {
 		logger.Error().Err(err).Msg("cannot initiate run request to workers")
+		exitCode = exitError
 		return
 	}
 
+	summary := stats.NewSummary()
+	defer checkResults(summary)
+	defer checkWorkers(instructor)
+	defer checkThresholds(thresholds, summary)
+	defer printSummary(summary)
+
 	// stop requests on sigint and sigterm
 	sigs := make(chan os.Signal, 1)
 	done := make(chan bool, 1)
@@ -55,21 +115,180 @@ func runRun(cmd *cobra.Command, args []string) {
 		done <- true
 	}()
 
+	// stop requests, if workers don't finish a time-bounded run in time
+	var timeout <-chan time.Time
+	if d := instructorCfg.RunDuration(); d > 0 {
+		logger.Info().Dur("duration", d).Msg("Run is time-bounded")
+		timeout = time.After(d + durationGracePeriod)
+	}
+
+	var thresholdTicker <-chan time.Time
+	if len(thresholds) > 0 {
+		t := time.NewTicker(thresholdInterval)
+		defer t.Stop()
+		thresholdTicker = t.C
+	}
+
+	// failing tracks thresholds currently failing to log each change only once
+	failing := make(map[*threshold.Threshold]bool)
+
 	for {
 		select {
-		case res := <-results:
-			logger.Info().Interface("result", res).Msg("received result")
+		case res, ok := <-results:
+			if !ok {
+				logger.Info().Msg("No worker left to receive results from")
+				return
+			}
+
+			logResult(&res)
+			if res.Event != "" {
+				logger.Warn().Str("worker", res.Worker).Int("user", res.User).Str("event", res.Event).Str("cause", res.Error).Msg("worker reported an event")
+			}
+			writeResult(resultWriter, &res)
+			summary.Add(&res)
 		case <-done:
 			logger.Info().Msg("Stopping requests to workers")
 			return
+		case <-timeout:
+			logger.Warn().Msg("Workers didn't finish in time, stopping requests to workers")
+			return
+		case <-thresholdTicker:
+			if watchThresholds(thresholds, summary, failing) && instructorCfg.AbortOnThreshold {
+				logger.Warn().Msg("Threshold can't pass anymore, stopping requests to workers")
+				return
+			}
+		}
+	}
+}
+
+// logResult logs a compact summary of res. Results may contain megabytes
+// of HARs and artifacts and unredacted values, which don't belong into logs.
+func logResult(res *client.Result) {
+	var url string
+	if res.URL != nil {
+		url = res.URL.String()
+	}
+
+	logger.Debug().
+		Str("worker", res.Worker).
+		Int("user", res.User).
+		Str("url", url).
+		Int("status", res.HttpStatusCode).
+		Dur("ttfb", res.Ttfb).
+		Str("error_class", res.ErrorClass).
+		Msg("received result")
+}
+
+// writeResult writes res into w and logs failures,
+// since a single failed write should not abort the run.
+func writeResult(w *resultfile.Writer, res *client.Result) {
+	if err := w.Write(res); err != nil {
+		logger.Error().Err(err).Msg("cannot write result into result file")
+	}
+}
+
+// printSummary prints the aggregated statistics of the run on stdout.
+func printSummary(s *stats.Summary) {
+	fmt.Println()
+	if err := s.Print(os.Stdout); err != nil {
+		logger.Error().Err(err).Msg("cannot print summary")
+	}
+}
+
+// watchThresholds evaluates thresholds against the results received so far
+// and logs thresholds which started or stopped failing since the last call.
+// It reports whether any threshold failed and can't pass anymore.
+func watchThresholds(set threshold.Set, s *stats.Summary, failing map[*threshold.Threshold]bool) bool {
+	var final bool
+
+	for _, r := range set.Evaluate(s) {
+		// wait for the first samples instead of reporting every threshold as failing
+		if r.Unsampled {
+			continue
+		}
+
+		if !r.Passed && !failing[r.Threshold] {
+			logger.Warn().Str("threshold", r.Expression).Str("endpoint", r.Endpoint).
+				Str("actual", r.Actual).Msg("Threshold is failing")
+		} else if r.Passed && failing[r.Threshold] {
+			logger.Info().Str("threshold", r.Expression).Str("endpoint", r.Endpoint).
+				Str("actual", r.Actual).Msg("Threshold is passing again")
 		}
+
+		failing[r.Threshold] = !r.Passed
+		final = final || r.Final
 	}
+
+	return final
+}
+
+// checkThresholds evaluates thresholds against all results of the run,
+// prints the outcome on stdout and sets the exit code, if any threshold failed.
+func checkThresholds(set threshold.Set, s *stats.Summary) {
+	if len(set) == 0 {
+		return
+	}
+
+	results := set.Evaluate(s)
+
+	fmt.Println()
+	if err := threshold.Print(os.Stdout, results); err != nil {
+		logger.Error().Err(err).Msg("cannot print thresholds")
+	}
+
+	if failed := threshold.Failed(results); len(failed) > 0 {
+		logger.Error().Int("failed", len(failed)).Int("total", len(results)).Msg("Thresholds failed")
+		exitCode = exitThresholdsFailed
+	}
+}
+
+// checkResults sets the exit code, if the run has no results at all.
+// Such a run is broken rather than failed, so it overrides failed thresholds.
+func checkResults(s *stats.Summary) {
+	if s.Total.Requests > 0 {
+		return
+	}
+
+	logger.Error().Msg("Run finished without any results")
+	exitCode = exitError
+}
+
+// checkWorkers sets the exit code, if any worker failed during the run,
+// i.e. because it crashed or rejected the run. Results of the other workers
+// don't make up for it, so like a run without results it overrides failed thresholds.
+func checkWorkers(c *client.Client) {
+	n := c.FailedWorkers()
+	if n == 0 {
+		return
+	}
+
+	logger.Error().Int("workers", n).Msg("Workers failed during the run")
+	exitCode = exitError
+}
+
+// closeResultWriter writes the footer of the result file and closes it.
+func closeResultWriter(w *resultfile.Writer) {
+	count := w.Count()
+
+	if err := w.Close(time.Now()); err != nil {
+		logger.Error().Err(err).Msg("cannot close result file")
+		return
+	}
+
+	logger.Info().Uint64("results", count).Str("path", resultPath).Msg("Results saved")
 }
 
 func preRunRun(cmd *cobra.Command, args []string) error {
+	if err := config.ValidateInstructorConfig(instructorCfg); err != nil {
+		logger.Error().Err(err).Msg("invalid config")
+		return err
+	}
+
 	logger.Info().Msg("Connecting to workers")
 
-	ctx, _ := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
 	err := instructor.Connect(ctx, &logger)
 
 	if err != nil {
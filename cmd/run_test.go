@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreRunRun_InvalidConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loago")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "loago.yaml")
+	err = ioutil.WriteFile(path, []byte(`instructor:
+  timeout: -1s
+  endpoints:
+    - url: http://foo.bar
+      weight: 1
+`), 0644)
+	require.NoError(t, err)
+
+	defer func(f string) { cfgFile = f }(cfgFile)
+	cfgFile = path
+	initConfig()
+
+	require.NotNil(t, instructorCfg)
+	assert.EqualError(t, preRunRun(runCmd, nil), "invalid timeout '-1s'")
+}
+
+func TestCheckResults(t *testing.T) {
+	defer func(c int) { exitCode = c }(exitCode)
+
+	s := stats.NewSummary()
+	exitCode = exitThresholdsFailed
+	checkResults(s)
+	assert.Equal(t, exitError, exitCode)
+
+	exitCode = 0
+	s.Add(&client.Result{HttpStatusCode: 200})
+	checkResults(s)
+	assert.Zero(t, exitCode)
+}
+
+func TestLogResult(t *testing.T) {
+	defer func(l zerolog.Logger) { logger = l }(logger)
+
+	var buf bytes.Buffer
+	logger = zerolog.New(&buf)
+
+	u, err := url.Parse("http://foo.bar")
+	require.NoError(t, err)
+
+	logResult(&client.Result{
+		Worker:         "w",
+		User:           3,
+		URL:            u,
+		HttpStatusCode: 200,
+		Ttfb:           100 * time.Millisecond,
+		HAR:            &client.HAR{JSON: []byte(`{"log":{"entries":"secret"}}`)},
+		Artifact:       &client.Artifact{HTML: "<html>secret</html>"},
+	})
+
+	assert.JSONEq(t, `{"level":"debug","worker":"w","user":3,"url":"http://foo.bar","status":200,"ttfb":100,"error_class":"","message":"received result"}`, buf.String())
+
+	// events have no URL
+	buf.Reset()
+	logResult(&client.Result{Worker: "w", Event: "restart"})
+	assert.Contains(t, buf.String(), `"url":""`)
+}
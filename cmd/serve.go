@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dkorittki/loago/internal/pkg/worker/server"
+	"github.com/dkorittki/loago/internal/pkg/worker/service/loadtest"
+	"github.com/dkorittki/loago/pkg/worker/runner"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -15,6 +19,16 @@ var (
 	certPath string
 	keyPath  string
 
+	chromePath       string
+	chromeFlags      []string
+	headful          bool
+	cacheDir         string
+	windowSize       string
+	ignoreCertErrors bool
+	remoteChrome     []string
+	maxRestarts      int
+	restartBackoff   time.Duration
+
 	// serveCmd represents the serve command
 	serveCmd = &cobra.Command{
 		Use:   "serve",
@@ -26,13 +40,55 @@ coming from other loago instances in instructor mode. It will use a Chrome
 based browser to perform the loadtest.
 
 Make sure you have Chrome or Chromium installed on the system, where you want
-to use Loago in worker mode.`,
+to use Loago in worker mode. The chrome flags configure how Chrome is launched,
+i.e. a Chrome at a non-default path with its caches outside of /tmp:
+
+  loago serve --chrome-path /opt/chrome/chrome --cache-dir /var/cache/loago \
+    --chrome-flag no-sandbox --chrome-flag lang=de
+
+Instead of launching Chrome, the worker can drive Chrome processes, which are
+already running, i.e. in separate sandboxed containers. Users are spread across
+them, each in its own browser context:
+
+  loago serve --remote-chrome ws://10.0.0.5:9222/devtools/browser/<id> \
+    --remote-chrome ws://10.0.0.6:9222/devtools/browser/<id>
+
+If a Chrome crashes, disconnects or stops responding, its user is restarted
+with a new Chrome after a backoff, which doubles with every restart of the user.
+A Chrome, which stops responding, is noticed once loading a page times out,
+so with restarts enabled, pages of endpoints without a timeout time out after
+2 minutes. The restarts of every user are limited, so a broken setup still fails
+the loadtest:
+
+  loago serve --max-restarts 50 --restart-backoff 5s`,
 		Run: func(cmd *cobra.Command, args []string) {
+			chrome := &runner.ChromeConfig{
+				ExecPath:         chromePath,
+				Flags:            chromeFlags,
+				Headful:          headful,
+				CacheRoot:        cacheDir,
+				IgnoreCertErrors: ignoreCertErrors,
+				RemoteURLs:       remoteChrome,
+			}
+
+			if windowSize != "" {
+				var err error
+				chrome.WindowWidth, chrome.WindowHeight, err = parseWindowSize(windowSize)
+				if err != nil {
+					log.Fatal().Err(err).Msg("invalid window size")
+				}
+			}
+
 			cfg := server.Config{
 				TLSCertPath:  certPath,
 				TLSKeyPath:   keyPath,
 				Secret:       secret,
 				ListenAdress: fmt.Sprintf("%s:%d", addr, port),
+				Chrome:       chrome,
+				Restarts: loadtest.RestartPolicy{
+					Max:     maxRestarts,
+					Backoff: restartBackoff,
+				},
 			}
 
 			log.Info().Str("listen_adress", cfg.ListenAdress).Msg("start serving")
@@ -57,4 +113,27 @@ func init() {
 	serveCmd.Flags().StringVar(&secret, "secret", "", "basic auth secret used between a worker and an instructor")
 	serveCmd.Flags().StringVar(&certPath, "cert", "", "path to TLS certificate")
 	serveCmd.Flags().StringVar(&keyPath, "key", "", "path to TLS key")
+
+	serveCmd.Flags().StringVar(&chromePath, "chrome-path", "", "path to the Chrome executable (default is searched in the PATH)")
+	serveCmd.Flags().StringArrayVar(&chromeFlags, "chrome-flag", nil,
+		"additional Chrome command line flag, i.e. 'no-sandbox' or 'lang=de', can be repeated")
+	serveCmd.Flags().BoolVar(&headful, "headful", false, "run Chrome with a visible window")
+	serveCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "directory of the Chrome caches (default is the temporary directory)")
+	serveCmd.Flags().StringVar(&windowSize, "window-size", "", "size of the Chrome window, i.e. '1920x1080'")
+	serveCmd.Flags().BoolVar(&ignoreCertErrors, "ignore-cert-errors", false, "make Chrome accept invalid TLS certificates")
+	serveCmd.Flags().StringArrayVar(&remoteChrome, "remote-chrome", nil,
+		"DevTools WebSocket URL of a running Chrome to use instead of launching Chrome, can be repeated")
+	serveCmd.Flags().IntVar(&maxRestarts, "max-restarts", 10,
+		"maximum amount of restarts of a user with a crashed or unresponsive Chrome, 0 disables restarts")
+	serveCmd.Flags().DurationVar(&restartBackoff, "restart-backoff", time.Second, "time to wait before restarting a user")
+}
+
+// parseWindowSize parses a window size of the form WIDTHxHEIGHT.
+func parseWindowSize(s string) (int, int, error) {
+	var w, h int
+	if _, err := fmt.Sscanf(s, "%dx%d", &w, &h); err != nil || w <= 0 || h <= 0 {
+		return 0, 0, errors.New("window size must be WIDTHxHEIGHT, i.e. '1920x1080'")
+	}
+
+	return w, h, nil
 }
@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dkorittki/loago/pkg/api/v1"
@@ -24,12 +25,138 @@ import (
 // AuthSchemeBasic is used as the authentication method description.
 const AuthSchemeBasic = "basic"
 
+// Result is a single response result received from a worker.
 type Result struct {
+	// Time at which the result was received by the instructor.
+	Time time.Time
+
+	// Worker is the address of the worker which sent this result.
+	Worker string
+
+	// User is the ID of the user of the worker, which requested the page,
+	// performed the step or restarted. A restarted user keeps its ID.
+	User int
+
 	URL               *url.URL
 	HttpStatusCode    int
 	HttpStatusMessage string
 	Ttfb              time.Duration
 	Cached            bool
+
+	// Timing contains the page load timings measured by the workers browser.
+	Timing PageTiming
+
+	// Network contains the network phases of the request.
+	Network NetworkTiming
+
+	// Subresources contains every resource loaded by the page,
+	// if requested in the config.
+	Subresources []*Subresource
+
+	// Journey and Step name the step of a journey, which the result belongs to.
+	// URL is the page URL once the step finished.
+	Journey string
+	Step    string
+
+	// Duration is how long the step took.
+	Duration time.Duration
+
+	// Error describes why the request or step failed, if it did.
+	Error string
+
+	// ErrorClass classifies why the request failed, one of "dns", "connect",
+	// "tls", "timeout", "navigation", "no-response" or "assertion".
+	ErrorClass string
+
+	// Event reports something that happened to a user of the worker instead
+	// of a request, i.e. "restart", if it replaced a crashed browser.
+	// Error describes its cause.
+	Event string
+
+	// Emulation is the name of the emulation of the user, if any.
+	Emulation string
+
+	// ColdCache indicates a visit of a first-time visitor,
+	// whose cache and cookies were cleared before.
+	ColdCache bool
+
+	// Artifact shows the page of a failed request, if it was captured.
+	Artifact *Artifact
+
+	// JSErrors is the amount of JavaScript errors of the page, which are uncaught
+	// exceptions, console errors and errors logged by the browser, and
+	// JSErrorMessages their distinct messages.
+	JSErrors        int
+	JSErrorMessages []string
+
+	// HAR contains the requests of the page load as HAR 1.2, if it was sampled.
+	HAR *HAR
+}
+
+// HAR is a sampled page load in the HTTP Archive format.
+type HAR struct {
+	// Name under which the HAR is stored, set once it is stored.
+	Name string
+
+	// JSON of the HAR.
+	JSON []byte
+}
+
+// Artifact shows what the page of a failed request looked like.
+type Artifact struct {
+	// Name under which the artifact is stored, set once it is stored.
+	Name string
+
+	// URL of the page.
+	URL string
+
+	// Screenshot of the viewport as JPEG, if captured.
+	Screenshot []byte
+
+	// HTML of the page, if captured.
+	HTML string
+}
+
+// Subresource is a single resource loaded by a page.
+type Subresource struct {
+	URL               string
+	Type              string
+	HttpStatusCode    int
+	HttpStatusMessage string
+	Ttfb              time.Duration
+
+	// Size is the amount of bytes received over the network.
+	Size   int64
+	Cached bool
+
+	// Initiator is the type of what caused the request, i.e. "parser" or "script",
+	// and InitiatorURL the URL of the causing resource, if known.
+	Initiator    string
+	InitiatorURL string
+
+	// Error describes why the request failed, if it did.
+	Error string
+}
+
+// NetworkTiming contains the phases of a request. A phase is zero,
+// if it didn't happen, i.e. DNS and Connect of a reused connection.
+type NetworkTiming struct {
+	DNS     time.Duration
+	Connect time.Duration
+	SSL     time.Duration
+	Send    time.Duration
+	Wait    time.Duration
+}
+
+// PageTiming contains page load timings relative to the start of the navigation.
+// Each value is zero, if the browser didn't report it.
+type PageTiming struct {
+	DOMContentLoaded       time.Duration
+	Load                   time.Duration
+	FirstPaint             time.Duration
+	FirstContentfulPaint   time.Duration
+	LargestContentfulPaint time.Duration
+	CumulativeLayoutShift  float64
 }
 
 // Worker represents the configuration and connection of a Worker.
@@ -51,6 +178,10 @@ type Client struct {
 	Workers        []*Worker
 	certPool       *x509.CertPool
 	activeRequests uint
+
+	// failedWorkers is the amount of workers of the last run,
+	// which failed before finishing the loadtest.
+	failedWorkers int32
 }
 
 // NewClient returns a new client.
@@ -160,17 +291,51 @@ func (c *Client) Ping(ctx context.Context, logger *zerolog.Logger) error {
 // the results will be written, but returns immediately.
 //
 // logger will be used to log messages mid-process,
-// endpoints are the endpoints which workers will target to,
-// amount specifies the amount of users each worker simulates,
-// minWaitTime and maxWaitTime specify the time to wait between
-// requests in milliseconds.
+// cfg describes the loadtest, i.e. the endpoints which workers will target to,
+// the amount of users each worker simulates or a load profile,
+// the time to wait between requests and the duration of the loadtest.
 //
 // To cancel requesting the workers, ctx has to be canceled.
+// If the loadtest is time-bounded, workers stop on their own once it elapsed,
+// and the result channel is closed after every worker finished.
+// Workers failing before they finish the loadtest are counted by FailedWorkers.
 func (c *Client) Run(
 	ctx context.Context,
 	logger *zerolog.Logger,
-	endpoints []*config.InstructorEndpoint,
-	amount, minWaitTime, maxWaitTime int) (chan Result, error) {
+	cfg *config.InstructorConfig) (chan Result, error) {
+
+	var userData *UserData
+	if cfg.UserData != "" && len(c.Workers) > 0 {
+		var err error
+		if userData, err = ReadUserData(cfg.UserData); err != nil {
+			return nil, err
+		}
+
+		if len(userData.Rows)/len(c.Workers) < cfg.MaxUsers() {
+			logger.Warn().
+				Int("rows", len(userData.Rows)).
+				Int("workers", len(c.Workers)).
+				Int("users_per_worker", cfg.MaxUsers()).
+				Msg("not enough user data for every user, some users share a row")
+		}
+	}
+
+	// create every request before requesting any worker,
+	// so an invalid config doesn't start a partial loadtest
+	reqs := make([]*api.RunRequest, len(c.Workers))
+	for i, w := range c.Workers {
+		req, err := createRunRequest(cfg.ForWorker(w.Adress, w.Port))
+		if err != nil {
+			return nil, err
+		}
+
+		if userData != nil {
+			req.UserData = userData.part(i, len(c.Workers))
+		}
+		reqs[i] = req
+	}
+
+	atomic.StoreInt32(&c.failedWorkers, 0)
 
 	results := make(chan Result, 1024)
 	wg := &sync.WaitGroup{}
@@ -185,10 +350,10 @@ func (c *Client) Run(
 		close(results)
 	}()
 
-	for _, w := range c.Workers {
+	for i, w := range c.Workers {
 		ctx = ctxWithSecret(ctx, AuthSchemeBasic, w.Secret)
 		client := api.NewWorkerClient(w.connection)
-		req := createRunRequest(endpoints, amount, minWaitTime, maxWaitTime)
+		req := reqs[i]
 		workerName := w.String()
 
 		// starting a new request go-routine
@@ -201,6 +366,7 @@ func (c *Client) Run(
 					Str("worker", workerName).
 					Msg("error while awaiting response from worker")
 
+				c.failWorker(ctx)
 				wg.Done()
 				return
 			}
@@ -209,18 +375,18 @@ func (c *Client) Run(
 				resp, err := stream.Recv()
 
 				if err != nil {
-					var msg string
-
 					if err == io.EOF {
-						msg = "connection closed by worker"
+						logger.Info().
+							Str("worker", workerName).
+							Msg("worker finished loadtest")
 					} else {
-						msg = "unexpected error by worker"
-					}
+						logger.Error().
+							Err(err).
+							Str("worker", workerName).
+							Msg("unexpected error by worker")
 
-					logger.Error().
-						Err(err).
-						Str("worker", workerName).
-						Msg(msg)
+						c.failWorker(ctx)
+					}
 
 					wg.Done()
 					return
@@ -234,10 +400,13 @@ func (c *Client) Run(
 						Str("worker", workerName).
 						Msg("cannot decode response from worker")
 
+					c.failWorker(ctx)
 					wg.Done()
 					return
 				}
 
+				r.Time = time.Now()
+				r.Worker = workerName
 				results <- *r
 			}
 		}()
@@ -246,27 +415,226 @@ func (c *Client) Run(
 	return results, nil
 }
 
-func createRunRequest(e []*config.InstructorEndpoint, amount, minWait, maxWait int) *api.RunRequest {
+// FailedWorkers returns the amount of workers of the last run, which failed
+// before they finished the loadtest, i.e. because they crashed, rejected the
+// run or sent a result which can't be decoded. Workers stopped by canceling
+// the run don't count. The amount is final once the result channel is closed.
+func (c *Client) FailedWorkers() int {
+	return int(atomic.LoadInt32(&c.failedWorkers))
+}
+
+// failWorker counts a failed worker, unless it failed since ctx was canceled.
+func (c *Client) failWorker(ctx context.Context) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	atomic.AddInt32(&c.failedWorkers, 1)
+}
+
+func createRunRequest(cfg *config.InstructorConfig) (*api.RunRequest, error) {
 	req := api.RunRequest{
-		Amount:      uint32(amount),
-		MinWaitTime: uint32(minWait),
-		MaxWaitTime: uint32(maxWait),
-		Type:        api.RunRequest_CHROME,
+		Amount:       uint32(cfg.MaxUsers()),
+		MinWaitTime:  uint32(cfg.MinWait),
+		MaxWaitTime:  uint32(cfg.MaxWait),
+		Duration:     uint32(cfg.RunDuration() / time.Millisecond),
+		Timeout:      uint32(cfg.Timeout / time.Millisecond),
+		Type:         api.RunRequest_CHROME,
+		Subresources: cfg.Subresources,
+		Cookies:      cfg.Cookies,
+		ColdVisits:   uint32(cfg.ColdVisitsPercent()),
+
+		ChromeProcesses: uint32(cfg.ChromeProcesses),
+	}
+
+	if cfg.Artifacts != nil {
+		a := cfg.Artifacts.Resolve()
+		req.Artifacts = &api.RunRequest_Artifacts{
+			Html:    a.HTML,
+			Max:     uint32(a.Max),
+			MaxSize: uint32(a.MaxSize),
+		}
+	}
+
+	if cfg.HAR != nil {
+		h := cfg.HAR.Resolve()
+		req.Har = &api.RunRequest_HAR{
+			First:  uint32(h.First),
+			Slower: uint32(h.Slower / time.Millisecond),
+			Max:    uint32(h.Max),
+		}
 	}
 
-	for _, v := range e {
-		req.Endpoints = append(req.Endpoints, &api.RunRequest_Endpoint{Url: v.Url, Weight: uint32(v.Weight)})
+	if cfg.Browser == config.BrowserHTTP {
+		req.Type = api.RunRequest_HTTP
 	}
 
-	return &req
+	for _, v := range cfg.Endpoints {
+		e := &api.RunRequest_Endpoint{
+			Url:        v.Url,
+			Weight:     uint32(v.Weight),
+			Headers:    v.Headers,
+			UserAgent:  v.UserAgent,
+			Timeout:    uint32(v.Timeout / time.Millisecond),
+			OriginOnly: v.OriginOnly,
+		}
+
+		if v.BasicAuth != nil {
+			e.Username = v.BasicAuth.Username
+			e.Password = v.BasicAuth.Password
+		}
+
+		req.Endpoints = append(req.Endpoints, e)
+	}
+
+	for _, v := range cfg.Emulations {
+		e := v.Resolve()
+		req.Emulations = append(req.Emulations, &api.RunRequest_Emulation{
+			Name:               e.Name,
+			Weight:             uint32(e.Weight),
+			Width:              uint32(e.Width),
+			Height:             uint32(e.Height),
+			DeviceScaleFactor:  e.ScaleFactor,
+			Mobile:             e.Mobile,
+			UserAgent:          e.UserAgent,
+			CpuThrottling:      e.CPUThrottling,
+			Latency:            uint32(e.Latency / time.Millisecond),
+			DownloadThroughput: uint32(e.Download * 1000 / 8),
+			UploadThroughput:   uint32(e.Upload * 1000 / 8),
+		})
+	}
+
+	for _, v := range cfg.Journeys {
+		j := &api.RunRequest_Journey{Name: v.Name, Weight: uint32(v.Weight)}
+
+		for i, s := range v.Steps {
+			step, err := createStep(i, s)
+			if err != nil {
+				return nil, fmt.Errorf("journey '%s': %w", v.Name, err)
+			}
+			j.Steps = append(j.Steps, step)
+		}
+
+		req.Journeys = append(req.Journeys, j)
+	}
+
+	for i, s := range cfg.Setup {
+		step, err := createStep(i, s)
+		if err != nil {
+			return nil, fmt.Errorf("setup: %w", err)
+		}
+		req.Setup = append(req.Setup, step)
+	}
+
+	for _, v := range cfg.Stages {
+		s := &api.RunRequest_Stage{
+			Duration: uint32(v.Duration / time.Millisecond),
+			Target:   uint32(v.Target),
+		}
+
+		if v.Ramp == config.RampStep {
+			s.Ramp = api.RunRequest_Stage_STEP
+		}
+
+		req.Stages = append(req.Stages, s)
+	}
+
+	return &req, nil
+}
+
+// stepActions maps journey step actions of the config to the gRPC API.
+var stepActions = map[string]api.RunRequest_Journey_Step_Action{
+	config.ActionNavigate:        api.RunRequest_Journey_Step_NAVIGATE,
+	config.ActionClick:           api.RunRequest_Journey_Step_CLICK,
+	config.ActionType:            api.RunRequest_Journey_Step_TYPE,
+	config.ActionSubmit:          api.RunRequest_Journey_Step_SUBMIT,
+	config.ActionWait:            api.RunRequest_Journey_Step_WAIT,
+	config.ActionSleep:           api.RunRequest_Journey_Step_SLEEP,
+	config.ActionAssertText:      api.RunRequest_Journey_Step_ASSERT_TEXT,
+	config.ActionSetCookie:       api.RunRequest_Journey_Step_SET_COOKIE,
+	config.ActionSetLocalStorage: api.RunRequest_Journey_Step_SET_LOCAL_STORAGE,
+}
+
+// createStep converts the i-th step s of a journey to the gRPC API.
+func createStep(i int, s *config.InstructorStep) (*api.RunRequest_Journey_Step, error) {
+	action, ok := stepActions[s.Action]
+	if !ok {
+		return nil, &UnknownStepActionError{Step: config.StepName(i, s), Action: s.Action}
+	}
+
+	return &api.RunRequest_Journey_Step{
+		Name:     config.StepName(i, s),
+		Action:   action,
+		Url:      s.URL,
+		Selector: s.Selector,
+		Key:      s.Key,
+		Value:    s.Value,
+		Duration: uint32(s.Duration / time.Millisecond),
+		Timeout:  uint32(s.Timeout / time.Millisecond),
+	}, nil
 }
 
 func createResult(res *api.EndpointResult) (*Result, error) {
 	r := Result{
+		User:              int(res.User),
 		Cached:            res.Cached,
 		HttpStatusCode:    int(res.HttpStatusCode),
 		HttpStatusMessage: res.HttpStatusMessage,
-		Ttfb:              time.Duration(res.Ttfb),
+		Ttfb:              time.Duration(res.Ttfb) * time.Millisecond,
+		Timing: PageTiming{
+			DOMContentLoaded:       time.Duration(res.DomContentLoaded) * time.Millisecond,
+			Load:                   time.Duration(res.Load) * time.Millisecond,
+			FirstPaint:             time.Duration(res.FirstPaint) * time.Millisecond,
+			FirstContentfulPaint:   time.Duration(res.FirstContentfulPaint) * time.Millisecond,
+			LargestContentfulPaint: time.Duration(res.LargestContentfulPaint) * time.Millisecond,
+			CumulativeLayoutShift:  float64(res.CumulativeLayoutShift),
+		},
+		Network: NetworkTiming{
+			DNS:     time.Duration(res.Dns) * time.Millisecond,
+			Connect: time.Duration(res.Connect) * time.Millisecond,
+			SSL:     time.Duration(res.Ssl) * time.Millisecond,
+			Send:    time.Duration(res.Send) * time.Millisecond,
+			Wait:    time.Duration(res.Wait) * time.Millisecond,
+		},
+		Journey:  res.Journey,
+		Step:     res.Step,
+		Duration: time.Duration(res.Duration) * time.Millisecond,
+		Error:    res.Error,
+
+		ErrorClass: res.ErrorClass,
+		Event:      res.Event,
+		Emulation:  res.Emulation,
+		ColdCache:  res.ColdCache,
+
+		JSErrors:        int(res.JsErrors),
+		JSErrorMessages: res.JsErrorMessages,
+	}
+
+	for _, s := range res.Subresources {
+		r.Subresources = append(r.Subresources, &Subresource{
+			URL:               s.Url,
+			Type:              s.Type,
+			HttpStatusCode:    int(s.HttpStatusCode),
+			HttpStatusMessage: s.HttpStatusMessage,
+			Ttfb:              time.Duration(s.Ttfb) * time.Millisecond,
+			Size:              s.Size,
+			Cached:            s.Cached,
+			Initiator:         s.Initiator,
+			InitiatorURL:      s.InitiatorUrl,
+			Error:             s.Error,
+		})
+	}
+
+	if a := res.Artifact; a != nil {
+		r.Artifact = &Artifact{
+			URL:        a.Url,
+			Screenshot: a.Screenshot,
+			HTML:       a.Html,
+		}
+	}
+
+	if len(res.Har) > 0 {
+		r.HAR = &HAR{JSON: res.Har}
 	}
 
 	url, err := url.Parse(res.Url)
@@ -283,7 +651,10 @@ func createResult(res *api.EndpointResult) (*Result, error) {
 // connect establishes a gRPC connection to a worker and returns the
 // connection and a cancelation func for ending the connection.
 func connect(ctx context.Context, w *Worker, certPool *x509.CertPool) (*grpc.ClientConn, error) {
-	opts := []grpc.DialOption{grpc.WithBlock()}
+	opts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(api.MaxMessageSize)),
+	}
 
 	if w.Certificate != nil {
 		creds := credentials.NewClientTLSFromCert(certPool, "")
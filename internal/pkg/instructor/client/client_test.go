@@ -2,15 +2,19 @@ package client
 
 import (
 	"context"
+	"io/ioutil"
 	"log"
 	"net"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/dkorittki/loago/internal/pkg/testing/fakeserver"
 	"github.com/dkorittki/loago/pkg/api/v1"
+	"github.com/dkorittki/loago/pkg/instructor/config"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/test/bufconn"
 )
@@ -90,3 +94,345 @@ func TestPing(t *testing.T) {
 	err = client.Disconnect()
 	assert.NoError(t, err)
 }
+
+func TestRun_FailedWorker(t *testing.T) {
+	lis := bufconn.Listen(bufConnBufferSize)
+
+	client := NewClient()
+	require.NoError(t, client.AddWorker("127.0.0.1", 1234, "test123", nil, newBufDialer(lis)))
+
+	// the fake server rejects every run
+	server := newTestServer()
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			log.Fatalf("server exited with error: %v", err)
+		}
+	}()
+	defer server.Stop()
+
+	logger := zerolog.New(ioutil.Discard)
+	require.NoError(t, client.Connect(context.Background(), &logger))
+	defer client.Disconnect()
+
+	results, err := client.Run(context.Background(), &logger, &config.InstructorConfig{
+		Endpoints: []*config.InstructorEndpoint{{Url: "http://foo.bar", Weight: 1}},
+		Amount:    1,
+		MinWait:   1000,
+		MaxWait:   2000,
+	})
+	require.NoError(t, err)
+
+	for range results {
+		t.Error("unexpected result")
+	}
+
+	assert.Equal(t, 1, client.FailedWorkers())
+}
+
+func TestCreateRunRequest_Stages(t *testing.T) {
+	cfg := &config.InstructorConfig{
+		Endpoints: []*config.InstructorEndpoint{
+			{
+				Url:    "http://foo.bar",
+				Weight: 2,
+			},
+		},
+		MinWait: 1000,
+		MaxWait: 2000,
+		Stages: []*config.InstructorStage{
+			{Duration: 2 * time.Minute, Target: 50},
+			{Duration: 10 * time.Minute, Target: 50},
+			{Duration: time.Minute, Target: 0, Ramp: config.RampStep},
+		},
+	}
+
+	req, err := createRunRequest(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint32(50), req.Amount)
+	assert.Equal(t, uint32(13*60*1000), req.Duration)
+	assert.Equal(t, uint32(1000), req.MinWaitTime)
+	assert.Equal(t, uint32(2000), req.MaxWaitTime)
+	assert.Len(t, req.Endpoints, 1)
+	assert.Equal(t, []*api.RunRequest_Stage{
+		{Duration: 120000, Target: 50, Ramp: api.RunRequest_Stage_LINEAR},
+		{Duration: 600000, Target: 50, Ramp: api.RunRequest_Stage_LINEAR},
+		{Duration: 60000, Target: 0, Ramp: api.RunRequest_Stage_STEP},
+	}, req.Stages)
+	assert.NoError(t, req.Validate())
+}
+
+func TestCreateRunRequest_EndpointOptions(t *testing.T) {
+	cfg := &config.InstructorConfig{
+		Endpoints: []*config.InstructorEndpoint{
+			{
+				Url:        "https://staging.foo.bar",
+				Weight:     1,
+				Headers:    map[string]string{"X-Loadtest": "1"},
+				UserAgent:  "loago",
+				BasicAuth:  &config.InstructorBasicAuth{Username: "user", Password: "secret"},
+				Timeout:    30 * time.Second,
+				OriginOnly: true,
+			},
+			{
+				Url:    "http://foo.bar",
+				Weight: 1,
+			},
+		},
+		Amount:  1,
+		MinWait: 1000,
+		MaxWait: 2000,
+		Timeout: 10 * time.Second,
+	}
+
+	req, err := createRunRequest(cfg)
+	require.NoError(t, err)
+
+	require.Len(t, req.Endpoints, 2)
+	assert.Equal(t, map[string]string{"X-Loadtest": "1"}, req.Endpoints[0].Headers)
+	assert.Equal(t, "loago", req.Endpoints[0].UserAgent)
+	assert.Equal(t, "user", req.Endpoints[0].Username)
+	assert.Equal(t, "secret", req.Endpoints[0].Password)
+	assert.Empty(t, req.Endpoints[1].Username)
+	assert.True(t, req.Endpoints[0].OriginOnly)
+	assert.False(t, req.Endpoints[1].OriginOnly)
+	assert.Equal(t, uint32(30000), req.Endpoints[0].Timeout)
+	assert.Zero(t, req.Endpoints[1].Timeout)
+	assert.Equal(t, uint32(10000), req.Timeout)
+	assert.NoError(t, req.Validate())
+}
+
+func TestCreateRunRequest_Emulations(t *testing.T) {
+	cfg := &config.InstructorConfig{
+		Endpoints: []*config.InstructorEndpoint{
+			{
+				Url:    "http://foo.bar",
+				Weight: 1,
+			},
+		},
+		Emulations: []*config.InstructorEmulation{
+			{Name: "mobile", Weight: 7, Device: config.DeviceMotoG4, Network: config.NetworkSlow3G},
+			{Name: "desktop", Device: config.DeviceDesktop, Network: config.NetworkCable, Width: 1920, Height: 1080},
+		},
+		Amount:  1,
+		MinWait: 1000,
+		MaxWait: 2000,
+	}
+
+	req, err := createRunRequest(cfg)
+	require.NoError(t, err)
+
+	require.Len(t, req.Emulations, 2)
+
+	mobile := req.Emulations[0]
+	assert.Equal(t, "mobile", mobile.Name)
+	assert.Equal(t, uint32(7), mobile.Weight)
+	assert.Equal(t, uint32(360), mobile.Width)
+	assert.Equal(t, 3.0, mobile.DeviceScaleFactor)
+	assert.True(t, mobile.Mobile)
+	assert.Contains(t, mobile.UserAgent, "Moto G (4)")
+	assert.Equal(t, 4.0, mobile.CpuThrottling)
+	assert.Equal(t, uint32(2000), mobile.Latency)
+	assert.Equal(t, uint32(50000), mobile.DownloadThroughput)
+
+	// explicit values override the device preset, the weight defaults to 1
+	desktop := req.Emulations[1]
+	assert.Equal(t, uint32(1), desktop.Weight)
+	assert.Equal(t, uint32(1920), desktop.Width)
+	assert.Equal(t, uint32(1080), desktop.Height)
+	assert.False(t, desktop.Mobile)
+	assert.Empty(t, desktop.UserAgent)
+	assert.Equal(t, uint32(28), desktop.Latency)
+	assert.Equal(t, uint32(125000), desktop.UploadThroughput)
+
+	assert.NoError(t, req.Validate())
+}
+
+func TestCreateRunRequest_Cache(t *testing.T) {
+	cfg := &config.InstructorConfig{
+		Endpoints: []*config.InstructorEndpoint{{Url: "http://foo.bar", Weight: 1}},
+		Amount:    1,
+		MinWait:   1000,
+		MaxWait:   2000,
+	}
+
+	for cache, cold := range map[string]uint32{"": 0, config.CacheWarm: 0, config.CacheCold: 100, config.CacheMixed: 30} {
+		cfg.Cache = cache
+		cfg.ColdVisits = 30
+
+		req, err := createRunRequest(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, cold, req.ColdVisits, cache)
+		assert.NoError(t, req.Validate())
+	}
+}
+
+func TestCreateRunRequest_Artifacts(t *testing.T) {
+	cfg := &config.InstructorConfig{
+		Endpoints: []*config.InstructorEndpoint{{Url: "http://foo.bar", Weight: 1}},
+		Amount:    1,
+		MinWait:   1000,
+		MaxWait:   2000,
+	}
+
+	req, err := createRunRequest(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, req.Artifacts)
+
+	cfg.Artifacts = &config.InstructorArtifacts{HTML: true}
+	req, err = createRunRequest(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &api.RunRequest_Artifacts{
+		Html:    true,
+		Max:     config.DefaultArtifactsMax,
+		MaxSize: config.DefaultArtifactsMaxSize,
+	}, req.Artifacts)
+	assert.NoError(t, req.Validate())
+
+	// the config is left untouched
+	assert.Equal(t, 0, cfg.Artifacts.Max)
+}
+
+func TestCreateRunRequest_HAR(t *testing.T) {
+	cfg := &config.InstructorConfig{
+		Endpoints: []*config.InstructorEndpoint{{Url: "http://foo.bar", Weight: 1}},
+		Amount:    1,
+		MinWait:   1000,
+		MaxWait:   2000,
+	}
+
+	req, err := createRunRequest(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, req.Har)
+
+	cfg.HAR = &config.InstructorHAR{First: 5, Slower: 3 * time.Second}
+	req, err = createRunRequest(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &api.RunRequest_HAR{
+		First:  5,
+		Slower: 3000,
+		Max:    config.DefaultHARMax,
+	}, req.Har)
+	assert.NoError(t, req.Validate())
+
+	// the config is left untouched
+	assert.Equal(t, 0, cfg.HAR.Max)
+}
+
+func TestCreateRunRequest_Browser(t *testing.T) {
+	cfg := &config.InstructorConfig{
+		Workers: []*config.InstructorWorkerConfig{
+			{Alias: "browser", Adress: "10.0.0.1", Port: 50051, ChromeProcesses: 8},
+			{Alias: "background", Adress: "10.0.0.2", Port: 50051, Browser: config.BrowserHTTP, Amount: 2000},
+		},
+		Endpoints: []*config.InstructorEndpoint{
+			{
+				Url:    "http://foo.bar",
+				Weight: 1,
+			},
+		},
+		Amount:          20,
+		MinWait:         1000,
+		MaxWait:         2000,
+		Cookies:         true,
+		ChromeProcesses: 2,
+	}
+
+	req, err := createRunRequest(cfg.ForWorker("10.0.0.1", 50051))
+	require.NoError(t, err)
+	assert.Equal(t, api.RunRequest_CHROME, req.Type)
+	assert.Equal(t, uint32(20), req.Amount)
+	assert.Equal(t, uint32(8), req.ChromeProcesses)
+	assert.NoError(t, req.Validate())
+
+	req, err = createRunRequest(cfg.ForWorker("10.0.0.2", 50051))
+	require.NoError(t, err)
+	assert.Equal(t, api.RunRequest_HTTP, req.Type)
+	assert.Equal(t, uint32(2000), req.Amount)
+	assert.Equal(t, uint32(2), req.ChromeProcesses)
+	assert.True(t, req.Cookies)
+	assert.NoError(t, req.Validate())
+
+	// the global config is left untouched
+	assert.Equal(t, "", cfg.Browser)
+	assert.Equal(t, 20, cfg.Amount)
+}
+
+func TestCreateRunRequest_Journeys(t *testing.T) {
+	cfg := &config.InstructorConfig{
+		Amount:  1,
+		MinWait: 1000,
+		MaxWait: 2000,
+		Journeys: []*config.InstructorJourney{
+			{
+				Name:   "checkout",
+				Weight: 3,
+				Steps: []*config.InstructorStep{
+					{Name: "home", Action: config.ActionNavigate, URL: "http://foo.bar"},
+					{Action: config.ActionClick, Selector: "#buy"},
+					{Action: config.ActionSleep, Duration: 2 * time.Second},
+					{Action: config.ActionAssertText, Value: "Thanks", Timeout: 5 * time.Second},
+				},
+			},
+		},
+	}
+
+	req, err := createRunRequest(cfg)
+	require.NoError(t, err)
+
+	assert.Empty(t, req.Endpoints)
+	require.Len(t, req.Journeys, 1)
+	assert.Equal(t, "checkout", req.Journeys[0].Name)
+	assert.Equal(t, uint32(3), req.Journeys[0].Weight)
+	assert.Equal(t, []*api.RunRequest_Journey_Step{
+		{Name: "home", Action: api.RunRequest_Journey_Step_NAVIGATE, Url: "http://foo.bar"},
+		{Name: "2 click", Action: api.RunRequest_Journey_Step_CLICK, Selector: "#buy"},
+		{Name: "3 sleep", Action: api.RunRequest_Journey_Step_SLEEP, Duration: 2000},
+		{Name: "4 assert_text", Action: api.RunRequest_Journey_Step_ASSERT_TEXT, Value: "Thanks", Timeout: 5000},
+	}, req.Journeys[0].Steps)
+	assert.NoError(t, req.Validate())
+
+	// unknown actions are rejected instead of becoming navigations
+	cfg.Journeys[0].Steps[1].Action = "hover"
+	_, err = createRunRequest(cfg)
+	assert.EqualError(t, err, "journey 'checkout': unknown action 'hover' of step '2 hover'")
+}
+
+func TestCreateResult_Step(t *testing.T) {
+	res, err := createResult(&api.EndpointResult{
+		Url:             "http://foo.bar/cart",
+		Journey:         "checkout",
+		Step:            "2 click",
+		Duration:        350,
+		Error:           "step timed out",
+		ErrorClass:      "timeout",
+		Event:           "restart",
+		User:            3,
+		JsErrors:        2,
+		JsErrorMessages: []string{"Uncaught TypeError: cart is undefined"},
+		Artifact: &api.EndpointResult_Artifact{
+			Url:        "http://foo.bar/cart",
+			Screenshot: []byte{0xff, 0xd8},
+			Html:       "<html></html>",
+		},
+		Har: []byte(`{"log":{"version":"1.2"}}`),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "http://foo.bar/cart", res.URL.String())
+	assert.Equal(t, "checkout", res.Journey)
+	assert.Equal(t, "2 click", res.Step)
+	assert.Equal(t, 350*time.Millisecond, res.Duration)
+	assert.Equal(t, "step timed out", res.Error)
+	assert.Equal(t, "timeout", res.ErrorClass)
+	assert.Equal(t, "restart", res.Event)
+	assert.Equal(t, 3, res.User)
+	assert.Equal(t, &HAR{JSON: []byte(`{"log":{"version":"1.2"}}`)}, res.HAR)
+	assert.Equal(t, 2, res.JSErrors)
+	assert.Equal(t, []string{"Uncaught TypeError: cart is undefined"}, res.JSErrorMessages)
+	assert.Equal(t, &Artifact{
+		URL:        "http://foo.bar/cart",
+		Screenshot: []byte{0xff, 0xd8},
+		HTML:       "<html></html>",
+	}, res.Artifact)
+}
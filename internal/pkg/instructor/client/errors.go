@@ -1,5 +1,7 @@
 package client
 
+import "fmt"
+
 type WrappableError interface {
 	error
 	Unwrap() error
@@ -58,3 +60,12 @@ func (e *InvalidConnectionError) Error() string {
 func (e *InvalidConnectionError) Unwrap() error {
 	return e.Err
 }
+
+type UnknownStepActionError struct {
+	Step   string
+	Action string
+}
+
+func (e *UnknownStepActionError) Error() string {
+	return fmt.Sprintf("unknown action '%s' of step '%s'", e.Action, e.Step)
+}
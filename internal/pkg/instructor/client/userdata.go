@@ -0,0 +1,65 @@
+package client
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/dkorittki/loago/pkg/api/v1"
+)
+
+// UserData contains a row of values per simulated user.
+type UserData struct {
+	// Columns contains the names of the values of each row.
+	Columns []string
+
+	// Rows contains the values of each user in the order of Columns.
+	Rows [][]string
+}
+
+// ReadUserData reads user data from the CSV file at path,
+// whose first line names the columns.
+func ReadUserData(path string) (*UserData, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readUserData(f)
+}
+
+func readUserData(r io.Reader) (*UserData, error) {
+	c := csv.NewReader(r)
+	c.TrimLeadingSpace = true
+
+	records, err := c.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, errors.New("user data contains no rows")
+	}
+
+	return &UserData{Columns: records[0], Rows: records[1:]}, nil
+}
+
+// part returns the i-th of n disjoint parts of the rows,
+// so users of different workers never share a row.
+// Every part contains at least one row, so with less rows than
+// parts, some rows are shared.
+func (d *UserData) part(i, n int) *api.RunRequest_UserData {
+	from, to := i*len(d.Rows)/n, (i+1)*len(d.Rows)/n
+	if from == to {
+		from, to = i%len(d.Rows), i%len(d.Rows)+1
+	}
+
+	p := &api.RunRequest_UserData{Columns: d.Columns}
+	for _, r := range d.Rows[from:to] {
+		p.Rows = append(p.Rows, &api.RunRequest_UserData_Row{Values: r})
+	}
+
+	return p
+}
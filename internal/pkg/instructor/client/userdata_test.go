@@ -0,0 +1,73 @@
+package client
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dkorittki/loago/pkg/api/v1"
+	"github.com/dkorittki/loago/pkg/instructor/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadUserData(t *testing.T) {
+	d, err := readUserData(strings.NewReader("user, password\njane, secret1\njohn, secret2\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"user", "password"}, d.Columns)
+	assert.Equal(t, [][]string{{"jane", "secret1"}, {"john", "secret2"}}, d.Rows)
+
+	_, err = readUserData(strings.NewReader("user,password\n"))
+	assert.Error(t, err)
+
+	_, err = readUserData(strings.NewReader("user,password\njane\n"))
+	assert.Error(t, err)
+}
+
+func TestUserData_Part(t *testing.T) {
+	d := &UserData{
+		Columns: []string{"user"},
+		Rows:    [][]string{{"a"}, {"b"}, {"c"}, {"d"}, {"e"}},
+	}
+
+	values := func(p *api.RunRequest_UserData) []string {
+		var v []string
+		for _, r := range p.Rows {
+			v = append(v, r.Values[0])
+		}
+		return v
+	}
+
+	assert.Equal(t, []string{"user"}, d.part(0, 2).Columns)
+	assert.Equal(t, []string{"a", "b"}, values(d.part(0, 2)))
+	assert.Equal(t, []string{"c", "d", "e"}, values(d.part(1, 2)))
+
+	// with less rows than workers, rows are shared
+	assert.Equal(t, []string{"a"}, values(d.part(0, 6)))
+	assert.Equal(t, []string{"a"}, values(d.part(1, 6)))
+	assert.Equal(t, []string{"e"}, values(d.part(5, 6)))
+}
+
+func TestCreateRunRequest_Setup(t *testing.T) {
+	cfg := &config.InstructorConfig{
+		Endpoints: []*config.InstructorEndpoint{{Url: "http://foo.bar/account", Weight: 1}},
+		Amount:    1,
+		MinWait:   1000,
+		MaxWait:   2000,
+		Setup: []*config.InstructorStep{
+			{Action: config.ActionNavigate, URL: "http://foo.bar/login"},
+			{Action: config.ActionType, Selector: "#user", Value: "${user}"},
+			{Name: "token", Action: config.ActionSetLocalStorage, Key: "token", Value: "${token}"},
+		},
+	}
+
+	req, err := createRunRequest(cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*api.RunRequest_Journey_Step{
+		{Name: "1 navigate", Action: api.RunRequest_Journey_Step_NAVIGATE, Url: "http://foo.bar/login"},
+		{Name: "2 type", Action: api.RunRequest_Journey_Step_TYPE, Selector: "#user", Value: "${user}"},
+		{Name: "token", Action: api.RunRequest_Journey_Step_SET_LOCAL_STORAGE, Key: "token", Value: "${token}"},
+	}, req.Setup)
+	assert.NoError(t, req.Validate())
+}
@@ -0,0 +1,220 @@
+// Package compare compares the results of two runs per endpoint and
+// flags regressions. A change only counts as regression, if it exceeds
+// a tolerance and is statistically significant, so noise between two
+// runs of the same scenario isn't reported.
+package compare
+
+import (
+	"io"
+	"sort"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/resultfile"
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+)
+
+// Metrics compared per endpoint.
+const (
+	MetricP50TTFB   = "p50_ttfb"
+	MetricP95TTFB   = "p95_ttfb"
+	MetricP99TTFB   = "p99_ttfb"
+	MetricErrorRate = "error_rate"
+)
+
+// ttfbQuantiles maps TTFB metrics to their quantile.
+var ttfbQuantiles = []struct {
+	metric   string
+	quantile float64
+}{
+	{MetricP50TTFB, 0.50},
+	{MetricP95TTFB, 0.95},
+	{MetricP99TTFB, 0.99},
+}
+
+// Tolerances control which changes are flagged as regression.
+type Tolerances struct {
+	// TTFB is the allowed relative increase of a TTFB percentile, i.e. 0.1 for 10%.
+	TTFB float64
+
+	// ErrorRate is the allowed absolute increase of the error rate,
+	// i.e. 0.01 for one percentage point.
+	ErrorRate float64
+
+	// Alpha is the significance level. Changes with a p-value
+	// of Alpha or above are never flagged as regression.
+	Alpha float64
+}
+
+// DefaultTolerances are the tolerances used, if none are configured.
+var DefaultTolerances = Tolerances{
+	TTFB:      0.1,
+	ErrorRate: 0.01,
+	Alpha:     0.05,
+}
+
+// Change is the change of a single metric between two runs.
+type Change struct {
+	// Metric is the name of the metric, i.e. MetricP95TTFB.
+	Metric string `json:"metric"`
+
+	// Old is the value of the old run. TTFB values are in milliseconds,
+	// the error rate is a ratio between 0 and 1.
+	Old float64 `json:"old"`
+
+	// New is the value of the new run.
+	New float64 `json:"new"`
+
+	// Delta is the relative change for TTFB metrics, i.e. 0.1 for +10%,
+	// and the absolute change for the error rate.
+	// It is zero, if a relative change is undefined since Old is zero.
+	Delta float64 `json:"delta"`
+
+	// PValue is the one-sided p-value of the hypothesis,
+	// that the new run performs worse than the old run.
+	PValue float64 `json:"p_value"`
+
+	// Regression is true, if the change exceeds its tolerance
+	// and is statistically significant.
+	Regression bool `json:"regression"`
+}
+
+// Endpoint is the comparison of a single endpoint.
+type Endpoint struct {
+	// URL of the endpoint.
+	URL string `json:"url"`
+
+	// OldRequests is the amount of requests in the old run.
+	OldRequests uint64 `json:"old_requests"`
+
+	// NewRequests is the amount of requests in the new run.
+	NewRequests uint64 `json:"new_requests"`
+
+	// Changes of all compared metrics.
+	Changes []Change `json:"changes"`
+}
+
+// Regressions returns the amount of regressed metrics.
+func (e *Endpoint) Regressions() int {
+	var n int
+	for _, c := range e.Changes {
+		if c.Regression {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Comparison is the comparison of two runs.
+type Comparison struct {
+	// Tolerances used for flagging regressions.
+	Tolerances Tolerances `json:"-"`
+
+	// Endpoints contains the comparison per endpoint in ascending order.
+	// Endpoints only present in one of both runs are included.
+	Endpoints []*Endpoint `json:"endpoints"`
+
+	// Total is the comparison over all results.
+	Total *Endpoint `json:"total"`
+
+	// Regressions is the amount of regressed metrics
+	// of all endpoints and the total.
+	Regressions int `json:"regressions"`
+}
+
+// Compare compares the summaries of an old and a new run.
+func Compare(old, new *stats.Summary, tol Tolerances) *Comparison {
+	c := &Comparison{
+		Tolerances: tol,
+		Total:      compareStats("", old.Total, new.Total, tol),
+	}
+	c.Regressions = c.Total.Regressions()
+
+	urls := old.URLs()
+	for _, url := range new.URLs() {
+		if _, ok := old.Endpoints[url]; !ok {
+			urls = append(urls, url)
+		}
+	}
+
+	sort.Strings(urls)
+
+	for _, url := range urls {
+		o, ok := old.Endpoints[url]
+		if !ok {
+			o = stats.NewStats()
+		}
+
+		n, ok := new.Endpoints[url]
+		if !ok {
+			n = stats.NewStats()
+		}
+
+		e := compareStats(url, o, n, tol)
+		c.Endpoints = append(c.Endpoints, e)
+		c.Regressions += e.Regressions()
+	}
+
+	return c
+}
+
+// compareStats compares the statistics of a single endpoint.
+func compareStats(url string, old, new *stats.Stats, tol Tolerances) *Endpoint {
+	e := &Endpoint{
+		URL:         url,
+		OldRequests: old.Requests,
+		NewRequests: new.Requests,
+	}
+
+	// the distribution test is shared by all percentiles
+	p := greaterPValue(old.TTFB, new.TTFB)
+
+	for _, q := range ttfbQuantiles {
+		c := Change{
+			Metric: q.metric,
+			Old:    milliseconds(old.TTFB.Quantile(q.quantile)),
+			New:    milliseconds(new.TTFB.Quantile(q.quantile)),
+			PValue: p,
+		}
+
+		if c.Old > 0 {
+			c.Delta = (c.New - c.Old) / c.Old
+		}
+		c.Regression = c.PValue < tol.Alpha && c.Delta > tol.TTFB
+
+		e.Changes = append(e.Changes, c)
+	}
+
+	c := Change{
+		Metric: MetricErrorRate,
+		Old:    old.ErrorRate(),
+		New:    new.ErrorRate(),
+		PValue: greaterRatePValue(old.Errors, old.Requests, new.Errors, new.Requests),
+	}
+	c.Delta = c.New - c.Old
+	c.Regression = c.PValue < tol.Alpha && c.Delta > tol.ErrorRate
+
+	e.Changes = append(e.Changes, c)
+
+	return e
+}
+
+// Load reads every result from r and returns a summary of them.
+func Load(r *resultfile.Reader) (*stats.Summary, error) {
+	s := stats.NewSummary()
+
+	for {
+		res, err := r.Next()
+		if err == io.EOF {
+			return s, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		s.Add(res)
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,125 @@
+package compare
+
+import (
+	"bytes"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHistogram(from, to time.Duration, n int) *stats.Histogram {
+	h := stats.NewHistogram()
+	step := (to - from) / time.Duration(n)
+	for i := 0; i < n; i++ {
+		h.Record(from + time.Duration(i)*step)
+	}
+
+	return h
+}
+
+func TestGreaterPValue(t *testing.T) {
+	base := newTestHistogram(100*time.Millisecond, 200*time.Millisecond, 500)
+
+	same := greaterPValue(base, newTestHistogram(100*time.Millisecond, 200*time.Millisecond, 500))
+	assert.InDelta(t, 0.5, same, 0.05)
+
+	slower := greaterPValue(base, newTestHistogram(130*time.Millisecond, 230*time.Millisecond, 500))
+	assert.Less(t, slower, 0.001)
+
+	faster := greaterPValue(base, newTestHistogram(70*time.Millisecond, 170*time.Millisecond, 500))
+	assert.Greater(t, faster, 0.999)
+
+	assert.Equal(t, 1.0, greaterPValue(base, stats.NewHistogram()))
+}
+
+func TestGreaterRatePValue(t *testing.T) {
+	assert.Less(t, greaterRatePValue(10, 1000, 50, 1000), 0.001)
+	assert.Greater(t, greaterRatePValue(50, 1000, 10, 1000), 0.999)
+	assert.Greater(t, greaterRatePValue(1, 100, 2, 100), 0.05)
+	assert.Equal(t, 1.0, greaterRatePValue(0, 1000, 0, 1000))
+	assert.Equal(t, 1.0, greaterRatePValue(0, 0, 1, 10))
+}
+
+func newTestSummary(t *testing.T, rawurl string, ttfb time.Duration, n, errors int) *stats.Summary {
+	u, err := url.Parse(rawurl)
+	require.NoError(t, err)
+
+	s := stats.NewSummary()
+	for i := 0; i < n; i++ {
+		code := 200
+		if i < errors {
+			code = 500
+		}
+
+		// spread values by +-10%
+		d := ttfb + ttfb*time.Duration(i%21-10)/100
+		s.Add(&client.Result{URL: u, HttpStatusCode: code, Ttfb: d})
+	}
+
+	return s
+}
+
+func TestCompare(t *testing.T) {
+	old := newTestSummary(t, "http://foo.bar/a", 100*time.Millisecond, 1000, 10)
+	new := newTestSummary(t, "http://foo.bar/a", 150*time.Millisecond, 1000, 10)
+	new.Endpoints["http://foo.bar/b"] = newTestSummary(t, "http://foo.bar/b", 100*time.Millisecond, 100, 0).Total
+
+	c := Compare(old, new, DefaultTolerances)
+
+	require.Len(t, c.Endpoints, 2)
+	a, b := c.Endpoints[0], c.Endpoints[1]
+
+	assert.Equal(t, "http://foo.bar/a", a.URL)
+	assert.Equal(t, 3, a.Regressions())
+	assert.InDelta(t, 0.5, a.Changes[0].Delta, 0.02)
+	assert.False(t, a.Changes[3].Regression, "error rate didn't change")
+
+	// endpoints only present in the new run are never regressions
+	assert.Equal(t, "http://foo.bar/b", b.URL)
+	assert.Equal(t, uint64(0), b.OldRequests)
+	assert.Equal(t, uint64(100), b.NewRequests)
+	assert.Equal(t, 0, b.Regressions())
+
+	assert.Equal(t, 3, c.Total.Regressions())
+	assert.Equal(t, 6, c.Regressions)
+}
+
+func TestCompare_Tolerances(t *testing.T) {
+	old := newTestSummary(t, "http://foo.bar", 100*time.Millisecond, 1000, 10)
+	new := newTestSummary(t, "http://foo.bar", 105*time.Millisecond, 1000, 40)
+
+	c := Compare(old, new, DefaultTolerances)
+	assert.Equal(t, []bool{false, false, false, true}, regressions(c.Total))
+
+	c = Compare(old, new, Tolerances{TTFB: 0.01, ErrorRate: 0.05, Alpha: 0.05})
+	assert.Equal(t, []bool{true, true, true, false}, regressions(c.Total))
+}
+
+func regressions(e *Endpoint) []bool {
+	r := make([]bool, len(e.Changes))
+	for i, c := range e.Changes {
+		r[i] = c.Regression
+	}
+
+	return r
+}
+
+func TestComparison_Print(t *testing.T) {
+	old := newTestSummary(t, "http://foo.bar", 100*time.Millisecond, 1000, 10)
+	new := newTestSummary(t, "http://foo.bar", 150*time.Millisecond, 1000, 10)
+
+	var buf bytes.Buffer
+	require.NoError(t, Compare(old, new, DefaultTolerances).Print(&buf))
+	out := buf.String()
+
+	assert.Contains(t, out, "URL             METRIC      OLD")
+	assert.Contains(t, out, "TOTAL           requests    1000")
+	assert.Contains(t, out, "REGRESSION")
+	assert.Contains(t, out, "<0.001")
+	assert.Contains(t, out, "6 regressions (TTFB tolerance 10.0%, error rate tolerance +1.0pp, significance level 0.05)")
+}
@@ -0,0 +1,68 @@
+package compare
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+)
+
+// totalName is the row name of the comparison over all results.
+const totalName = "TOTAL"
+
+// Print writes a human readable table of the comparison into w.
+func (c *Comparison) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "URL\tMETRIC\tOLD\tNEW\tCHANGE\tP-VALUE\tRESULT")
+	for _, e := range c.Endpoints {
+		printEndpoint(tw, e.URL, e)
+	}
+	printEndpoint(tw, totalName, c.Total)
+
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "%d regressions (TTFB tolerance %s, error rate tolerance %s, significance level %g)\n",
+		c.Regressions,
+		stats.FormatPercent(c.Tolerances.TTFB),
+		formatPoints(c.Tolerances.ErrorRate),
+		c.Tolerances.Alpha,
+	)
+
+	return tw.Flush()
+}
+
+func printEndpoint(w io.Writer, name string, e *Endpoint) {
+	fmt.Fprintf(w, "%s\trequests\t%d\t%d\t\t\t\n", name, e.OldRequests, e.NewRequests)
+
+	for _, c := range e.Changes {
+		var old, new, delta string
+
+		if c.Metric == MetricErrorRate {
+			old, new, delta = stats.FormatPercent(c.Old), stats.FormatPercent(c.New), formatPoints(c.Delta)
+		} else {
+			old, new = fmt.Sprintf("%.1fms", c.Old), fmt.Sprintf("%.1fms", c.New)
+			delta = fmt.Sprintf("%+.1f%%", c.Delta*100)
+		}
+
+		result := "ok"
+		if c.Regression {
+			result = "REGRESSION"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", name, c.Metric, old, new, delta, formatPValue(c.PValue), result)
+	}
+}
+
+// formatPoints formats an absolute change of a ratio in percentage points.
+func formatPoints(d float64) string {
+	return fmt.Sprintf("%+.1fpp", d*100)
+}
+
+func formatPValue(p float64) string {
+	if p < 0.001 {
+		return "<0.001"
+	}
+
+	return fmt.Sprintf("%.3f", p)
+}
@@ -0,0 +1,86 @@
+package compare
+
+import (
+	"math"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+)
+
+// greaterPValue returns the one-sided p-value of a Mann-Whitney U test
+// for the hypothesis, that values of b tend to be greater than values of a.
+//
+// The test is computed from the histogram buckets, so values in the same
+// bucket are treated as ties. It uses the normal approximation with tie
+// and continuity correction, which is accurate for the sample sizes of
+// a loadtest. It returns 1, if either histogram is empty.
+func greaterPValue(a, b *stats.Histogram) float64 {
+	n1, n2 := float64(a.Count()), float64(b.Count())
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	ba, bb := a.Buckets(), b.Buckets()
+
+	// rank sum of b, walking the buckets of both histograms in ascending order
+	var rank, rankSum, ties float64
+	for i, j := 0, 0; i < len(ba) || j < len(bb); {
+		var ca, cb uint64
+
+		switch {
+		case j == len(bb) || (i < len(ba) && ba[i].Value < bb[j].Value):
+			ca = ba[i].Count
+			i++
+		case i == len(ba) || bb[j].Value < ba[i].Value:
+			cb = bb[j].Count
+			j++
+		default:
+			ca, cb = ba[i].Count, bb[j].Count
+			i++
+			j++
+		}
+
+		t := float64(ca + cb)
+		rankSum += float64(cb) * (rank + (t+1)/2)
+		ties += t*t*t - t
+		rank += t
+	}
+
+	n := n1 + n2
+	u := rankSum - n2*(n2+1)/2
+	mean := n1 * n2 / 2
+	variance := n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1)))
+
+	if variance <= 0 {
+		// all values are tied
+		return 1
+	}
+
+	z := (u - mean - 0.5) / math.Sqrt(variance)
+	return upperTail(z)
+}
+
+// greaterRatePValue returns the one-sided p-value of a two-proportion z-test
+// for the hypothesis, that the ratio k2/n2 is greater than k1/n1.
+// It returns 1, if either sample is empty.
+func greaterRatePValue(k1, n1, k2, n2 uint64) float64 {
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	p1 := float64(k1) / float64(n1)
+	p2 := float64(k2) / float64(n2)
+	p := float64(k1+k2) / float64(n1+n2)
+
+	se := math.Sqrt(p * (1 - p) * (1/float64(n1) + 1/float64(n2)))
+	if se == 0 {
+		// both ratios are either 0 or 1
+		return 1
+	}
+
+	return upperTail((p2 - p1) / se)
+}
+
+// upperTail returns the probability of a standard normal variable being greater than z.
+func upperTail(z float64) float64 {
+	return math.Erfc(z/math.Sqrt2) / 2
+}
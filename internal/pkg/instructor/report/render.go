@@ -0,0 +1,316 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+)
+
+var funcs = template.FuncMap{
+	"duration": stats.FormatDuration,
+	"percent":  stats.FormatPercent,
+	"time": func(t time.Time) string {
+		return t.Format(time.RFC1123)
+	},
+	"codes": func(s *stats.Stats) string {
+		codes := make([]string, 0, len(s.StatusCodes))
+		for _, code := range s.Codes() {
+			codes = append(codes, fmt.Sprintf("%d: %d", code, s.StatusCodes[code]))
+		}
+		return strings.Join(codes, ", ")
+	},
+	"failures": func(s *stats.Stats) string {
+		classes := make([]string, 0, len(s.Failures))
+		for _, class := range s.FailureClasses() {
+			classes = append(classes, fmt.Sprintf("%s: %d", class, s.Failures[class]))
+		}
+		return strings.Join(classes, ", ")
+	},
+}
+
+var page = template.Must(template.New("report").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Loago report {{time .Header.Start}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1000px; color: #222; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.2em; margin-top: 2em; border-bottom: 1px solid #ddd; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9em; }
+th, td { padding: 4px 8px; text-align: right; border-bottom: 1px solid #eee; }
+th:first-child, td:first-child { text-align: left; word-break: break-all; }
+tr.total td { font-weight: bold; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 4px 16px; }
+dt { font-weight: bold; }
+dd { margin: 0; }
+.chart { width: 100%; height: auto; }
+.chart .grid { stroke: #eee; }
+.chart .axis { stroke: #888; }
+.chart text { font-size: 11px; fill: #555; }
+.chart .ylabel { text-anchor: end; }
+.chart .xlabel { text-anchor: middle; }
+</style>
+</head>
+<body>
+<h1>Loago report</h1>
+<dl>
+<dt>Start</dt><dd>{{time .Header.Start}}</dd>
+<dt>Stop</dt><dd>{{time .Stop}}{{if not .Footer}} (run didn't stop gracefully){{end}}</dd>
+<dt>Duration</dt><dd>{{.Duration}}</dd>
+<dt>Workers</dt><dd>{{range $i, $w := .Header.Workers}}{{if $i}}, {{end}}{{$w}}{{end}}</dd>
+{{- with .Header.Config}}
+<dt>Users per worker</dt><dd>{{.MaxUsers}}{{if .Stages}} (max. of {{len .Stages}} stages){{end}}</dd>
+<dt>Wait time</dt><dd>{{.MinWait}}ms - {{.MaxWait}}ms</dd>
+{{- end}}
+<dt>Requests</dt><dd>{{.Summary.Total.Requests}}</dd>
+<dt>Error rate</dt><dd>{{percent .Summary.Total.ErrorRate}}</dd>
+</dl>
+
+<h2>TTFB over time</h2>
+{{.TTFBChart}}
+
+<h2>TTFB distribution</h2>
+{{.HistogramChart}}
+
+<h2>Status codes over time</h2>
+{{.StatusChart}}
+
+<h2>Endpoints</h2>
+<table>
+<tr><th>URL</th><th>Requests</th><th>Errors</th><th>Cached</th><th>Min</th><th>Mean</th><th>Max</th><th>p50</th><th>p90</th><th>p95</th><th>p99</th></tr>
+{{- range .Endpoints}}
+{{template "stats" .}}
+{{- end}}
+</table>
+
+{{- if .Caches}}
+<h2>Cold and warm cache</h2>
+<table>
+<tr><th>Cache</th><th>Requests</th><th>Errors</th><th>Cached</th><th>Min</th><th>Mean</th><th>Max</th><th>p50</th><th>p90</th><th>p95</th><th>p99</th></tr>
+{{- range .Caches}}
+{{template "stats" .}}
+{{- end}}
+</table>
+{{- end}}
+
+{{- if .Emulations}}
+<h2>Emulations</h2>
+<table>
+<tr><th>Emulation</th><th>Requests</th><th>Errors</th><th>Cached</th><th>Min</th><th>Mean</th><th>Max</th><th>p50</th><th>p90</th><th>p95</th><th>p99</th></tr>
+{{- range .Emulations}}
+{{template "stats" .}}
+{{- end}}
+</table>
+{{- end}}
+
+{{- if .Steps}}
+<h2>Journeys</h2>
+<table>
+<tr><th>Journey / step</th><th>Runs</th><th>Errors</th><th>Min</th><th>Mean</th><th>Max</th><th>p50</th><th>p90</th><th>p95</th><th>p99</th></tr>
+{{- range .Steps}}
+<tr><td>{{.Name}}</td><td>{{.Stats.Requests}}</td><td>{{.Stats.Errors}}</td>{{with .Stats.Duration}}<td>{{duration .Min}}</td><td>{{duration .Mean}}</td><td>{{duration .Max}}</td><td>{{duration (.Quantile 0.5)}}</td><td>{{duration (.Quantile 0.9)}}</td><td>{{duration (.Quantile 0.95)}}</td><td>{{duration (.Quantile 0.99)}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Network phases</h2>
+<table>
+<tr><th>URL</th><th>DNS mean</th><th>DNS p95</th><th>Connect mean</th><th>Connect p95</th><th>SSL mean</th><th>SSL p95</th><th>Send mean</th><th>Send p95</th><th>Wait mean</th><th>Wait p95</th></tr>
+{{- range .Endpoints}}
+<tr{{if .Total}} class="total"{{end}}><td>{{.Name}}</td>{{with .Stats.Network}}{{template "phase" .DNS}}{{template "phase" .Connect}}{{template "phase" .SSL}}{{template "phase" .Send}}{{template "phase" .Wait}}{{end}}</tr>
+{{- end}}
+</table>
+
+{{- if .JSErrors}}
+<h2>JavaScript errors</h2>
+<table>
+<tr><th>URL</th><th>Requests</th><th>With JS errors</th><th>JS errors</th></tr>
+{{- range .JSErrors}}
+<tr{{if .Total}} class="total"{{end}}><td>{{.Name}}</td><td>{{.Stats.Requests}}</td><td>{{percent .Stats.JSErrorRate}}</td><td>{{.Stats.JSErrors}}</td></tr>
+{{- end}}
+</table>
+<table>
+<tr><th>Message</th><th>Requests</th></tr>
+{{- range .JSErrorMessages}}
+<tr><td>{{.Message}}</td><td>{{.Count}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- if .Subresources}}
+<h2>Slowest subresources</h2>
+<table>
+<tr><th>URL</th><th>Requests</th><th>Errors</th><th>Cached</th><th>Min</th><th>Mean</th><th>Max</th><th>p50</th><th>p90</th><th>p95</th><th>p99</th></tr>
+{{- range .Subresources}}
+{{template "stats" .}}
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Workers</h2>
+<table>
+<tr><th>Worker</th><th>Requests</th><th>Errors</th><th>Cached</th><th>Min</th><th>Mean</th><th>Max</th><th>p50</th><th>p90</th><th>p95</th><th>p99</th></tr>
+{{- range .WorkerRows}}
+{{template "stats" .}}
+{{- end}}
+</table>
+
+<h2>Status codes</h2>
+<table>
+<tr><th>URL</th><th>Status codes</th></tr>
+{{- range .Endpoints}}
+<tr{{if .Total}} class="total"{{end}}><td>{{.Name}}</td><td>{{codes .Stats}}</td></tr>
+{{- end}}
+</table>
+
+{{- if .Failures}}
+<h2>Failed requests</h2>
+<table>
+<tr><th>URL</th><th>Failures</th></tr>
+{{- range .Failures}}
+<tr{{if .Total}} class="total"{{end}}><td>{{.Name}}</td><td>{{failures .Stats}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+{{- if .Events}}
+<h2>Events</h2>
+<table>
+<tr><th>Event</th><th>Count</th><th>Users</th></tr>
+{{- range .Events}}
+<tr><td>{{.Name}}</td><td>{{.Count}}</td><td>{{.Users}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+{{define "phase"}}<td>{{duration .Mean}}</td><td>{{duration (.Quantile 0.95)}}</td>{{end}}
+{{define "stats"}}<tr{{if .Total}} class="total"{{end}}><td>{{.Name}}</td><td>{{.Stats.Requests}}</td><td>{{.Stats.Errors}}</td><td>{{percent .Stats.CachedRatio}}</td><td>{{duration .Stats.TTFB.Min}}</td><td>{{duration .Stats.TTFB.Mean}}</td><td>{{duration .Stats.TTFB.Max}}</td><td>{{duration (.Stats.TTFB.Quantile 0.5)}}</td><td>{{duration (.Stats.TTFB.Quantile 0.9)}}</td><td>{{duration (.Stats.TTFB.Quantile 0.95)}}</td><td>{{duration (.Stats.TTFB.Quantile 0.99)}}</td></tr>{{end}}
+`))
+
+// reportedSubresources is the amount of subresources shown in a report.
+const reportedSubresources = 50
+
+// reportedJSErrors is the amount of JavaScript error messages shown in a report.
+const reportedJSErrors = 50
+
+// row is a named table row of statistics.
+type row struct {
+	Name  string
+	Stats *stats.Stats
+	Total bool
+}
+
+// message is a JavaScript error message
+// with the amount of results it occurred in.
+type message struct {
+	Message string
+	Count   uint64
+}
+
+// event is a row of the events table.
+type event struct {
+	Name  string
+	Count uint64
+	Users uint64
+}
+
+// view contains everything rendered into the HTML page.
+type view struct {
+	*Report
+
+	Duration       time.Duration
+	TTFBChart      template.HTML
+	HistogramChart template.HTML
+	StatusChart    template.HTML
+	Endpoints      []row
+	Caches         []row
+	Emulations     []row
+	Steps          []row
+	Subresources   []row
+	WorkerRows     []row
+
+	JSErrors        []row
+	JSErrorMessages []message
+
+	// Failures contains the failed requests by error class, if any.
+	Failures []row
+
+	// Events contains the events of users, i.e. restarts of crashed browsers.
+	Events []event
+}
+
+// Render writes the report as a single HTML page into w.
+func (r *Report) Render(w io.Writer) error {
+	v := view{
+		Report:         r,
+		Duration:       r.Stop().Sub(r.Header.Start).Round(time.Second),
+		TTFBChart:      r.ttfbChart(),
+		HistogramChart: r.histogramChart(),
+		StatusChart:    r.statusChart(),
+	}
+
+	for _, url := range r.Summary.URLs() {
+		v.Endpoints = append(v.Endpoints, row{Name: url, Stats: r.Summary.Endpoints[url]})
+	}
+	v.Endpoints = append(v.Endpoints, row{Name: "Total", Stats: r.Summary.Total, Total: true})
+
+	// cold and warm visits are only distinguished, if there were cold visits
+	if r.Summary.Cold.Requests > 0 {
+		v.Caches = []row{
+			{Name: "cold", Stats: r.Summary.Cold},
+			{Name: "warm", Stats: r.Summary.Warm},
+		}
+	}
+
+	for _, name := range r.Summary.EmulationNames() {
+		v.Emulations = append(v.Emulations, row{Name: name, Stats: r.Summary.Emulations[name]})
+	}
+
+	for _, key := range r.Summary.StepKeys() {
+		v.Steps = append(v.Steps, row{Name: key, Stats: r.Summary.Steps[key]})
+	}
+
+	if r.Summary.Total.JSErrors > 0 {
+		for _, url := range r.Summary.URLs() {
+			v.JSErrors = append(v.JSErrors, row{Name: url, Stats: r.Summary.Endpoints[url]})
+		}
+		for _, key := range r.Summary.StepKeys() {
+			v.JSErrors = append(v.JSErrors, row{Name: key, Stats: r.Summary.Steps[key]})
+		}
+		v.JSErrors = append(v.JSErrors, row{Name: "Total", Stats: r.Summary.Total, Total: true})
+
+		for _, msg := range r.Summary.TopJSErrors(reportedJSErrors) {
+			v.JSErrorMessages = append(v.JSErrorMessages, message{Message: msg, Count: r.Summary.JSErrorMessages[msg]})
+		}
+	}
+
+	if len(r.Summary.Total.Failures) > 0 {
+		for _, url := range r.Summary.URLs() {
+			v.Failures = append(v.Failures, row{Name: url, Stats: r.Summary.Endpoints[url]})
+		}
+		for _, key := range r.Summary.StepKeys() {
+			v.Failures = append(v.Failures, row{Name: key, Stats: r.Summary.Steps[key]})
+		}
+		v.Failures = append(v.Failures, row{Name: "Total", Stats: r.Summary.Total, Total: true})
+	}
+
+	for _, name := range r.Summary.EventNames() {
+		v.Events = append(v.Events, event{Name: name, Count: r.Summary.Events[name], Users: r.Summary.EventUsers[name]})
+	}
+
+	for _, url := range r.Summary.SlowestSubresources(reportedSubresources) {
+		v.Subresources = append(v.Subresources, row{Name: url, Stats: r.Summary.Subresources[url]})
+	}
+
+	for _, name := range r.WorkerNames() {
+		v.WorkerRows = append(v.WorkerRows, row{Name: name, Stats: r.Workers[name]})
+	}
+
+	return page.Execute(w, v)
+}
@@ -0,0 +1,205 @@
+// Package report renders a self-contained HTML report of a finished run
+// from a result file. The report embeds every chart as inline SVG and
+// every style inline, so it doesn't depend on any external asset.
+package report
+
+import (
+	"html/template"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+	"github.com/dkorittki/loago/internal/pkg/instructor/resultfile"
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+)
+
+// histogramBins is the amount of bins of the latency histogram.
+const histogramBins = 40
+
+// Colors used in charts.
+var (
+	colorP50  = "#4e79a7"
+	colorP95  = "#f28e2b"
+	colorMax  = "#e15759"
+	colorBars = "#4e79a7"
+
+	statusClassColors = []string{"#7f7f7f", "#76b7b2", "#59a14f", "#edc948", "#f28e2b", "#e15759"}
+)
+
+// Report aggregates the results of a run for rendering.
+type Report struct {
+	// Header of the result file.
+	Header *resultfile.Header
+
+	// Footer of the result file. It is nil for runs which didn't stop gracefully.
+	Footer *resultfile.Footer
+
+	// Summary contains statistics per endpoint and over all results.
+	Summary *stats.Summary
+
+	// Workers contains statistics per worker.
+	Workers map[string]*stats.Stats
+
+	// Timeline contains statistics over time.
+	Timeline *Timeline
+
+	// last is the receive time of the latest result.
+	last time.Time
+}
+
+// New returns a new empty Report for the run described by h.
+func New(h *resultfile.Header) *Report {
+	return &Report{
+		Header:   h,
+		Summary:  stats.NewSummary(),
+		Workers:  make(map[string]*stats.Stats),
+		Timeline: NewTimeline(h.Start),
+	}
+}
+
+// Load reads every result from r and returns a Report of them.
+func Load(r *resultfile.Reader) (*Report, error) {
+	rep := New(r.Header())
+
+	for {
+		res, err := r.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		rep.Add(res)
+	}
+
+	rep.Footer = r.Footer()
+	return rep, nil
+}
+
+// Add adds r to the report.
+// Events of users are only counted by the summary, since they aren't requests.
+func (r *Report) Add(res *client.Result) {
+	r.Summary.Add(res)
+
+	if res.Time.After(r.last) {
+		r.last = res.Time
+	}
+
+	if res.Event != "" {
+		return
+	}
+
+	r.Timeline.Add(res)
+
+	w, ok := r.Workers[res.Worker]
+	if !ok {
+		w = stats.NewStats()
+		r.Workers[res.Worker] = w
+	}
+	w.Add(res)
+}
+
+// Stop returns the stop time of the run, or the receive time of the
+// latest result, if the run didn't stop gracefully.
+func (r *Report) Stop() time.Time {
+	if r.Footer != nil {
+		return r.Footer.Stop
+	}
+
+	return r.last
+}
+
+// WorkerNames returns the names of all workers in ascending order.
+func (r *Report) WorkerNames() []string {
+	names := make([]string, 0, len(r.Workers))
+	for name := range r.Workers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// timelineLabel returns a function labeling timeline slots by their offset to the start.
+func (r *Report) timelineLabel() func(i int) string {
+	return func(i int) string {
+		return (time.Duration(i) * r.Timeline.Width).String()
+	}
+}
+
+// ttfbChart renders p50, p95 and max TTFB over time.
+func (r *Report) ttfbChart() template.HTML {
+	n := r.Timeline.Len()
+	p50 := series{name: "p50", color: colorP50, values: make([]float64, n)}
+	p95 := series{name: "p95", color: colorP95, values: make([]float64, n)}
+	max := series{name: "max", color: colorMax, values: make([]float64, n)}
+
+	for i, v := range r.Timeline.intervals {
+		if v.ttfb.Count() == 0 {
+			p50.values[i], p95.values[i], max.values[i] = math.NaN(), math.NaN(), math.NaN()
+			continue
+		}
+
+		p50.values[i] = milliseconds(v.ttfb.Quantile(0.5))
+		p95.values[i] = milliseconds(v.ttfb.Quantile(0.95))
+		max.values[i] = milliseconds(v.ttfb.Max())
+	}
+
+	return lineChart(n, "ms", r.timelineLabel(), p50, p95, max)
+}
+
+// statusChart renders the amount of results per status class over time.
+func (r *Report) statusChart() template.HTML {
+	n := r.Timeline.Len()
+	s := make([]series, len(statusClasses))
+
+	for c := range statusClasses {
+		s[c] = series{name: statusClasses[c], color: statusClassColors[c], values: make([]float64, n)}
+		for i, v := range r.Timeline.intervals {
+			s[c].values[i] = float64(v.classes[c])
+		}
+	}
+
+	return stackedBarChart(n, "", r.timelineLabel(), s...)
+}
+
+// histogramChart renders the distribution of TTFB values over all results.
+// Values above the 99th percentile are counted in the last bin.
+func (r *Report) histogramChart() template.HTML {
+	h := r.Summary.Total.TTFB
+	upper := milliseconds(h.Quantile(0.99))
+	if upper <= 0 {
+		upper = 1
+	}
+
+	width := upper / histogramBins
+	bins := series{name: "requests", color: colorBars, values: make([]float64, histogramBins+1)}
+
+	for _, b := range h.Buckets() {
+		v := milliseconds(b.Value)
+		i := int(v / width)
+
+		if v > upper {
+			i = histogramBins
+		} else if i >= histogramBins {
+			i = histogramBins - 1
+		}
+
+		bins.values[i] += float64(b.Count)
+	}
+
+	label := func(i int) string {
+		if i == histogramBins {
+			return ">" + formatNumber(upper) + "ms"
+		}
+		return formatNumber(float64(i)*width) + "ms"
+	}
+
+	return barChart("", label, bins)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,223 @@
+package report
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+	"github.com/dkorittki/loago/internal/pkg/instructor/resultfile"
+	"github.com/dkorittki/loago/pkg/instructor/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testStart = time.Date(2020, 11, 30, 12, 0, 0, 0, time.UTC)
+
+func newTestResult(t *testing.T, offset time.Duration, worker string, code int, ttfb time.Duration) *client.Result {
+	u, err := url.Parse("http://foo.bar/<script>")
+	require.NoError(t, err)
+
+	return &client.Result{
+		Time:           testStart.Add(offset),
+		Worker:         worker,
+		URL:            u,
+		HttpStatusCode: code,
+		Ttfb:           ttfb,
+	}
+}
+
+func TestTimeline_Compact(t *testing.T) {
+	tl := NewTimeline(testStart)
+
+	for i := 0; i < maxIntervals*3; i++ {
+		tl.Add(newTestResult(t, time.Duration(i)*time.Second, "w", 200, time.Millisecond))
+	}
+
+	assert.Equal(t, 4*time.Second, tl.Width)
+	assert.LessOrEqual(t, tl.Len(), maxIntervals)
+
+	var count uint64
+	for _, i := range tl.intervals {
+		count += i.ttfb.Count()
+	}
+	assert.Equal(t, uint64(maxIntervals*3), count)
+}
+
+func TestTimeline_StatusClasses(t *testing.T) {
+	tl := NewTimeline(testStart)
+	tl.Add(newTestResult(t, 0, "w", 200, time.Millisecond))
+	tl.Add(newTestResult(t, 0, "w", 503, time.Millisecond))
+	tl.Add(newTestResult(t, 0, "w", 0, 0))
+	tl.Add(newTestResult(t, -time.Second, "w", 404, time.Millisecond))
+
+	require.Equal(t, 1, tl.Len())
+	assert.Equal(t, []uint64{1, 0, 1, 0, 1, 1}, tl.intervals[0].classes)
+	assert.Equal(t, uint64(3), tl.intervals[0].ttfb.Count())
+}
+
+func TestReport_Render(t *testing.T) {
+	h := &resultfile.Header{
+		Start:   testStart,
+		Workers: []string{"worker-a:50051", "worker-b:50051"},
+		Config:  &config.InstructorConfig{Amount: 5, MinWait: 1000, MaxWait: 2000},
+	}
+
+	r := New(h)
+	r.Add(newTestResult(t, time.Second, "worker-a:50051", 200, 10*time.Millisecond))
+	r.Add(newTestResult(t, 2*time.Second, "worker-b:50051", 500, 90*time.Millisecond))
+	r.Footer = &resultfile.Footer{Stop: testStart.Add(time.Minute), Results: 2}
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(&buf))
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.Equal(t, 3, strings.Count(out, "<svg"))
+	assert.Contains(t, out, "worker-a:50051")
+	assert.Contains(t, out, "worker-b:50051")
+	assert.Contains(t, out, "200: 1, 500: 1")
+	assert.Contains(t, out, "Network phases")
+	assert.Contains(t, out, "1m0s")
+	assert.NotContains(t, out, "<script>")
+	assert.NotContains(t, out, "didn't stop gracefully")
+	assert.NotContains(t, out, "Slowest subresources")
+
+	// no external assets
+	assert.NotContains(t, out, "src=")
+	assert.NotContains(t, out, "<link")
+}
+
+func TestReport_RenderWithoutFooter(t *testing.T) {
+	r := New(&resultfile.Header{Start: testStart})
+	r.Add(newTestResult(t, 5*time.Second, "w", 200, 10*time.Millisecond))
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(&buf))
+
+	assert.Equal(t, testStart.Add(5*time.Second), r.Stop())
+	assert.Contains(t, buf.String(), "didn't stop gracefully")
+}
+
+func TestReport_RenderEmpty(t *testing.T) {
+	r := New(&resultfile.Header{Start: testStart})
+
+	var buf bytes.Buffer
+	assert.NoError(t, r.Render(&buf))
+}
+
+func TestReport_RenderSubresources(t *testing.T) {
+	r := New(&resultfile.Header{Start: testStart})
+
+	res := newTestResult(t, time.Second, "w", 200, 10*time.Millisecond)
+	res.Subresources = []*client.Subresource{{URL: "http://foo.bar/api.json", HttpStatusCode: 200, Ttfb: 200 * time.Millisecond}}
+	r.Add(res)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(&buf))
+
+	assert.Contains(t, buf.String(), "Slowest subresources")
+	assert.Contains(t, buf.String(), "http://foo.bar/api.json")
+}
+
+func TestReport_RenderCaches(t *testing.T) {
+	r := New(&resultfile.Header{Start: testStart})
+	r.Add(newTestResult(t, time.Second, "w", 0, 0))
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(&buf))
+	assert.NotContains(t, buf.String(), "Cold and warm cache")
+
+	res := newTestResult(t, 2*time.Second, "w", 0, 0)
+	res.ColdCache = true
+	r.Add(res)
+
+	buf.Reset()
+	require.NoError(t, r.Render(&buf))
+	assert.Contains(t, buf.String(), "<h2>Cold and warm cache</h2>")
+}
+
+func TestReport_RenderEmulations(t *testing.T) {
+	r := New(&resultfile.Header{Start: testStart})
+
+	res := newTestResult(t, time.Second, "w", 0, 0)
+	res.Emulation = "moto g4 on slow 3g"
+	r.Add(res)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(&buf))
+
+	assert.Contains(t, buf.String(), "<h2>Emulations</h2>")
+	assert.Contains(t, buf.String(), "moto g4 on slow 3g")
+}
+
+func TestReport_RenderJSErrors(t *testing.T) {
+	r := New(&resultfile.Header{Start: testStart})
+
+	res := newTestResult(t, time.Second, "w", 200, 10*time.Millisecond)
+	res.JSErrors = 2
+	res.JSErrorMessages = []string{"Uncaught TypeError: <cart> is undefined"}
+	r.Add(res)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(&buf))
+
+	assert.Contains(t, buf.String(), "<h2>JavaScript errors</h2>")
+	assert.Contains(t, buf.String(), "Uncaught TypeError: &lt;cart&gt; is undefined")
+}
+
+func TestReport_RenderFailures(t *testing.T) {
+	r := New(&resultfile.Header{Start: testStart})
+
+	res := newTestResult(t, time.Second, "w", 0, 0)
+	res.Error = "can't load http://foo.bar: net::ERR_CONNECTION_REFUSED"
+	res.ErrorClass = "connect"
+	r.Add(res)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(&buf))
+
+	assert.Contains(t, buf.String(), "<h2>Failed requests</h2>")
+	assert.Contains(t, buf.String(), "connect: 1")
+}
+
+func TestReport_RenderEvents(t *testing.T) {
+	r := New(&resultfile.Header{Start: testStart})
+
+	r.Add(&client.Result{
+		Time:   testStart.Add(2 * time.Second),
+		Worker: "w",
+		Event:  "restart",
+		Error:  "browser closed unexpectedly",
+	})
+	r.Add(newTestResult(t, time.Second, "w", 200, 100*time.Millisecond))
+
+	// events aren't requests of a worker
+	assert.Equal(t, uint64(1), r.Workers["w"].Requests)
+	assert.Equal(t, testStart.Add(2*time.Second), r.Stop())
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(&buf))
+
+	assert.Contains(t, buf.String(), "<h2>Events</h2>")
+	assert.Contains(t, buf.String(), "<td>restart</td><td>1</td><td>1</td>")
+}
+
+func TestReport_RenderJourneys(t *testing.T) {
+	r := New(&resultfile.Header{Start: testStart})
+
+	res := newTestResult(t, time.Second, "w", 0, 0)
+	res.Journey = "checkout"
+	res.Step = "2 click"
+	res.Duration = 120 * time.Millisecond
+	r.Add(res)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Render(&buf))
+
+	assert.Contains(t, buf.String(), "<h2>Journeys</h2>")
+	assert.Contains(t, buf.String(), "checkout / 2 click")
+	assert.Contains(t, buf.String(), "120.0ms")
+}
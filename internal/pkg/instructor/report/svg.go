@@ -0,0 +1,198 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+)
+
+// Dimensions of rendered charts in pixels.
+const (
+	chartWidth   = 960
+	chartHeight  = 320
+	marginLeft   = 70
+	marginRight  = 20
+	marginTop    = 20
+	marginBottom = 40
+
+	plotWidth  = chartWidth - marginLeft - marginRight
+	plotHeight = chartHeight - marginTop - marginBottom
+
+	yTicks = 5
+	xTicks = 6
+)
+
+// series is a named sequence of values plotted in a chart.
+// NaN values are gaps.
+type series struct {
+	name   string
+	color  string
+	values []float64
+}
+
+// niceCeil rounds v up to 1, 2 or 5 times a power of ten.
+func niceCeil(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+
+	exp := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if v <= m*exp {
+			return m * exp
+		}
+	}
+
+	return 10 * exp
+}
+
+// chart contains the shared scaffolding of every chart.
+type chart struct {
+	b    strings.Builder
+	n    int
+	yMax float64
+}
+
+func newChart(n int, yMax float64, yUnit string, xLabel func(i int) string) *chart {
+	c := &chart{n: n, yMax: niceCeil(yMax)}
+
+	fmt.Fprintf(&c.b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" class="chart">`,
+		chartWidth, chartHeight)
+
+	for i := 0; i <= yTicks; i++ {
+		v := c.yMax * float64(i) / yTicks
+		y := c.y(v)
+		fmt.Fprintf(&c.b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" class="grid"/>`,
+			marginLeft, y, chartWidth-marginRight, y)
+		fmt.Fprintf(&c.b, `<text x="%d" y="%.1f" class="ylabel">%s%s</text>`,
+			marginLeft-6, y+4, formatNumber(v), template.HTMLEscapeString(yUnit))
+	}
+
+	if n > 0 && xLabel != nil {
+		step := int(math.Ceil(float64(n) / xTicks))
+		for i := 0; i < n; i += step {
+			fmt.Fprintf(&c.b, `<text x="%.1f" y="%d" class="xlabel">%s</text>`,
+				c.x(i), chartHeight-marginBottom+18, template.HTMLEscapeString(xLabel(i)))
+		}
+	}
+
+	fmt.Fprintf(&c.b, `<line x1="%d" y1="%d" x2="%d" y2="%d" class="axis"/>`,
+		marginLeft, chartHeight-marginBottom, chartWidth-marginRight, chartHeight-marginBottom)
+
+	return c
+}
+
+// x returns the horizontal center of slot i.
+func (c *chart) x(i int) float64 {
+	return marginLeft + (float64(i)+0.5)*c.slotWidth()
+}
+
+// slotWidth returns the horizontal space of a single slot.
+func (c *chart) slotWidth() float64 {
+	if c.n == 0 {
+		return plotWidth
+	}
+
+	return float64(plotWidth) / float64(c.n)
+}
+
+// y returns the vertical position of value v.
+func (c *chart) y(v float64) float64 {
+	return marginTop + plotHeight - v/c.yMax*plotHeight
+}
+
+func (c *chart) legend(s []series) {
+	for i, v := range s {
+		x := marginLeft + 10 + i*140
+		fmt.Fprintf(&c.b, `<rect x="%d" y="%d" width="10" height="10" fill="%s"/>`, x, marginTop-14, v.color)
+		fmt.Fprintf(&c.b, `<text x="%d" y="%d" class="legend">%s</text>`,
+			x+14, marginTop-5, template.HTMLEscapeString(v.name))
+	}
+}
+
+func (c *chart) html() template.HTML {
+	c.b.WriteString(`</svg>`)
+
+	// The chart is built from numbers and escaped labels only.
+	return template.HTML(c.b.String())
+}
+
+// lineChart renders every series as a line over n slots.
+func lineChart(n int, yUnit string, xLabel func(i int) string, s ...series) template.HTML {
+	var yMax float64
+	for _, v := range s {
+		for _, f := range v.values {
+			if !math.IsNaN(f) && f > yMax {
+				yMax = f
+			}
+		}
+	}
+
+	c := newChart(n, yMax, yUnit, xLabel)
+
+	for _, v := range s {
+		var points []string
+		flush := func() {
+			if len(points) > 0 {
+				fmt.Fprintf(&c.b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`,
+					strings.Join(points, " "), v.color)
+			}
+			points = points[:0]
+		}
+
+		for i, f := range v.values {
+			if math.IsNaN(f) {
+				flush()
+				continue
+			}
+			points = append(points, fmt.Sprintf("%.1f,%.1f", c.x(i), c.y(f)))
+		}
+		flush()
+	}
+
+	c.legend(s)
+	return c.html()
+}
+
+// stackedBarChart renders every series as a stacked bar per slot.
+func stackedBarChart(n int, yUnit string, xLabel func(i int) string, s ...series) template.HTML {
+	var yMax float64
+	for i := 0; i < n; i++ {
+		var sum float64
+		for _, v := range s {
+			sum += v.values[i]
+		}
+		yMax = math.Max(yMax, sum)
+	}
+
+	c := newChart(n, yMax, yUnit, xLabel)
+	w := math.Max(c.slotWidth()*0.9, 1)
+
+	for i := 0; i < n; i++ {
+		var base float64
+		for _, v := range s {
+			if v.values[i] <= 0 {
+				continue
+			}
+
+			top := base + v.values[i]
+			fmt.Fprintf(&c.b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"/>`,
+				c.x(i)-w/2, c.y(top), w, c.y(base)-c.y(top), v.color)
+			base = top
+		}
+	}
+
+	c.legend(s)
+	return c.html()
+}
+
+// barChart renders a single series as bars, i.e. a histogram.
+func barChart(yUnit string, xLabel func(i int) string, s series) template.HTML {
+	return stackedBarChart(len(s.values), yUnit, xLabel, s)
+}
+
+// formatNumber formats v without insignificant decimal places.
+func formatNumber(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
@@ -0,0 +1,120 @@
+package report
+
+import (
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+)
+
+const (
+	// maxIntervals is the maximum amount of intervals of a timeline.
+	// Once exceeded, adjacent intervals are merged.
+	maxIntervals = 200
+
+	// initialIntervalWidth is the width of a timeline interval
+	// before any intervals are merged.
+	initialIntervalWidth = time.Second
+)
+
+// statusClasses contains the names of the status classes counted per interval.
+// A status class is the first digit of a HTTP status code.
+// Results without response are counted in class 0.
+var statusClasses = []string{"no response", "1xx", "2xx", "3xx", "4xx", "5xx"}
+
+// interval contains the statistics of all results received in a period of time.
+type interval struct {
+	ttfb    *stats.Histogram
+	classes []uint64
+}
+
+func newInterval() *interval {
+	return &interval{
+		ttfb:    stats.NewHistogram(),
+		classes: make([]uint64, len(statusClasses)),
+	}
+}
+
+func (i *interval) merge(o *interval) {
+	i.ttfb.Merge(o.ttfb)
+	for c := range o.classes {
+		i.classes[c] += o.classes[c]
+	}
+}
+
+// statusClass returns the index of the status class of code.
+func statusClass(code int) int {
+	c := code / 100
+	if c < 1 || c >= len(statusClasses) {
+		return 0
+	}
+
+	return c
+}
+
+// Timeline aggregates results in consecutive intervals of equal width,
+// starting at the start of a run. The width of the intervals doubles
+// whenever the timeline grows beyond maxIntervals, so memory usage
+// is bounded regardless of the duration of the run.
+type Timeline struct {
+	// Start is the start time of the first interval.
+	Start time.Time
+
+	// Width is the duration of a single interval.
+	Width time.Duration
+
+	intervals []*interval
+}
+
+// NewTimeline returns a new empty Timeline starting at start.
+func NewTimeline(start time.Time) *Timeline {
+	return &Timeline{
+		Start: start,
+		Width: initialIntervalWidth,
+	}
+}
+
+// Add adds r to the interval matching its receive time.
+func (t *Timeline) Add(r *client.Result) {
+	offset := r.Time.Sub(t.Start)
+	if offset < 0 {
+		offset = 0
+	}
+
+	idx := int(offset / t.Width)
+	for idx >= maxIntervals {
+		t.compact()
+		idx = int(offset / t.Width)
+	}
+
+	for len(t.intervals) <= idx {
+		t.intervals = append(t.intervals, newInterval())
+	}
+
+	i := t.intervals[idx]
+	i.classes[statusClass(r.HttpStatusCode)]++
+	if !r.Cached && r.HttpStatusCode != 0 {
+		i.ttfb.Record(r.Ttfb)
+	}
+}
+
+// Len returns the amount of intervals.
+func (t *Timeline) Len() int {
+	return len(t.intervals)
+}
+
+// compact doubles the interval width by merging adjacent intervals.
+func (t *Timeline) compact() {
+	merged := make([]*interval, 0, (len(t.intervals)+1)/2)
+
+	for i := 0; i < len(t.intervals); i += 2 {
+		m := t.intervals[i]
+		if i+1 < len(t.intervals) {
+			m.merge(t.intervals[i+1])
+		}
+		merged = append(merged, m)
+	}
+
+	t.intervals = merged
+	t.Width *= 2
+}
@@ -0,0 +1,332 @@
+package resultfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+)
+
+// Type values of JSON lines records.
+const (
+	recordTypeHeader = "header"
+	recordTypeResult = "result"
+	recordTypeFooter = "footer"
+)
+
+// csvColumns contains the column names of CSV encoded results.
+var csvColumns = []string{
+	"time",
+	"worker",
+	"user",
+	"url",
+	"status_code",
+	"status_message",
+	"ttfb_ms",
+	"cached",
+	"dom_content_loaded_ms",
+	"load_ms",
+	"first_paint_ms",
+	"first_contentful_paint_ms",
+	"largest_contentful_paint_ms",
+	"cumulative_layout_shift",
+	"dns_ms",
+	"connect_ms",
+	"ssl_ms",
+	"send_ms",
+	"wait_ms",
+	"journey",
+	"step",
+	"duration_ms",
+	"error",
+	"error_class",
+	"event",
+	"emulation",
+	"cold_cache",
+	"artifact",
+	"har",
+	"js_errors",
+	"js_error_messages",
+	"subresources",
+}
+
+// encoder encodes header, results and footer of a result file.
+type encoder interface {
+	header(h *Header) error
+	result(r *client.Result) error
+	footer(f *Footer) error
+}
+
+// result is the serialized form of a client result.
+type result struct {
+	Time              time.Time `json:"time"`
+	Worker            string    `json:"worker"`
+	User              int       `json:"user"`
+	URL               string    `json:"url"`
+	HTTPStatusCode    int       `json:"status_code"`
+	HTTPStatusMessage string    `json:"status_message"`
+	TTFB              float64   `json:"ttfb_ms"`
+	Cached            bool      `json:"cached"`
+
+	DOMContentLoaded       float64 `json:"dom_content_loaded_ms"`
+	Load                   float64 `json:"load_ms"`
+	FirstPaint             float64 `json:"first_paint_ms"`
+	FirstContentfulPaint   float64 `json:"first_contentful_paint_ms"`
+	LargestContentfulPaint float64 `json:"largest_contentful_paint_ms"`
+	CumulativeLayoutShift  float64 `json:"cumulative_layout_shift"`
+
+	DNS     float64 `json:"dns_ms"`
+	Connect float64 `json:"connect_ms"`
+	SSL     float64 `json:"ssl_ms"`
+	Send    float64 `json:"send_ms"`
+	Wait    float64 `json:"wait_ms"`
+
+	Journey  string  `json:"journey,omitempty"`
+	Step     string  `json:"step,omitempty"`
+	Duration float64 `json:"duration_ms,omitempty"`
+	Error    string  `json:"error,omitempty"`
+
+	// ErrorClass classifies why the request failed, if it did.
+	ErrorClass string `json:"error_class,omitempty"`
+
+	// Event reports something that happened to a user instead of a request, if it did.
+	Event string `json:"event,omitempty"`
+
+	Emulation string `json:"emulation,omitempty"`
+	ColdCache bool   `json:"cold_cache,omitempty"`
+
+	// Artifact is the name of the stored artifact of the result, if any.
+	Artifact string `json:"artifact,omitempty"`
+
+	// HAR is the name of the stored HAR of the result, if any.
+	HAR string `json:"har,omitempty"`
+
+	JSErrors        int      `json:"js_errors,omitempty"`
+	JSErrorMessages []string `json:"js_error_messages,omitempty"`
+
+	Subresources []*subresource `json:"subresources,omitempty"`
+}
+
+// subresource is the serialized form of a subresource of a client result.
+type subresource struct {
+	URL               string  `json:"url"`
+	Type              string  `json:"type"`
+	HTTPStatusCode    int     `json:"status_code"`
+	HTTPStatusMessage string  `json:"status_message"`
+	TTFB              float64 `json:"ttfb_ms"`
+	Size              int64   `json:"size"`
+	Cached            bool    `json:"cached"`
+	Initiator         string  `json:"initiator,omitempty"`
+	InitiatorURL      string  `json:"initiator_url,omitempty"`
+	Error             string  `json:"error,omitempty"`
+}
+
+func toResult(r *client.Result) *result {
+	res := &result{
+		Time:              r.Time,
+		Worker:            r.Worker,
+		User:              r.User,
+		HTTPStatusCode:    r.HttpStatusCode,
+		HTTPStatusMessage: r.HttpStatusMessage,
+		TTFB:              toMilliseconds(r.Ttfb),
+		Cached:            r.Cached,
+
+		DOMContentLoaded:       toMilliseconds(r.Timing.DOMContentLoaded),
+		Load:                   toMilliseconds(r.Timing.Load),
+		FirstPaint:             toMilliseconds(r.Timing.FirstPaint),
+		FirstContentfulPaint:   toMilliseconds(r.Timing.FirstContentfulPaint),
+		LargestContentfulPaint: toMilliseconds(r.Timing.LargestContentfulPaint),
+		CumulativeLayoutShift:  r.Timing.CumulativeLayoutShift,
+
+		DNS:     toMilliseconds(r.Network.DNS),
+		Connect: toMilliseconds(r.Network.Connect),
+		SSL:     toMilliseconds(r.Network.SSL),
+		Send:    toMilliseconds(r.Network.Send),
+		Wait:    toMilliseconds(r.Network.Wait),
+
+		Journey:  r.Journey,
+		Step:     r.Step,
+		Duration: toMilliseconds(r.Duration),
+		Error:    r.Error,
+
+		ErrorClass: r.ErrorClass,
+		Event:      r.Event,
+		Emulation:  r.Emulation,
+		ColdCache:  r.ColdCache,
+
+		JSErrors:        r.JSErrors,
+		JSErrorMessages: r.JSErrorMessages,
+	}
+
+	if r.URL != nil {
+		res.URL = r.URL.String()
+	}
+
+	if r.Artifact != nil {
+		res.Artifact = r.Artifact.Name
+	}
+
+	if r.HAR != nil {
+		res.HAR = r.HAR.Name
+	}
+
+	for _, s := range r.Subresources {
+		res.Subresources = append(res.Subresources, &subresource{
+			URL:               s.URL,
+			Type:              s.Type,
+			HTTPStatusCode:    s.HttpStatusCode,
+			HTTPStatusMessage: s.HttpStatusMessage,
+			TTFB:              toMilliseconds(s.Ttfb),
+			Size:              s.Size,
+			Cached:            s.Cached,
+			Initiator:         s.Initiator,
+			InitiatorURL:      s.InitiatorURL,
+			Error:             s.Error,
+		})
+	}
+
+	return res
+}
+
+func toMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// jsonLinesEncoder writes every record as a JSON object in a separate line.
+type jsonLinesEncoder struct {
+	enc *json.Encoder
+}
+
+func newJSONLinesEncoder(w io.Writer) *jsonLinesEncoder {
+	return &jsonLinesEncoder{enc: json.NewEncoder(w)}
+}
+
+func (e *jsonLinesEncoder) header(h *Header) error {
+	return e.enc.Encode(struct {
+		Type string `json:"type"`
+		*Header
+	}{recordTypeHeader, h})
+}
+
+func (e *jsonLinesEncoder) result(r *client.Result) error {
+	return e.enc.Encode(struct {
+		Type string `json:"type"`
+		*result
+	}{recordTypeResult, toResult(r)})
+}
+
+func (e *jsonLinesEncoder) footer(f *Footer) error {
+	return e.enc.Encode(struct {
+		Type string `json:"type"`
+		*Footer
+	}{recordTypeFooter, f})
+}
+
+// csvEncoder writes results as CSV records.
+// Header and footer are written as JSON encoded comment lines.
+type csvEncoder struct {
+	w   io.Writer
+	csv *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) *csvEncoder {
+	return &csvEncoder{w: w, csv: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) comment(recordType string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(e.w, "# "+recordType+" "+string(b)+"\n")
+	return err
+}
+
+func (e *csvEncoder) header(h *Header) error {
+	if err := e.comment(recordTypeHeader, h); err != nil {
+		return err
+	}
+
+	return e.write(csvColumns)
+}
+
+func (e *csvEncoder) result(r *client.Result) error {
+	res := toResult(r)
+
+	// subresources don't fit into columns, so they are stored as JSON array
+	var subresources string
+	if len(res.Subresources) > 0 {
+		b, err := json.Marshal(res.Subresources)
+		if err != nil {
+			return err
+		}
+		subresources = string(b)
+	}
+
+	var jsErrorMessages string
+	if len(res.JSErrorMessages) > 0 {
+		b, err := json.Marshal(res.JSErrorMessages)
+		if err != nil {
+			return err
+		}
+		jsErrorMessages = string(b)
+	}
+
+	return e.write([]string{
+		res.Time.Format(time.RFC3339Nano),
+		res.Worker,
+		strconv.Itoa(res.User),
+		res.URL,
+		strconv.Itoa(res.HTTPStatusCode),
+		res.HTTPStatusMessage,
+		formatFloat(res.TTFB),
+		strconv.FormatBool(res.Cached),
+		formatFloat(res.DOMContentLoaded),
+		formatFloat(res.Load),
+		formatFloat(res.FirstPaint),
+		formatFloat(res.FirstContentfulPaint),
+		formatFloat(res.LargestContentfulPaint),
+		formatFloat(res.CumulativeLayoutShift),
+		formatFloat(res.DNS),
+		formatFloat(res.Connect),
+		formatFloat(res.SSL),
+		formatFloat(res.Send),
+		formatFloat(res.Wait),
+		res.Journey,
+		res.Step,
+		formatFloat(res.Duration),
+		res.Error,
+		res.ErrorClass,
+		res.Event,
+		res.Emulation,
+		strconv.FormatBool(res.ColdCache),
+		res.Artifact,
+		res.HAR,
+		strconv.Itoa(res.JSErrors),
+		jsErrorMessages,
+		subresources,
+	})
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func (e *csvEncoder) footer(f *Footer) error {
+	return e.comment(recordTypeFooter, f)
+}
+
+// write writes a single CSV record and flushes it into the
+// underlying writer, so records and comments stay in order.
+func (e *csvEncoder) write(record []string) error {
+	if err := e.csv.Write(record); err != nil {
+		return err
+	}
+
+	e.csv.Flush()
+	return e.csv.Error()
+}
@@ -0,0 +1,442 @@
+package resultfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+)
+
+// ErrMissingHeader indicates an error when a result file doesn't start with a header.
+var ErrMissingHeader = errors.New("result file has no header")
+
+// Reader reads results from a result file.
+// The format of the file is detected automatically.
+type Reader struct {
+	r      *bufio.Reader
+	closer io.Closer
+	format Format
+	line   int
+
+	header  *Header
+	footer  *Footer
+	columns map[string]int
+}
+
+// Open opens the result file at path for reading.
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	r.closer = f
+
+	return r, nil
+}
+
+// NewReader returns a Reader reading results from r.
+// It reads the header immediately and returns an error,
+// if it is missing or malformed.
+func NewReader(r io.Reader) (*Reader, error) {
+	rd := &Reader{r: bufio.NewReader(r)}
+
+	line, err := rd.readLine()
+	if err == io.EOF {
+		return nil, ErrMissingHeader
+	} else if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bytes.HasPrefix(line, []byte("{")):
+		rd.format = FormatJSONLines
+		err = rd.parseJSONHeader(line)
+	case bytes.HasPrefix(line, []byte("#")):
+		rd.format = FormatCSV
+		err = rd.parseCSVHeader(line)
+	default:
+		err = ErrMissingHeader
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rd, nil
+}
+
+// Header returns the header of the result file.
+func (r *Reader) Header() *Header {
+	return r.header
+}
+
+// Footer returns the footer of the result file.
+// It is nil, until Next returned io.EOF, and stays nil
+// for files of runs which didn't stop gracefully.
+func (r *Reader) Footer() *Footer {
+	return r.footer
+}
+
+// Format returns the detected format of the result file.
+func (r *Reader) Format() Format {
+	return r.format
+}
+
+// Next returns the next result in the file.
+// It returns io.EOF once every result has been read.
+func (r *Reader) Next() (*client.Result, error) {
+	for {
+		line, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var res *client.Result
+		if r.format == FormatJSONLines {
+			res, err = r.parseJSONLine(line)
+		} else {
+			res, err = r.parseCSVLine(line)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+
+		if res != nil {
+			return res, nil
+		}
+	}
+}
+
+// Close closes the underlying file, if the Reader was created by Open.
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+
+	return r.closer.Close()
+}
+
+func (r *Reader) readLine() ([]byte, error) {
+	line, err := r.r.ReadBytes('\n')
+	if err == io.EOF && len(line) > 0 {
+		err = nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.line++
+	return bytes.TrimRight(line, "\r\n"), nil
+}
+
+func (r *Reader) parseJSONHeader(line []byte) error {
+	var h struct {
+		Type string `json:"type"`
+		Header
+	}
+
+	if err := json.Unmarshal(line, &h); err != nil {
+		return err
+	}
+
+	if h.Type != recordTypeHeader {
+		return ErrMissingHeader
+	}
+
+	r.header = &h.Header
+	return nil
+}
+
+// parseJSONLine parses a single JSON lines record.
+// It returns a nil result, if the record is no result.
+func (r *Reader) parseJSONLine(line []byte) (*client.Result, error) {
+	var rec struct {
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return nil, err
+	}
+
+	switch rec.Type {
+	case recordTypeResult:
+		var res result
+		if err := json.Unmarshal(line, &res); err != nil {
+			return nil, err
+		}
+
+		return fromResult(&res)
+	case recordTypeFooter:
+		var f Footer
+		if err := json.Unmarshal(line, &f); err != nil {
+			return nil, err
+		}
+
+		r.footer = &f
+	}
+
+	return nil, nil
+}
+
+func (r *Reader) parseCSVHeader(line []byte) error {
+	prefix := []byte("# " + recordTypeHeader + " ")
+	if !bytes.HasPrefix(line, prefix) {
+		return ErrMissingHeader
+	}
+
+	var h Header
+	if err := json.Unmarshal(line[len(prefix):], &h); err != nil {
+		return err
+	}
+	r.header = &h
+
+	columns, err := r.readLine()
+	if err != nil {
+		return ErrMissingHeader
+	}
+
+	record, err := csv.NewReader(bytes.NewReader(columns)).Read()
+	if err != nil {
+		return err
+	}
+
+	r.columns = make(map[string]int, len(record))
+	for i, name := range record {
+		r.columns[name] = i
+	}
+
+	return nil
+}
+
+// parseCSVLine parses a single CSV record or comment.
+// It returns a nil result, if the line is no result.
+func (r *Reader) parseCSVLine(line []byte) (*client.Result, error) {
+	footerPrefix := []byte("# " + recordTypeFooter + " ")
+
+	if bytes.HasPrefix(line, footerPrefix) {
+		var f Footer
+		if err := json.Unmarshal(line[len(footerPrefix):], &f); err != nil {
+			return nil, err
+		}
+
+		r.footer = &f
+		return nil, nil
+	}
+
+	if bytes.HasPrefix(line, []byte("#")) {
+		return nil, nil
+	}
+
+	record, err := csv.NewReader(bytes.NewReader(line)).Read()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.csvResult(record)
+}
+
+// csvResult converts a CSV record into a result.
+func (r *Reader) csvResult(record []string) (*client.Result, error) {
+	field := func(name string) string {
+		i, ok := r.columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+
+		return record[i]
+	}
+
+	var (
+		res result
+		err error
+	)
+
+	if s := field("time"); s != "" {
+		if res.Time, err = time.Parse(time.RFC3339Nano, s); err != nil {
+			return nil, err
+		}
+	}
+
+	res.Worker = field("worker")
+	res.URL = field("url")
+	res.HTTPStatusMessage = field("status_message")
+	res.Journey = field("journey")
+	res.Step = field("step")
+	res.Error = field("error")
+	res.ErrorClass = field("error_class")
+	res.Event = field("event")
+	res.Emulation = field("emulation")
+	res.Artifact = field("artifact")
+	res.HAR = field("har")
+
+	if s := field("user"); s != "" {
+		if res.User, err = strconv.Atoi(s); err != nil {
+			return nil, err
+		}
+	}
+
+	if s := field("status_code"); s != "" {
+		if res.HTTPStatusCode, err = strconv.Atoi(s); err != nil {
+			return nil, err
+		}
+	}
+
+	if s := field("js_errors"); s != "" {
+		if res.JSErrors, err = strconv.Atoi(s); err != nil {
+			return nil, err
+		}
+	}
+
+	floats := []struct {
+		name string
+		v    *float64
+	}{
+		{"ttfb_ms", &res.TTFB},
+		{"dom_content_loaded_ms", &res.DOMContentLoaded},
+		{"load_ms", &res.Load},
+		{"first_paint_ms", &res.FirstPaint},
+		{"first_contentful_paint_ms", &res.FirstContentfulPaint},
+		{"largest_contentful_paint_ms", &res.LargestContentfulPaint},
+		{"cumulative_layout_shift", &res.CumulativeLayoutShift},
+		{"dns_ms", &res.DNS},
+		{"connect_ms", &res.Connect},
+		{"ssl_ms", &res.SSL},
+		{"send_ms", &res.Send},
+		{"wait_ms", &res.Wait},
+		{"duration_ms", &res.Duration},
+	}
+
+	for _, f := range floats {
+		if s := field(f.name); s != "" {
+			if *f.v, err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if s := field("subresources"); s != "" {
+		if err := json.Unmarshal([]byte(s), &res.Subresources); err != nil {
+			return nil, err
+		}
+	}
+
+	if s := field("js_error_messages"); s != "" {
+		if err := json.Unmarshal([]byte(s), &res.JSErrorMessages); err != nil {
+			return nil, err
+		}
+	}
+
+	if s := field("cached"); s != "" {
+		if res.Cached, err = strconv.ParseBool(strings.ToLower(s)); err != nil {
+			return nil, err
+		}
+	}
+
+	if s := field("cold_cache"); s != "" {
+		if res.ColdCache, err = strconv.ParseBool(strings.ToLower(s)); err != nil {
+			return nil, err
+		}
+	}
+
+	return fromResult(&res)
+}
+
+func fromResult(res *result) (*client.Result, error) {
+	u, err := url.Parse(res.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	var subresources []*client.Subresource
+	for _, s := range res.Subresources {
+		subresources = append(subresources, &client.Subresource{
+			URL:               s.URL,
+			Type:              s.Type,
+			HttpStatusCode:    s.HTTPStatusCode,
+			HttpStatusMessage: s.HTTPStatusMessage,
+			Ttfb:              fromMilliseconds(s.TTFB),
+			Size:              s.Size,
+			Cached:            s.Cached,
+			Initiator:         s.Initiator,
+			InitiatorURL:      s.InitiatorURL,
+			Error:             s.Error,
+		})
+	}
+
+	var artifact *client.Artifact
+	if res.Artifact != "" {
+		artifact = &client.Artifact{Name: res.Artifact}
+	}
+
+	var har *client.HAR
+	if res.HAR != "" {
+		har = &client.HAR{Name: res.HAR}
+	}
+
+	return &client.Result{
+		Time:              res.Time,
+		Worker:            res.Worker,
+		User:              res.User,
+		URL:               u,
+		HttpStatusCode:    res.HTTPStatusCode,
+		HttpStatusMessage: res.HTTPStatusMessage,
+		Ttfb:              fromMilliseconds(res.TTFB),
+		Cached:            res.Cached,
+		Timing: client.PageTiming{
+			DOMContentLoaded:       fromMilliseconds(res.DOMContentLoaded),
+			Load:                   fromMilliseconds(res.Load),
+			FirstPaint:             fromMilliseconds(res.FirstPaint),
+			FirstContentfulPaint:   fromMilliseconds(res.FirstContentfulPaint),
+			LargestContentfulPaint: fromMilliseconds(res.LargestContentfulPaint),
+			CumulativeLayoutShift:  res.CumulativeLayoutShift,
+		},
+		Network: client.NetworkTiming{
+			DNS:     fromMilliseconds(res.DNS),
+			Connect: fromMilliseconds(res.Connect),
+			SSL:     fromMilliseconds(res.SSL),
+			Send:    fromMilliseconds(res.Send),
+			Wait:    fromMilliseconds(res.Wait),
+		},
+		Subresources: subresources,
+		Journey:      res.Journey,
+		Step:         res.Step,
+		Duration:     fromMilliseconds(res.Duration),
+		Error:        res.Error,
+		ErrorClass:   res.ErrorClass,
+		Event:        res.Event,
+		Emulation:    res.Emulation,
+		ColdCache:    res.ColdCache,
+		Artifact:     artifact,
+		HAR:          har,
+
+		JSErrors:        res.JSErrors,
+		JSErrorMessages: res.JSErrorMessages,
+	}, nil
+}
+
+func fromMilliseconds(ms float64) time.Duration {
+	return time.Duration(math.Round(ms * float64(time.Millisecond)))
+}
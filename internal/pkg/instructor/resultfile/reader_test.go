@@ -0,0 +1,127 @@
+package resultfile
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_RoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSONLines, FormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "loago_resultfile")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "results."+string(format))
+			w, err := Create(path, format, newTestHeader(), 0)
+			require.NoError(t, err)
+
+			expected := newTestResult(t)
+			expected.Subresources = []*client.Subresource{
+				{
+					URL:            "http://foo.bar/app.js",
+					Type:           "Script",
+					HttpStatusCode: 200,
+					Ttfb:           12 * time.Millisecond,
+					Size:           2048,
+					Initiator:      "parser",
+					InitiatorURL:   "http://foo.bar",
+				},
+				{URL: "http://foo.bar/img.png", Type: "Image", Error: "net::ERR_FAILED"},
+			}
+			expected.Journey = "checkout"
+			expected.Step = "2 click"
+			expected.Duration = 350 * time.Millisecond
+			expected.Error = "step timed out"
+			expected.ErrorClass = "timeout"
+			expected.Event = "restart"
+			expected.User = 7
+			expected.Emulation = "moto g4 on slow 3g"
+			expected.ColdCache = true
+			expected.JSErrors = 3
+			expected.JSErrorMessages = []string{"Uncaught TypeError: cart is undefined", "request failed: 500"}
+			require.NoError(t, w.Write(expected))
+			require.NoError(t, w.Write(expected))
+			require.NoError(t, w.Close(testStop))
+
+			r, err := Open(path)
+			require.NoError(t, err)
+			defer r.Close()
+
+			assert.Equal(t, format, r.Format())
+			require.NotNil(t, r.Header())
+			assert.Equal(t, Version, r.Header().Version)
+			assert.True(t, testStart.Equal(r.Header().Start))
+			assert.Equal(t, []string{"127.0.0.1:50051"}, r.Header().Workers)
+			assert.Equal(t, "http://foo.bar", r.Header().Config.Endpoints[0].Url)
+			assert.Nil(t, r.Footer())
+
+			for i := 0; i < 2; i++ {
+				res, err := r.Next()
+				require.NoError(t, err)
+				assert.True(t, expected.Time.Equal(res.Time))
+				assert.Equal(t, expected.Worker, res.Worker)
+				assert.Equal(t, expected.URL.String(), res.URL.String())
+				assert.Equal(t, expected.HttpStatusCode, res.HttpStatusCode)
+				assert.Equal(t, expected.HttpStatusMessage, res.HttpStatusMessage)
+				assert.Equal(t, expected.Ttfb, res.Ttfb)
+				assert.Equal(t, expected.Cached, res.Cached)
+				assert.Equal(t, expected.Timing, res.Timing)
+				assert.Equal(t, expected.Network, res.Network)
+				assert.Equal(t, expected.Subresources, res.Subresources)
+				assert.Equal(t, expected.Journey, res.Journey)
+				assert.Equal(t, expected.Step, res.Step)
+				assert.Equal(t, expected.Duration, res.Duration)
+				assert.Equal(t, expected.Error, res.Error)
+				assert.Equal(t, expected.ErrorClass, res.ErrorClass)
+				assert.Equal(t, expected.Event, res.Event)
+				assert.Equal(t, expected.Emulation, res.Emulation)
+				assert.Equal(t, expected.ColdCache, res.ColdCache)
+				assert.Equal(t, expected.JSErrors, res.JSErrors)
+				assert.Equal(t, expected.JSErrorMessages, res.JSErrorMessages)
+			}
+
+			_, err = r.Next()
+			assert.Equal(t, io.EOF, err)
+			require.NotNil(t, r.Footer())
+			assert.Equal(t, uint64(2), r.Footer().Results)
+			assert.True(t, testStop.Equal(r.Footer().Stop))
+		})
+	}
+}
+
+func TestReader_Truncated(t *testing.T) {
+	in := `{"type":"header","version":1,"start":"2020-11-30T12:00:00Z","workers":[],"config":null}
+{"type":"result","time":"2020-11-30T12:00:01Z","worker":"w","url":"http://foo.bar","status_code":200,"status_message":"OK","ttfb_ms":3,"cached":false}`
+
+	r, err := NewReader(strings.NewReader(in))
+	require.NoError(t, err)
+
+	res, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.HttpStatusCode)
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+	assert.Nil(t, r.Footer())
+}
+
+func TestReader_MissingHeader(t *testing.T) {
+	_, err := NewReader(strings.NewReader(""))
+	assert.Equal(t, ErrMissingHeader, err)
+
+	_, err = NewReader(strings.NewReader("time,worker,url\n"))
+	assert.Equal(t, ErrMissingHeader, err)
+
+	_, err = NewReader(strings.NewReader(`{"type":"result"}` + "\n"))
+	assert.Equal(t, ErrMissingHeader, err)
+}
@@ -0,0 +1,83 @@
+// Package resultfile provides persistence of loadtest results received
+// by an instructor. Results are streamed into a file as they arrive,
+// framed by a header and a footer containing metadata about the run.
+package resultfile
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dkorittki/loago/pkg/instructor/config"
+)
+
+// Version is the version of the result file layout.
+const Version = 1
+
+// Format is the encoding of a result file.
+type Format string
+
+const (
+	// FormatJSONLines encodes the header, every result and the footer
+	// as one JSON object per line.
+	FormatJSONLines Format = "jsonl"
+
+	// FormatCSV encodes every result as a CSV record.
+	// Header and footer are written as comment lines starting with '#'.
+	FormatCSV Format = "csv"
+)
+
+var (
+	// ErrUnknownFormat indicates an error when an unsupported format is given.
+	ErrUnknownFormat = errors.New("unknown result file format")
+
+	// ErrClosed indicates an error when writing into an already closed result file.
+	ErrClosed = errors.New("result file already closed")
+)
+
+// FormatFromPath returns the format matching the file extension of path.
+// It defaults to FormatJSONLines for unknown extensions.
+func FormatFromPath(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return FormatCSV
+	}
+
+	return FormatJSONLines
+}
+
+// ArtifactsDir returns the directory, in which artifacts of the result file
+// at path are stored, i.e. "results_artifacts" for "results.jsonl".
+// Artifacts are named relative to the directory of the result file, so
+// artifact "results_artifacts/1" consists of the files "results_artifacts/1.jpg"
+// and "results_artifacts/1.html" next to the result file. HARs are
+// named after their file, i.e. "results_artifacts/1.har".
+func ArtifactsDir(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + "_artifacts"
+}
+
+// Header contains metadata about a run and is written
+// at the beginning of a result file.
+type Header struct {
+	// Version of the result file layout.
+	Version int `json:"version"`
+
+	// Start is the time at which the run started.
+	Start time.Time `json:"start"`
+
+	// Workers contains the addresses of all workers taking part in the run.
+	Workers []string `json:"workers"`
+
+	// Config is the instructor config used for the run.
+	Config *config.InstructorConfig `json:"config"`
+}
+
+// Footer contains metadata about a run and is written
+// at the end of a result file.
+type Footer struct {
+	// Stop is the time at which the run stopped.
+	Stop time.Time `json:"stop"`
+
+	// Results is the amount of results written into the file.
+	Results uint64 `json:"results"`
+}
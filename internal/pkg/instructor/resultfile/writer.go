@@ -0,0 +1,248 @@
+package resultfile
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+)
+
+// DefaultFlushInterval is the default interval in which
+// buffered results are flushed to disk.
+const DefaultFlushInterval = time.Second
+
+// Writer streams results into a result file.
+// It is safe for concurrent use.
+type Writer struct {
+	mu     sync.Mutex
+	file   *os.File
+	buf    *bufio.Writer
+	enc    encoder
+	count  uint64
+	closed bool
+
+	// artifacts is the directory of artifacts, which is created with the first artifact.
+	artifacts     string
+	artifactCount int
+	harCount      int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Create creates the file at path, writes header h into it and returns
+// a Writer for streaming results into the file using format.
+// Buffered results are flushed to disk every flushInterval.
+// A flushInterval of zero disables periodic flushing.
+func Create(path string, format Format, h *Header, flushInterval time.Duration) (*Writer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{
+		file:      f,
+		buf:       bufio.NewWriter(f),
+		artifacts: ArtifactsDir(path),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+
+	switch format {
+	case FormatJSONLines:
+		w.enc = newJSONLinesEncoder(w.buf)
+	case FormatCSV:
+		w.enc = newCSVEncoder(w.buf)
+	default:
+		_ = f.Close()
+		_ = os.Remove(path)
+		return nil, ErrUnknownFormat
+	}
+
+	if h.Version == 0 {
+		h.Version = Version
+	}
+
+	if err := w.enc.header(h); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if err := w.buf.Flush(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	go w.flushPeriodically(flushInterval)
+
+	return w, nil
+}
+
+// Write writes a single result into the file.
+// The artifact and HAR of the result, if any, are stored in the artifacts
+// directory of the file and named after the stored files.
+// A result, whose artifact or HAR can't be stored, is written without it.
+func (w *Writer) Write(r *client.Result) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrClosed
+	}
+
+	var artifactErr error
+	if r.Artifact != nil {
+		artifactErr = w.writeArtifact(r.Artifact)
+	}
+
+	if r.HAR != nil {
+		if err := w.writeHAR(r.HAR); artifactErr == nil {
+			artifactErr = err
+		}
+	}
+
+	if err := w.enc.result(r); err != nil {
+		return err
+	}
+
+	w.count++
+	return artifactErr
+}
+
+// Flush writes buffered results to disk.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrClosed
+	}
+
+	return w.flush()
+}
+
+// Close writes the footer containing the stop time of the run,
+// flushes all buffered results and closes the file.
+func (w *Writer) Close(stop time.Time) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrClosed
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.stop)
+	<-w.done
+
+	err := w.enc.footer(&Footer{Stop: stop, Results: w.count})
+	if err == nil {
+		err = w.flush()
+	}
+
+	if cerr := w.file.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// Count returns the amount of results written so far.
+func (w *Writer) Count() uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.count
+}
+
+// writeArtifact stores the screenshot and HTML of a as files
+// named after the sequence number of the artifact.
+func (w *Writer) writeArtifact(a *client.Artifact) error {
+	if len(a.Screenshot) == 0 && a.HTML == "" {
+		return nil
+	}
+
+	if w.artifactCount == 0 {
+		if err := os.MkdirAll(w.artifacts, 0755); err != nil {
+			return err
+		}
+	}
+
+	w.artifactCount++
+	path := filepath.Join(w.artifacts, strconv.Itoa(w.artifactCount))
+
+	if len(a.Screenshot) > 0 {
+		if err := ioutil.WriteFile(path+".jpg", a.Screenshot, 0644); err != nil {
+			return err
+		}
+	}
+
+	if a.HTML != "" {
+		if err := ioutil.WriteFile(path+".html", []byte(a.HTML), 0644); err != nil {
+			return err
+		}
+	}
+
+	a.Name = filepath.Join(filepath.Base(w.artifacts), strconv.Itoa(w.artifactCount))
+	return nil
+}
+
+// writeHAR stores h as file named after the sequence number of the HAR.
+func (w *Writer) writeHAR(h *client.HAR) error {
+	if len(h.JSON) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(w.artifacts, 0755); err != nil {
+		return err
+	}
+
+	w.harCount++
+	name := strconv.Itoa(w.harCount) + ".har"
+
+	if err := ioutil.WriteFile(filepath.Join(w.artifacts, name), h.JSON, 0644); err != nil {
+		return err
+	}
+
+	h.Name = filepath.Join(filepath.Base(w.artifacts), name)
+	return nil
+}
+
+func (w *Writer) flush() error {
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+
+	return w.file.Sync()
+}
+
+// flushPeriodically flushes the buffer every interval until Close is called.
+func (w *Writer) flushPeriodically(interval time.Duration) {
+	defer close(w.done)
+
+	if interval <= 0 {
+		<-w.stop
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.mu.Lock()
+			if !w.closed {
+				_ = w.flush()
+			}
+			w.mu.Unlock()
+		case <-w.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package resultfile
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+	"github.com/dkorittki/loago/pkg/instructor/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testStart = time.Date(2020, 11, 30, 12, 0, 0, 0, time.UTC)
+	testStop  = testStart.Add(time.Minute)
+)
+
+func newTestHeader() *Header {
+	return &Header{
+		Start:   testStart,
+		Workers: []string{"127.0.0.1:50051"},
+		Config: &config.InstructorConfig{
+			Workers: []*config.InstructorWorkerConfig{
+				{
+					Alias:  "local",
+					Adress: "127.0.0.1",
+					Port:   50051,
+					Secret: "foobar",
+				},
+			},
+			Endpoints: []*config.InstructorEndpoint{
+				{
+					Url:    "http://foo.bar",
+					Weight: 1,
+				},
+			},
+			Amount:  1,
+			MinWait: 1000,
+			MaxWait: 2000,
+		},
+	}
+}
+
+func newTestResult(t *testing.T) *client.Result {
+	u, err := url.Parse("http://foo.bar")
+	require.NoError(t, err)
+
+	return &client.Result{
+		Time:              testStart.Add(time.Second),
+		Worker:            "127.0.0.1:50051",
+		URL:               u,
+		HttpStatusCode:    200,
+		HttpStatusMessage: "OK",
+		Ttfb:              1500 * time.Microsecond,
+		Timing: client.PageTiming{
+			DOMContentLoaded:      120 * time.Millisecond,
+			Load:                  250 * time.Millisecond,
+			FirstPaint:            80 * time.Millisecond,
+			FirstContentfulPaint:  80 * time.Millisecond,
+			CumulativeLayoutShift: 0.05,
+		},
+		Network: client.NetworkTiming{
+			Send: 250 * time.Microsecond,
+			Wait: 1250 * time.Microsecond,
+		},
+	}
+}
+
+func readLines(t *testing.T, path string) []string {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+	require.NoError(t, s.Err())
+
+	return lines
+}
+
+func TestFormatFromPath(t *testing.T) {
+	assert.Equal(t, FormatCSV, FormatFromPath("results.csv"))
+	assert.Equal(t, FormatCSV, FormatFromPath("/tmp/RESULTS.CSV"))
+	assert.Equal(t, FormatJSONLines, FormatFromPath("results.jsonl"))
+	assert.Equal(t, FormatJSONLines, FormatFromPath("results"))
+}
+
+func TestWriter_JSONLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loago_resultfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "results.jsonl")
+	w, err := Create(path, FormatJSONLines, newTestHeader(), 0)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(newTestResult(t)))
+	require.NoError(t, w.Write(newTestResult(t)))
+	assert.Equal(t, uint64(2), w.Count())
+	require.NoError(t, w.Close(testStop))
+
+	lines := readLines(t, path)
+	require.Len(t, lines, 4)
+
+	var header map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &header))
+	assert.Equal(t, "header", header["type"])
+	assert.Equal(t, float64(Version), header["version"])
+	assert.NotContains(t, lines[0], "foobar")
+
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &res))
+	assert.Equal(t, "result", res["type"])
+	assert.Equal(t, "http://foo.bar", res["url"])
+	assert.Equal(t, "127.0.0.1:50051", res["worker"])
+	assert.Equal(t, float64(0), res["user"])
+	assert.Equal(t, float64(200), res["status_code"])
+	assert.Equal(t, 1.5, res["ttfb_ms"])
+
+	var footer map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[3]), &footer))
+	assert.Equal(t, "footer", footer["type"])
+	assert.Equal(t, float64(2), footer["results"])
+}
+
+func TestWriter_CSV(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loago_resultfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "results.csv")
+	w, err := Create(path, FormatCSV, newTestHeader(), 0)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(newTestResult(t)))
+	require.NoError(t, w.Close(testStop))
+
+	lines := readLines(t, path)
+	require.Len(t, lines, 4)
+
+	assert.True(t, strings.HasPrefix(lines[0], "# header {"))
+	assert.Equal(t, strings.Join(csvColumns, ","), lines[1])
+	assert.Equal(t, "2020-11-30T12:00:01Z,127.0.0.1:50051,0,http://foo.bar,200,OK,1.5,false,120,250,80,80,0,0.05,0,0,0,0.25,1.25,,,0,,,,,false,,,0,,", lines[2])
+	assert.True(t, strings.HasPrefix(lines[3], "# footer {"))
+}
+
+func TestWriter_Artifacts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loago_resultfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "results.csv")
+	w, err := Create(path, FormatCSV, newTestHeader(), 0)
+	require.NoError(t, err)
+
+	// results without artifacts don't create the artifacts directory
+	require.NoError(t, w.Write(newTestResult(t)))
+	_, err = os.Stat(ArtifactsDir(path))
+	assert.True(t, os.IsNotExist(err))
+
+	res := newTestResult(t)
+	res.HttpStatusCode = 500
+	res.Artifact = &client.Artifact{Screenshot: []byte{0xff, 0xd8}, HTML: "<html></html>"}
+	require.NoError(t, w.Write(res))
+
+	res.Artifact = &client.Artifact{Screenshot: []byte{0xff, 0xd8}}
+	require.NoError(t, w.Write(res))
+	require.NoError(t, w.Close(testStop))
+
+	assert.Equal(t, filepath.Join("results_artifacts", "2"), res.Artifact.Name)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "results_artifacts", "1.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0xd8}, b)
+
+	b, err = ioutil.ReadFile(filepath.Join(dir, "results_artifacts", "1.html"))
+	require.NoError(t, err)
+	assert.Equal(t, "<html></html>", string(b))
+
+	_, err = os.Stat(filepath.Join(dir, "results_artifacts", "2.html"))
+	assert.True(t, os.IsNotExist(err))
+
+	r, err := Open(path)
+	require.NoError(t, err)
+	defer r.Close()
+
+	res, err = r.Next()
+	require.NoError(t, err)
+	assert.Nil(t, res.Artifact)
+
+	res, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, &client.Artifact{Name: filepath.Join("results_artifacts", "1")}, res.Artifact)
+}
+
+func TestWriter_HAR(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loago_resultfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "results.jsonl")
+	w, err := Create(path, FormatJSONLines, newTestHeader(), 0)
+	require.NoError(t, err)
+
+	res := newTestResult(t)
+	res.HAR = &client.HAR{JSON: []byte(`{"log":{"version":"1.2"}}`)}
+	require.NoError(t, w.Write(res))
+
+	// HARs are numbered independently of artifacts
+	res = newTestResult(t)
+	res.Artifact = &client.Artifact{Screenshot: []byte{0xff, 0xd8}}
+	res.HAR = &client.HAR{JSON: []byte(`{"log":{"version":"1.2"}}`)}
+	require.NoError(t, w.Write(res))
+	require.NoError(t, w.Close(testStop))
+
+	assert.Equal(t, filepath.Join("results_artifacts", "2.har"), res.HAR.Name)
+	assert.Equal(t, filepath.Join("results_artifacts", "1"), res.Artifact.Name)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "results_artifacts", "1.har"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"log":{"version":"1.2"}}`, string(b))
+
+	r, err := Open(path)
+	require.NoError(t, err)
+	defer r.Close()
+
+	res, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, &client.HAR{Name: filepath.Join("results_artifacts", "1.har")}, res.HAR)
+	assert.Nil(t, res.Artifact)
+}
+
+func TestWriter_PeriodicFlush(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loago_resultfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "results.jsonl")
+	w, err := Create(path, FormatJSONLines, newTestHeader(), 50*time.Millisecond)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Write(newTestResult(t)))
+	time.Sleep(200 * time.Millisecond)
+
+	// The result must be on disk even though the file is not closed yet.
+	assert.Len(t, readLines(t, path), 2)
+
+	require.NoError(t, w.Close(testStop))
+	assert.Equal(t, ErrClosed, w.Write(newTestResult(t)))
+	assert.Equal(t, ErrClosed, w.Close(testStop))
+}
+
+func TestCreate_UnknownFormat(t *testing.T) {
+	dir, err := ioutil.TempDir("", "loago_resultfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "results.xml")
+	_, err = Create(path, Format("xml"), newTestHeader(), 0)
+
+	assert.Equal(t, ErrUnknownFormat, err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,192 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// histogramPrecision is the relative width of a histogram bucket.
+	// Quantiles read from a histogram have a relative error of at most half of it.
+	histogramPrecision = 0.01
+
+	// histogramUnit is the smallest value distinguished by a histogram.
+	// Values below are counted in the first bucket.
+	histogramUnit = time.Microsecond
+)
+
+var logBase = math.Log1p(histogramPrecision)
+
+// Histogram is a memory-bounded histogram of durations.
+// Buckets grow exponentially, so their amount is only logarithmic
+// to the range of recorded values, no matter how many values are recorded.
+// Min, max, sum and count are tracked exactly.
+//
+// The zero value is an empty histogram ready to use.
+type Histogram struct {
+	buckets map[int]uint64
+	count   uint64
+	sum     time.Duration
+	min     time.Duration
+	max     time.Duration
+}
+
+// NewHistogram returns a new empty Histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{}
+}
+
+// bucketIndex returns the index of the bucket d is counted in.
+func bucketIndex(d time.Duration) int {
+	if d < histogramUnit {
+		return 0
+	}
+
+	return int(math.Log(float64(d)/float64(histogramUnit))/logBase) + 1
+}
+
+// bucketValue returns the representative value of the bucket with index i.
+func bucketValue(i int) time.Duration {
+	if i == 0 {
+		return 0
+	}
+
+	lower := float64(histogramUnit) * math.Exp(float64(i-1)*logBase)
+	return time.Duration(lower * (1 + histogramPrecision/2))
+}
+
+// Record adds d to the histogram.
+func (h *Histogram) Record(d time.Duration) {
+	if h.buckets == nil {
+		h.buckets = make(map[int]uint64)
+	}
+
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+
+	if h.count == 0 || d > h.max {
+		h.max = d
+	}
+
+	h.buckets[bucketIndex(d)]++
+	h.count++
+	h.sum += d
+}
+
+// Merge adds every value recorded by o to h.
+func (h *Histogram) Merge(o *Histogram) {
+	if o == nil || o.count == 0 {
+		return
+	}
+
+	if h.buckets == nil {
+		h.buckets = make(map[int]uint64)
+	}
+
+	if h.count == 0 || o.min < h.min {
+		h.min = o.min
+	}
+
+	if h.count == 0 || o.max > h.max {
+		h.max = o.max
+	}
+
+	for i, c := range o.buckets {
+		h.buckets[i] += c
+	}
+
+	h.count += o.count
+	h.sum += o.sum
+}
+
+// Count returns the amount of recorded values.
+func (h *Histogram) Count() uint64 {
+	return h.count
+}
+
+// Min returns the smallest recorded value.
+func (h *Histogram) Min() time.Duration {
+	return h.min
+}
+
+// Max returns the largest recorded value.
+func (h *Histogram) Max() time.Duration {
+	return h.max
+}
+
+// Mean returns the arithmetic mean of all recorded values.
+func (h *Histogram) Mean() time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	return h.sum / time.Duration(h.count)
+}
+
+// Quantile returns an approximation of the q-quantile of all recorded values,
+// i.e. q = 0.95 returns the 95th percentile.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if h.count == 0 {
+		return 0
+	}
+
+	if q <= 0 {
+		return h.min
+	}
+
+	if q >= 1 {
+		return h.max
+	}
+
+	rank := uint64(math.Ceil(q * float64(h.count)))
+	var seen uint64
+
+	for _, b := range h.Buckets() {
+		seen += b.Count
+		if seen >= rank {
+			return h.clamp(b.Value)
+		}
+	}
+
+	return h.max
+}
+
+// Bucket is a single histogram bucket.
+type Bucket struct {
+	// Value is the representative value of all values counted in this bucket.
+	Value time.Duration
+
+	// Count is the amount of values counted in this bucket.
+	Count uint64
+}
+
+// Buckets returns all non-empty buckets in ascending order.
+func (h *Histogram) Buckets() []Bucket {
+	indices := make([]int, 0, len(h.buckets))
+	for i := range h.buckets {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	buckets := make([]Bucket, len(indices))
+	for j, i := range indices {
+		buckets[j] = Bucket{Value: h.clamp(bucketValue(i)), Count: h.buckets[i]}
+	}
+
+	return buckets
+}
+
+// clamp limits d to the exact range of recorded values.
+func (h *Histogram) clamp(d time.Duration) time.Duration {
+	if d < h.min {
+		return h.min
+	}
+
+	if d > h.max {
+		return h.max
+	}
+
+	return d
+}
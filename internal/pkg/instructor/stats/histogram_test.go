@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram_Empty(t *testing.T) {
+	h := NewHistogram()
+
+	assert.Equal(t, uint64(0), h.Count())
+	assert.Equal(t, time.Duration(0), h.Min())
+	assert.Equal(t, time.Duration(0), h.Max())
+	assert.Equal(t, time.Duration(0), h.Mean())
+	assert.Equal(t, time.Duration(0), h.Quantile(0.5))
+	assert.Empty(t, h.Buckets())
+}
+
+func TestHistogram_Record(t *testing.T) {
+	h := NewHistogram()
+	h.Record(10 * time.Millisecond)
+	h.Record(20 * time.Millisecond)
+	h.Record(30 * time.Millisecond)
+
+	assert.Equal(t, uint64(3), h.Count())
+	assert.Equal(t, 10*time.Millisecond, h.Min())
+	assert.Equal(t, 30*time.Millisecond, h.Max())
+	assert.Equal(t, 20*time.Millisecond, h.Mean())
+	assert.Equal(t, 10*time.Millisecond, h.Quantile(0))
+	assert.Equal(t, 30*time.Millisecond, h.Quantile(1))
+	assert.InEpsilon(t, float64(20*time.Millisecond), float64(h.Quantile(0.5)), histogramPrecision)
+}
+
+func TestHistogram_Quantile(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	h := NewHistogram()
+	values := make([]time.Duration, 100000)
+
+	for i := range values {
+		values[i] = time.Duration(rnd.ExpFloat64() * float64(100*time.Millisecond))
+		h.Record(values[i])
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	for _, q := range []float64{0.5, 0.9, 0.95, 0.99} {
+		exact := values[int(q*float64(len(values)))-1]
+		assert.InEpsilon(t, float64(exact), float64(h.Quantile(q)), histogramPrecision, "quantile %v", q)
+	}
+
+	// Memory is bounded by the amount of buckets, not by the amount of values.
+	assert.Less(t, len(h.buckets), 2000)
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	h1 := NewHistogram()
+	h1.Record(10 * time.Millisecond)
+	h1.Record(20 * time.Millisecond)
+
+	h2 := NewHistogram()
+	h2.Record(5 * time.Millisecond)
+	h2.Record(40 * time.Millisecond)
+
+	h1.Merge(h2)
+	h1.Merge(nil)
+	h1.Merge(NewHistogram())
+
+	assert.Equal(t, uint64(4), h1.Count())
+	assert.Equal(t, 5*time.Millisecond, h1.Min())
+	assert.Equal(t, 40*time.Millisecond, h1.Max())
+	assert.Equal(t, 75*time.Millisecond/4, h1.Mean())
+}
@@ -0,0 +1,201 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// totalName is the row name of the overall statistics.
+const totalName = "TOTAL"
+
+// printedSubresources is the amount of subresources printed in a summary.
+const printedSubresources = 10
+
+// printedJSErrors is the amount of JavaScript error messages printed in a summary.
+const printedJSErrors = 10
+
+// Print writes a human readable table of the summary into w.
+func (s *Summary) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "URL\tREQUESTS\tERRORS\tCACHED\tMIN\tMEAN\tMAX\tP50\tP90\tP95\tP99")
+	for _, url := range s.URLs() {
+		printStats(tw, url, s.Endpoints[url])
+	}
+	printStats(tw, totalName, s.Total)
+
+	if s.Cold.Requests > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "CACHE\tREQUESTS\tERRORS\tCACHED\tMIN\tMEAN\tMAX\tP50\tP90\tP95\tP99")
+		printStats(tw, "cold", s.Cold)
+		printStats(tw, "warm", s.Warm)
+	}
+
+	if len(s.Emulations) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "EMULATION\tREQUESTS\tERRORS\tCACHED\tMIN\tMEAN\tMAX\tP50\tP90\tP95\tP99")
+		for _, name := range s.EmulationNames() {
+			printStats(tw, name, s.Emulations[name])
+		}
+	}
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "URL\tDNS MEAN/P95\tCONNECT MEAN/P95\tSSL MEAN/P95\tSEND MEAN/P95\tWAIT MEAN/P95")
+	for _, url := range s.URLs() {
+		printPhases(tw, url, s.Endpoints[url].Network)
+	}
+	printPhases(tw, totalName, s.Total.Network)
+
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "URL\tSTATUS CODES")
+	for _, url := range s.URLs() {
+		printStatusCodes(tw, url, s.Endpoints[url])
+	}
+	printStatusCodes(tw, totalName, s.Total)
+
+	if len(s.Total.Failures) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "URL\tFAILURES")
+		for _, url := range s.URLs() {
+			printFailures(tw, url, s.Endpoints[url])
+		}
+		for _, key := range s.StepKeys() {
+			printFailures(tw, key, s.Steps[key])
+		}
+		printFailures(tw, totalName, s.Total)
+	}
+
+	if len(s.Steps) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "JOURNEY / STEP\tRUNS\tERRORS\tMIN\tMEAN\tMAX\tP50\tP90\tP95\tP99")
+		for _, key := range s.StepKeys() {
+			printSteps(tw, key, s.Steps[key])
+		}
+	}
+
+	if s.Total.JSErrors > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "URL\tREQUESTS\tWITH JS ERRORS\tJS ERRORS")
+		for _, url := range s.URLs() {
+			printJSErrors(tw, url, s.Endpoints[url])
+		}
+		for _, key := range s.StepKeys() {
+			printJSErrors(tw, key, s.Steps[key])
+		}
+		printJSErrors(tw, totalName, s.Total)
+
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "TOP JS ERRORS\tREQUESTS")
+		for _, msg := range s.TopJSErrors(printedJSErrors) {
+			fmt.Fprintf(tw, "%s\t%d\n", msg, s.JSErrorMessages[msg])
+		}
+	}
+
+	if len(s.Events) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "EVENT\tCOUNT\tUSERS")
+		for _, name := range s.EventNames() {
+			fmt.Fprintf(tw, "%s\t%d\t%d\n", name, s.Events[name], s.EventUsers[name])
+		}
+	}
+
+	if len(s.Subresources) > 0 {
+		fmt.Fprintln(tw)
+		fmt.Fprintln(tw, "SLOWEST SUBRESOURCES\tREQUESTS\tERRORS\tCACHED\tMIN\tMEAN\tMAX\tP50\tP90\tP95\tP99")
+		for _, url := range s.SlowestSubresources(printedSubresources) {
+			printStats(tw, url, s.Subresources[url])
+		}
+
+		if s.DroppedSubresources > 0 {
+			fmt.Fprintf(tw, "%d subresources of further URLs aren't counted\n", s.DroppedSubresources)
+		}
+	}
+
+	return tw.Flush()
+}
+
+func printStats(w io.Writer, name string, s *Stats) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		name,
+		s.Requests,
+		s.Errors,
+		FormatPercent(s.CachedRatio()),
+		FormatDuration(s.TTFB.Min()),
+		FormatDuration(s.TTFB.Mean()),
+		FormatDuration(s.TTFB.Max()),
+		FormatDuration(s.TTFB.Quantile(0.50)),
+		FormatDuration(s.TTFB.Quantile(0.90)),
+		FormatDuration(s.TTFB.Quantile(0.95)),
+		FormatDuration(s.TTFB.Quantile(0.99)),
+	)
+}
+
+func printSteps(w io.Writer, name string, s *Stats) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+		name,
+		s.Requests,
+		s.Errors,
+		FormatDuration(s.Duration.Min()),
+		FormatDuration(s.Duration.Mean()),
+		FormatDuration(s.Duration.Max()),
+		FormatDuration(s.Duration.Quantile(0.50)),
+		FormatDuration(s.Duration.Quantile(0.90)),
+		FormatDuration(s.Duration.Quantile(0.95)),
+		FormatDuration(s.Duration.Quantile(0.99)),
+	)
+}
+
+func printJSErrors(w io.Writer, name string, s *Stats) {
+	fmt.Fprintf(w, "%s\t%d\t%s\t%d\n",
+		name,
+		s.Requests,
+		FormatPercent(s.JSErrorRate()),
+		s.JSErrors,
+	)
+}
+
+func printPhases(w io.Writer, name string, p *Phases) {
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+		name,
+		formatMeanP95(p.DNS),
+		formatMeanP95(p.Connect),
+		formatMeanP95(p.SSL),
+		formatMeanP95(p.Send),
+		formatMeanP95(p.Wait),
+	)
+}
+
+func formatMeanP95(h *Histogram) string {
+	return FormatDuration(h.Mean()) + " / " + FormatDuration(h.Quantile(0.95))
+}
+
+func printStatusCodes(w io.Writer, name string, s *Stats) {
+	codes := make([]string, 0, len(s.StatusCodes))
+	for _, code := range s.Codes() {
+		codes = append(codes, fmt.Sprintf("%d: %d", code, s.StatusCodes[code]))
+	}
+
+	fmt.Fprintf(w, "%s\t%s\n", name, strings.Join(codes, ", "))
+}
+
+func printFailures(w io.Writer, name string, s *Stats) {
+	classes := make([]string, 0, len(s.Failures))
+	for _, class := range s.FailureClasses() {
+		classes = append(classes, fmt.Sprintf("%s: %d", class, s.Failures[class]))
+	}
+
+	fmt.Fprintf(w, "%s\t%s\n", name, strings.Join(classes, ", "))
+}
+
+// FormatDuration formats d in milliseconds with one decimal place.
+func FormatDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
+
+// FormatPercent formats a ratio between 0 and 1 as a percentage.
+func FormatPercent(r float64) string {
+	return fmt.Sprintf("%.1f%%", r*100)
+}
@@ -0,0 +1,481 @@
+// Package stats aggregates results received by an instructor into
+// memory-bounded summary statistics, such as request counts, error rates
+// and latency percentiles, per URL and overall.
+package stats
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+)
+
+// Stats contains aggregated statistics of a set of results.
+type Stats struct {
+	// Requests is the amount of results.
+	Requests uint64
+
+	// Errors is the amount of failed requests.
+	Errors uint64
+
+	// Cached is the amount of results served from a browser cache.
+	Cached uint64
+
+	// TTFB contains the time-to-first-byte of every result not served from cache.
+	TTFB *Histogram
+
+	// Network contains the network phases of every result not served from cache.
+	Network *Phases
+
+	// Duration contains the duration of every successful journey step.
+	Duration *Histogram
+
+	// StatusCodes counts the results with a response by HTTP status code.
+	// Failed requests without a response are counted by Failures instead.
+	StatusCodes map[int]uint64
+
+	// Failures counts the failed requests and journey steps by their
+	// error class, i.e. "dns" or "timeout".
+	Failures map[string]uint64
+
+	// JSErrors is the amount of JavaScript errors of all pages.
+	JSErrors uint64
+
+	// JSErrorPages is the amount of results with at least one JavaScript error.
+	JSErrorPages uint64
+}
+
+// Phases contains a histogram per network phase of a request.
+type Phases struct {
+	DNS     *Histogram
+	Connect *Histogram
+	SSL     *Histogram
+	Send    *Histogram
+	Wait    *Histogram
+}
+
+// NewPhases returns new empty Phases.
+func NewPhases() *Phases {
+	return &Phases{
+		DNS:     NewHistogram(),
+		Connect: NewHistogram(),
+		SSL:     NewHistogram(),
+		Send:    NewHistogram(),
+		Wait:    NewHistogram(),
+	}
+}
+
+// Record adds the phases of t.
+func (p *Phases) Record(t client.NetworkTiming) {
+	p.DNS.Record(t.DNS)
+	p.Connect.Record(t.Connect)
+	p.SSL.Record(t.SSL)
+	p.Send.Record(t.Send)
+	p.Wait.Record(t.Wait)
+}
+
+// Merge adds every value recorded by o to p.
+func (p *Phases) Merge(o *Phases) {
+	p.DNS.Merge(o.DNS)
+	p.Connect.Merge(o.Connect)
+	p.SSL.Merge(o.SSL)
+	p.Send.Merge(o.Send)
+	p.Wait.Merge(o.Wait)
+}
+
+// NewStats returns a new empty Stats.
+func NewStats() *Stats {
+	return &Stats{
+		TTFB:        NewHistogram(),
+		Network:     NewPhases(),
+		Duration:    NewHistogram(),
+		StatusCodes: make(map[int]uint64),
+		Failures:    make(map[string]uint64),
+	}
+}
+
+// Add adds r to the statistics.
+func (s *Stats) Add(r *client.Result) {
+	s.Requests++
+	if r.HttpStatusCode != 0 {
+		s.StatusCodes[r.HttpStatusCode]++
+	}
+
+	if IsError(r) {
+		s.Errors++
+	}
+
+	if r.ErrorClass != "" {
+		s.Failures[r.ErrorClass]++
+	}
+
+	if r.Cached {
+		s.Cached++
+	} else if r.HttpStatusCode != 0 {
+		s.TTFB.Record(r.Ttfb)
+		s.Network.Record(r.Network)
+	}
+
+	if r.Journey != "" && r.Error == "" {
+		s.Duration.Record(r.Duration)
+	}
+
+	if r.JSErrors > 0 {
+		s.JSErrors += uint64(r.JSErrors)
+		s.JSErrorPages++
+	}
+}
+
+// Merge adds every result aggregated in o to s.
+func (s *Stats) Merge(o *Stats) {
+	s.Requests += o.Requests
+	s.Errors += o.Errors
+	s.Cached += o.Cached
+	s.TTFB.Merge(o.TTFB)
+	s.Network.Merge(o.Network)
+	s.Duration.Merge(o.Duration)
+	s.JSErrors += o.JSErrors
+	s.JSErrorPages += o.JSErrorPages
+
+	for code, c := range o.StatusCodes {
+		s.StatusCodes[code] += c
+	}
+
+	for class, c := range o.Failures {
+		s.Failures[class] += c
+	}
+}
+
+// ErrorRate returns the ratio of failed requests between 0 and 1.
+func (s *Stats) ErrorRate() float64 {
+	return ratio(s.Errors, s.Requests)
+}
+
+// JSErrorRate returns the ratio of results with JavaScript errors between 0 and 1.
+func (s *Stats) JSErrorRate() float64 {
+	return ratio(s.JSErrorPages, s.Requests)
+}
+
+// CachedRatio returns the ratio of cached results between 0 and 1.
+func (s *Stats) CachedRatio() float64 {
+	return ratio(s.Cached, s.Requests)
+}
+
+// Codes returns all received HTTP status codes in ascending order.
+func (s *Stats) Codes() []int {
+	codes := make([]int, 0, len(s.StatusCodes))
+	for code := range s.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	return codes
+}
+
+// FailureClasses returns the error classes of all failures in ascending order.
+func (s *Stats) FailureClasses() []string {
+	classes := make([]string, 0, len(s.Failures))
+	for class := range s.Failures {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	return classes
+}
+
+// IsError returns true, if r represents a failed request.
+// Every result without a response or with a HTTP status code
+// of 400 and above is considered an error. Journey steps
+// which don't load a document have no response, so they
+// are only considered an error, if they failed.
+func IsError(r *client.Result) bool {
+	if r.Error != "" || r.HttpStatusCode >= 400 {
+		return true
+	}
+
+	return r.HttpStatusCode == 0 && r.Journey == ""
+}
+
+func ratio(a, b uint64) float64 {
+	if b == 0 {
+		return 0
+	}
+
+	return float64(a) / float64(b)
+}
+
+// Summary contains statistics per URL and over all results.
+type Summary struct {
+	// Total contains the statistics over all results.
+	Total *Stats
+
+	// Endpoints contains statistics per requested URL.
+	Endpoints map[string]*Stats
+
+	// Subresources contains statistics per URL of resources loaded by pages.
+	// URLs are keyed without their query, and at most maxSubresources
+	// distinct URLs are counted.
+	Subresources map[string]*Stats
+
+	// DroppedSubresources is the amount of subresources,
+	// which aren't counted, since there were too many distinct URLs.
+	DroppedSubresources uint64
+
+	// Steps contains statistics per journey step, keyed by StepKey.
+	// Results of journey steps aren't part of Endpoints.
+	Steps map[string]*Stats
+
+	// steps contains the keys of Steps in the order they were first seen.
+	steps []string
+
+	// Emulations contains statistics per emulation of the users,
+	// i.e. to compare mobile and desktop users.
+	Emulations map[string]*Stats
+
+	// Cold and Warm contain statistics of visits with a cold cache
+	// (first-time visitors) and with a warm cache.
+	Cold *Stats
+	Warm *Stats
+
+	// JSErrorMessages counts the results per JavaScript error message.
+	// At most maxJSErrorMessages distinct messages are counted.
+	JSErrorMessages map[string]uint64
+
+	// Events counts the events of users, i.e. restarts of crashed browsers.
+	// Events aren't requests, so they aren't part of any statistics.
+	Events map[string]uint64
+
+	// EventUsers counts the distinct users per event, i.e. how many users
+	// restarted. Users are identified by their worker and ID.
+	EventUsers map[string]uint64
+
+	// eventUsers contains the users seen per event.
+	eventUsers map[string]map[string]bool
+}
+
+// maxJSErrorMessages bounds the memory of a summary, since messages
+// may contain values unique per page, i.e. IDs.
+const maxJSErrorMessages = 1000
+
+// maxSubresources bounds the memory of a summary, since pages may load
+// resources from URLs unique per page, i.e. tracking pixels.
+const maxSubresources = 1000
+
+// SubresourceKey returns the key of a subresource with the given URL in a summary.
+// Queries and fragments are dropped, since they often differ per page load,
+// i.e. to bust caches.
+func SubresourceKey(url string) string {
+	if i := strings.IndexAny(url, "?#"); i >= 0 {
+		return url[:i]
+	}
+
+	return url
+}
+
+// StepKey returns the key of a step of a journey in a summary.
+func StepKey(journey, step string) string {
+	return journey + " / " + step
+}
+
+// NewSummary returns a new empty Summary.
+func NewSummary() *Summary {
+	return &Summary{
+		Total:        NewStats(),
+		Endpoints:    make(map[string]*Stats),
+		Subresources: make(map[string]*Stats),
+		Steps:        make(map[string]*Stats),
+		Emulations:   make(map[string]*Stats),
+		Cold:         NewStats(),
+		Warm:         NewStats(),
+
+		JSErrorMessages: make(map[string]uint64),
+		Events:          make(map[string]uint64),
+		EventUsers:      make(map[string]uint64),
+		eventUsers:      make(map[string]map[string]bool),
+	}
+}
+
+// Add adds r to the summary.
+func (s *Summary) Add(r *client.Result) {
+	if r.Event != "" {
+		s.addEvent(r)
+		return
+	}
+
+	var url string
+	if r.URL != nil {
+		url = r.URL.String()
+	}
+
+	if r.Journey != "" {
+		key := StepKey(r.Journey, r.Step)
+
+		st, ok := s.Steps[key]
+		if !ok {
+			st = NewStats()
+			s.Steps[key] = st
+			s.steps = append(s.steps, key)
+		}
+
+		st.Add(r)
+	} else {
+		e, ok := s.Endpoints[url]
+		if !ok {
+			e = NewStats()
+			s.Endpoints[url] = e
+		}
+
+		e.Add(r)
+	}
+
+	s.Total.Add(r)
+
+	if r.ColdCache {
+		s.Cold.Add(r)
+	} else {
+		s.Warm.Add(r)
+	}
+
+	if r.Emulation != "" {
+		e, ok := s.Emulations[r.Emulation]
+		if !ok {
+			e = NewStats()
+			s.Emulations[r.Emulation] = e
+		}
+
+		e.Add(r)
+	}
+
+	for _, msg := range r.JSErrorMessages {
+		if _, ok := s.JSErrorMessages[msg]; ok || len(s.JSErrorMessages) < maxJSErrorMessages {
+			s.JSErrorMessages[msg]++
+		}
+	}
+
+	for _, sub := range r.Subresources {
+		key := SubresourceKey(sub.URL)
+
+		st, ok := s.Subresources[key]
+		if !ok {
+			if len(s.Subresources) >= maxSubresources {
+				s.DroppedSubresources++
+				continue
+			}
+
+			st = NewStats()
+			s.Subresources[key] = st
+		}
+
+		// subresources are aggregated like pages, apart from their network phases
+		st.Add(&client.Result{
+			HttpStatusCode: sub.HttpStatusCode,
+			Ttfb:           sub.Ttfb,
+			Cached:         sub.Cached,
+		})
+	}
+}
+
+// addEvent counts the event of r and the user it happened to.
+func (s *Summary) addEvent(r *client.Result) {
+	s.Events[r.Event]++
+
+	users, ok := s.eventUsers[r.Event]
+	if !ok {
+		users = make(map[string]bool)
+		s.eventUsers[r.Event] = users
+	}
+
+	user := r.Worker + "/" + strconv.Itoa(r.User)
+	if !users[user] {
+		users[user] = true
+		s.EventUsers[r.Event]++
+	}
+}
+
+// SlowestSubresources returns the URLs of at most n subresources
+// ordered by their 95th TTFB percentile, slowest first.
+func (s *Summary) SlowestSubresources(n int) []string {
+	urls := make([]string, 0, len(s.Subresources))
+	for url := range s.Subresources {
+		urls = append(urls, url)
+	}
+
+	sort.Slice(urls, func(i, j int) bool {
+		pi := s.Subresources[urls[i]].TTFB.Quantile(0.95)
+		pj := s.Subresources[urls[j]].TTFB.Quantile(0.95)
+		if pi != pj {
+			return pi > pj
+		}
+		return urls[i] < urls[j]
+	})
+
+	if len(urls) > n {
+		urls = urls[:n]
+	}
+
+	return urls
+}
+
+// TopJSErrors returns at most n JavaScript error messages
+// ordered by the amount of results they occurred in, most frequent first.
+func (s *Summary) TopJSErrors(n int) []string {
+	messages := make([]string, 0, len(s.JSErrorMessages))
+	for msg := range s.JSErrorMessages {
+		messages = append(messages, msg)
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		ci := s.JSErrorMessages[messages[i]]
+		cj := s.JSErrorMessages[messages[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return messages[i] < messages[j]
+	})
+
+	if len(messages) > n {
+		messages = messages[:n]
+	}
+
+	return messages
+}
+
+// StepKeys returns the keys of all journey steps in the summary in the order
+// they were first seen, which keeps the steps of a journey in order.
+func (s *Summary) StepKeys() []string {
+	return s.steps
+}
+
+// EventNames returns the names of all events in the summary in ascending order.
+func (s *Summary) EventNames() []string {
+	names := make([]string, 0, len(s.Events))
+	for name := range s.Events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// EmulationNames returns the names of all emulations in the summary in ascending order.
+func (s *Summary) EmulationNames() []string {
+	names := make([]string, 0, len(s.Emulations))
+	for name := range s.Emulations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// URLs returns the URLs of all endpoints in the summary in ascending order.
+func (s *Summary) URLs() []string {
+	urls := make([]string, 0, len(s.Endpoints))
+	for url := range s.Endpoints {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	return urls
+}
@@ -0,0 +1,352 @@
+package stats
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestResult(t *testing.T, rawurl string, code int, ttfb time.Duration, cached bool) *client.Result {
+	u, err := url.Parse(rawurl)
+	require.NoError(t, err)
+
+	return &client.Result{
+		URL:            u,
+		HttpStatusCode: code,
+		Ttfb:           ttfb,
+		Cached:         cached,
+	}
+}
+
+func TestSummary_Add(t *testing.T) {
+	s := NewSummary()
+	s.Add(newTestResult(t, "http://foo.bar/b", 200, 10*time.Millisecond, false))
+	s.Add(newTestResult(t, "http://foo.bar/b", 200, 0, true))
+	s.Add(newTestResult(t, "http://foo.bar/b", 500, 30*time.Millisecond, false))
+	s.Add(newTestResult(t, "http://foo.bar/a", 404, 20*time.Millisecond, false))
+
+	assert.Equal(t, []string{"http://foo.bar/a", "http://foo.bar/b"}, s.URLs())
+
+	b := s.Endpoints["http://foo.bar/b"]
+	assert.Equal(t, uint64(3), b.Requests)
+	assert.Equal(t, uint64(1), b.Errors)
+	assert.Equal(t, uint64(1), b.Cached)
+	assert.InDelta(t, 1.0/3, b.CachedRatio(), 0.0001)
+	assert.InDelta(t, 1.0/3, b.ErrorRate(), 0.0001)
+	assert.Equal(t, uint64(2), b.TTFB.Count())
+	assert.Equal(t, 10*time.Millisecond, b.TTFB.Min())
+	assert.Equal(t, map[int]uint64{200: 2, 500: 1}, b.StatusCodes)
+
+	assert.Equal(t, uint64(4), s.Total.Requests)
+	assert.Equal(t, uint64(2), s.Total.Errors)
+	assert.Equal(t, uint64(3), s.Total.TTFB.Count())
+	assert.Equal(t, []int{200, 404, 500}, s.Total.Codes())
+}
+
+func TestStats_Merge(t *testing.T) {
+	s1 := NewStats()
+	s1.Add(newTestResult(t, "http://foo.bar", 200, 10*time.Millisecond, false))
+
+	s2 := NewStats()
+	s2.Add(newTestResult(t, "http://foo.bar", 503, 20*time.Millisecond, false))
+	s2.Add(newTestResult(t, "http://foo.bar", 200, 0, true))
+
+	s1.Merge(s2)
+
+	assert.Equal(t, uint64(3), s1.Requests)
+	assert.Equal(t, uint64(1), s1.Errors)
+	assert.Equal(t, uint64(1), s1.Cached)
+	assert.Equal(t, uint64(2), s1.TTFB.Count())
+	assert.Equal(t, map[int]uint64{200: 2, 503: 1}, s1.StatusCodes)
+}
+
+func TestStats_EmptyRatios(t *testing.T) {
+	s := NewStats()
+
+	assert.Equal(t, float64(0), s.ErrorRate())
+	assert.Equal(t, float64(0), s.CachedRatio())
+}
+
+func TestSummary_Print(t *testing.T) {
+	s := NewSummary()
+	for i, wait := range []time.Duration{7 * time.Millisecond, 9 * time.Millisecond} {
+		r := newTestResult(t, "http://foo.bar", []int{200, 404}[i], time.Duration(i+1)*10*time.Millisecond, false)
+		r.Network.Wait = wait
+		s.Add(r)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Print(&buf))
+
+	out := buf.String()
+	assert.Contains(t, out, "P95")
+	assert.Contains(t, out, "http://foo.bar")
+	assert.Contains(t, out, "TOTAL")
+	assert.Contains(t, out, "200: 1, 404: 1")
+	assert.Contains(t, out, "10.0ms")
+	assert.Contains(t, out, "WAIT MEAN/P95")
+	assert.Contains(t, out, "8.0ms / 9.0ms")
+}
+
+func TestStats_Network(t *testing.T) {
+	s := NewStats()
+
+	r := newTestResult(t, "http://foo.bar", 200, 40*time.Millisecond, false)
+	r.Network = client.NetworkTiming{DNS: 5 * time.Millisecond, Connect: 10 * time.Millisecond, Wait: 25 * time.Millisecond}
+	s.Add(r)
+
+	// reused connection
+	r = newTestResult(t, "http://foo.bar", 200, 20*time.Millisecond, false)
+	r.Network = client.NetworkTiming{Wait: 20 * time.Millisecond}
+	s.Add(r)
+
+	// cached results and failed requests have no network phases
+	s.Add(newTestResult(t, "http://foo.bar", 200, 0, true))
+	s.Add(newTestResult(t, "http://foo.bar", 0, 0, false))
+
+	assert.Equal(t, uint64(2), s.Network.DNS.Count())
+	assert.Equal(t, 2500*time.Microsecond, s.Network.DNS.Mean())
+	assert.Equal(t, 5*time.Millisecond, s.Network.Connect.Mean())
+	assert.Equal(t, 22500*time.Microsecond, s.Network.Wait.Mean())
+
+	o := NewStats()
+	o.Merge(s)
+	assert.Equal(t, uint64(2), o.Network.Wait.Count())
+}
+
+func TestSummary_Subresources(t *testing.T) {
+	s := NewSummary()
+
+	r := newTestResult(t, "http://foo.bar", 200, 10*time.Millisecond, false)
+	r.Subresources = []*client.Subresource{
+		{URL: "http://foo.bar/app.js", HttpStatusCode: 200, Ttfb: 5 * time.Millisecond},
+		{URL: "http://foo.bar/api", HttpStatusCode: 200, Ttfb: 300 * time.Millisecond},
+		{URL: "http://foo.bar/img.png", Error: "net::ERR_FAILED"},
+	}
+	s.Add(r)
+	s.Add(r)
+
+	// subresources don't count as requests of the page
+	assert.Equal(t, uint64(2), s.Total.Requests)
+
+	require.Len(t, s.Subresources, 3)
+	assert.Equal(t, uint64(2), s.Subresources["http://foo.bar/api"].Requests)
+	assert.Equal(t, uint64(2), s.Subresources["http://foo.bar/img.png"].Errors)
+
+	assert.Equal(t, []string{"http://foo.bar/api", "http://foo.bar/app.js"}, s.SlowestSubresources(2))
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Print(&buf))
+	assert.Contains(t, buf.String(), "SLOWEST SUBRESOURCES")
+	assert.Contains(t, buf.String(), "http://foo.bar/img.png")
+}
+
+func TestSummary_SubresourcesBounded(t *testing.T) {
+	s := NewSummary()
+
+	r := newTestResult(t, "http://foo.bar", 200, 10*time.Millisecond, false)
+	r.Subresources = []*client.Subresource{
+		{URL: "http://foo.bar/app.js?v=1", HttpStatusCode: 200},
+		{URL: "http://foo.bar/app.js?v=2#main", HttpStatusCode: 200},
+	}
+	s.Add(r)
+
+	// queries don't make distinct subresources
+	require.Len(t, s.Subresources, 1)
+	assert.Equal(t, uint64(2), s.Subresources["http://foo.bar/app.js"].Requests)
+
+	r.Subresources = nil
+	for i := 0; i < maxSubresources+10; i++ {
+		r.Subresources = append(r.Subresources, &client.Subresource{
+			URL:            fmt.Sprintf("http://foo.bar/pixel/%d.gif", i),
+			HttpStatusCode: 200,
+		})
+	}
+	s.Add(r)
+
+	assert.Len(t, s.Subresources, maxSubresources)
+	assert.Equal(t, uint64(11), s.DroppedSubresources)
+
+	// known URLs are still counted
+	r.Subresources = []*client.Subresource{{URL: "http://foo.bar/app.js?v=3", HttpStatusCode: 200}}
+	s.Add(r)
+	assert.Equal(t, uint64(3), s.Subresources["http://foo.bar/app.js"].Requests)
+	assert.Equal(t, uint64(11), s.DroppedSubresources)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Print(&buf))
+	assert.Contains(t, buf.String(), "11 subresources of further URLs aren't counted")
+}
+
+func TestSummary_AddColdCache(t *testing.T) {
+	s := NewSummary()
+
+	r := newTestResult(t, "http://foo.bar", 200, 800*time.Millisecond, false)
+	r.ColdCache = true
+	s.Add(r)
+	s.Add(newTestResult(t, "http://foo.bar", 200, 200*time.Millisecond, true))
+
+	assert.Equal(t, uint64(1), s.Cold.Requests)
+	assert.Equal(t, 800*time.Millisecond, s.Cold.TTFB.Max())
+	assert.Equal(t, uint64(1), s.Warm.Requests)
+	assert.Equal(t, 1.0, s.Warm.CachedRatio())
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Print(&buf))
+	assert.Contains(t, buf.String(), "CACHE")
+}
+
+func TestSummary_AddJSErrors(t *testing.T) {
+	broken := func(messages ...string) *client.Result {
+		r := newTestResult(t, "http://foo.bar", 200, 100*time.Millisecond, false)
+		r.JSErrors = len(messages) + 1
+		r.JSErrorMessages = messages
+		return r
+	}
+
+	s := NewSummary()
+	s.Add(broken("Uncaught TypeError: cart is undefined", "request failed: 500"))
+	s.Add(broken("request failed: 500"))
+	s.Add(newTestResult(t, "http://foo.bar", 200, 100*time.Millisecond, false))
+
+	// pages with JavaScript errors still load successfully
+	assert.Equal(t, uint64(0), s.Total.Errors)
+	assert.Equal(t, uint64(5), s.Total.JSErrors)
+	assert.Equal(t, uint64(2), s.Total.JSErrorPages)
+	assert.InDelta(t, 2.0/3, s.Endpoints["http://foo.bar"].JSErrorRate(), 0.0001)
+	assert.Equal(t, []string{"request failed: 500"}, s.TopJSErrors(1))
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Print(&buf))
+	assert.Contains(t, buf.String(), "TOP JS ERRORS")
+	assert.Contains(t, buf.String(), "Uncaught TypeError: cart is undefined")
+}
+
+func TestSummary_AddFailures(t *testing.T) {
+	failed := func(class string) *client.Result {
+		r := newTestResult(t, "http://foo.bar", 0, 0, false)
+		r.Error = "can't load http://foo.bar"
+		r.ErrorClass = class
+		return r
+	}
+
+	s := NewSummary()
+	s.Add(failed("dns"))
+	s.Add(failed("timeout"))
+	s.Add(failed("timeout"))
+	s.Add(newTestResult(t, "http://foo.bar", 200, 100*time.Millisecond, false))
+
+	assert.Equal(t, uint64(3), s.Total.Errors)
+	assert.Equal(t, map[string]uint64{"dns": 1, "timeout": 2}, s.Total.Failures)
+	assert.Equal(t, []string{"dns", "timeout"}, s.Total.FailureClasses())
+
+	// failures have neither a TTFB nor a status code
+	assert.Equal(t, uint64(1), s.Total.TTFB.Count())
+	assert.Equal(t, map[int]uint64{200: 1}, s.Total.StatusCodes)
+
+	merged := NewStats()
+	merged.Merge(s.Total)
+	assert.Equal(t, s.Total.Failures, merged.Failures)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Print(&buf))
+	assert.Contains(t, buf.String(), "FAILURES")
+	assert.Contains(t, buf.String(), "dns: 1, timeout: 2")
+}
+
+func TestSummary_AddEvents(t *testing.T) {
+	s := NewSummary()
+	s.Add(&client.Result{Worker: "w1", User: 1, Event: "restart", Error: "browser closed unexpectedly"})
+	s.Add(&client.Result{Worker: "w1", User: 1, Event: "restart", Error: "browser doesn't respond"})
+	s.Add(newTestResult(t, "http://foo.bar", 200, 100*time.Millisecond, false))
+
+	// events aren't requests
+	assert.Equal(t, map[string]uint64{"restart": 2}, s.Events)
+	assert.Equal(t, map[string]uint64{"restart": 1}, s.EventUsers)
+	assert.Equal(t, uint64(1), s.Total.Requests)
+	assert.Zero(t, s.Total.Errors)
+	assert.Len(t, s.Endpoints, 1)
+
+	// users are distinct per worker
+	s.Add(&client.Result{Worker: "w2", User: 1, Event: "restart", Error: "browser closed unexpectedly"})
+	assert.Equal(t, map[string]uint64{"restart": 3}, s.Events)
+	assert.Equal(t, map[string]uint64{"restart": 2}, s.EventUsers)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Print(&buf))
+	assert.Contains(t, buf.String(), "EVENT")
+	assert.Contains(t, buf.String(), "restart  3      2")
+}
+
+func TestSummary_AddEmulations(t *testing.T) {
+	emulated := func(emulation string, ttfb time.Duration) *client.Result {
+		r := newTestResult(t, "http://foo.bar", 200, ttfb, false)
+		r.Emulation = emulation
+		return r
+	}
+
+	s := NewSummary()
+	s.Add(emulated("mobile", 300*time.Millisecond))
+	s.Add(emulated("mobile", 500*time.Millisecond))
+	s.Add(emulated("desktop", 100*time.Millisecond))
+	s.Add(newTestResult(t, "http://foo.bar", 200, 100*time.Millisecond, false))
+
+	assert.Equal(t, []string{"desktop", "mobile"}, s.EmulationNames())
+	assert.Equal(t, uint64(2), s.Emulations["mobile"].Requests)
+	assert.Equal(t, 300*time.Millisecond, s.Emulations["mobile"].TTFB.Min())
+	assert.Equal(t, uint64(1), s.Emulations["desktop"].Requests)
+	assert.Equal(t, uint64(4), s.Total.Requests)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Print(&buf))
+	assert.Contains(t, buf.String(), "EMULATION")
+	assert.Contains(t, buf.String(), "mobile")
+}
+
+func TestSummary_AddSteps(t *testing.T) {
+	step := func(name string, code int, d time.Duration, err string) *client.Result {
+		r := newTestResult(t, "http://foo.bar/cart", code, d/2, false)
+		r.Journey = "checkout"
+		r.Step = name
+		r.Duration = d
+		r.Error = err
+		return r
+	}
+
+	s := NewSummary()
+	s.Add(step("home", 200, 100*time.Millisecond, ""))
+	s.Add(step("buy", 0, 40*time.Millisecond, ""))
+	s.Add(step("pay", 0, 0, "step timed out"))
+	s.Add(step("home", 200, 300*time.Millisecond, ""))
+	s.Add(step("buy", 0, 0, "assertion failed"))
+
+	assert.Empty(t, s.Endpoints)
+	assert.Equal(t, []string{"checkout / home", "checkout / buy", "checkout / pay"}, s.StepKeys())
+
+	home := s.Steps[StepKey("checkout", "home")]
+	assert.Equal(t, uint64(2), home.Requests)
+	assert.Equal(t, uint64(0), home.Errors)
+	assert.Equal(t, uint64(2), home.TTFB.Count())
+	assert.Equal(t, 100*time.Millisecond, home.Duration.Min())
+
+	// steps without a document aren't errors, unless they failed
+	buy := s.Steps[StepKey("checkout", "buy")]
+	assert.Equal(t, uint64(2), buy.Requests)
+	assert.Equal(t, uint64(1), buy.Errors)
+	assert.Equal(t, uint64(0), buy.TTFB.Count())
+	assert.Equal(t, uint64(1), buy.Duration.Count())
+
+	assert.Equal(t, uint64(5), s.Total.Requests)
+	assert.Equal(t, uint64(2), s.Total.Errors)
+
+	var buf bytes.Buffer
+	require.NoError(t, s.Print(&buf))
+	assert.Contains(t, buf.String(), "JOURNEY / STEP")
+	assert.Contains(t, buf.String(), "checkout / pay")
+}
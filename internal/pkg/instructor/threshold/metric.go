@@ -0,0 +1,203 @@
+package threshold
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+)
+
+// unit is the unit of a metric value.
+type unit int
+
+const (
+	// unitDuration values are in milliseconds.
+	unitDuration unit = iota
+
+	// unitRatio values are between 0 and 1.
+	unitRatio
+
+	// unitCount values are plain amounts.
+	unitCount
+)
+
+// trend describes how a metric develops while results are added.
+type trend int
+
+const (
+	// trendNone metrics may increase and decrease.
+	trendNone trend = iota
+
+	// trendIncreasing metrics never decrease.
+	trendIncreasing
+
+	// trendDecreasing metrics never increase, once they have a value.
+	trendDecreasing
+)
+
+// metric reads a single value from statistics.
+type metric struct {
+	unit  unit
+	trend trend
+	value func(s *stats.Stats) float64
+
+	// sampled reports whether statistics contain samples of the metric.
+	// Nil means that the metric always has a value, i.e. a count.
+	sampled func(s *stats.Stats) bool
+}
+
+// ttfbSampled reports whether s contains TTFBs, which
+// are missing without results or for cached results.
+func ttfbSampled(s *stats.Stats) bool {
+	return s.TTFB.Count() > 0
+}
+
+// requestsSampled reports whether s contains results,
+// without any, ratios are meaningless.
+func requestsSampled(s *stats.Stats) bool {
+	return s.Requests > 0
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func quantile(q float64) metric {
+	return metric{
+		unit: unitDuration,
+		value: func(s *stats.Stats) float64 {
+			return milliseconds(s.TTFB.Quantile(q))
+		},
+		sampled: ttfbSampled,
+	}
+}
+
+// metrics contains every metric with a fixed name.
+var metrics = map[string]metric{
+	"min_ttfb": {
+		unit:    unitDuration,
+		trend:   trendDecreasing,
+		value:   func(s *stats.Stats) float64 { return milliseconds(s.TTFB.Min()) },
+		sampled: ttfbSampled,
+	},
+	"mean_ttfb": {
+		unit:    unitDuration,
+		value:   func(s *stats.Stats) float64 { return milliseconds(s.TTFB.Mean()) },
+		sampled: ttfbSampled,
+	},
+	"max_ttfb": {
+		unit:    unitDuration,
+		trend:   trendIncreasing,
+		value:   func(s *stats.Stats) float64 { return milliseconds(s.TTFB.Max()) },
+		sampled: ttfbSampled,
+	},
+	"p50_ttfb": quantile(0.50),
+	"p90_ttfb": quantile(0.90),
+	"p95_ttfb": quantile(0.95),
+	"p99_ttfb": quantile(0.99),
+	"error_rate": {
+		unit:    unitRatio,
+		value:   func(s *stats.Stats) float64 { return s.ErrorRate() },
+		sampled: requestsSampled,
+	},
+	"cached_ratio": {
+		unit:    unitRatio,
+		value:   func(s *stats.Stats) float64 { return s.CachedRatio() },
+		sampled: requestsSampled,
+	},
+	"requests": {
+		unit:  unitCount,
+		trend: trendIncreasing,
+		value: func(s *stats.Stats) float64 { return float64(s.Requests) },
+	},
+	"errors": {
+		unit:  unitCount,
+		trend: trendIncreasing,
+		value: func(s *stats.Stats) float64 { return float64(s.Errors) },
+	},
+}
+
+// lookupMetric returns the metric called name.
+// Besides the metrics with fixed names, status code counts are
+// supported either by code, i.e. "status_503", or by class, i.e. "status_5xx".
+func lookupMetric(name string) (metric, error) {
+	if m, ok := metrics[name]; ok {
+		return m, nil
+	}
+
+	if !strings.HasPrefix(name, "status_") {
+		return metric{}, fmt.Errorf("unknown metric '%s'", name)
+	}
+
+	code := strings.TrimPrefix(name, "status_")
+
+	if len(code) == 3 && strings.HasSuffix(code, "xx") && code[0] >= '1' && code[0] <= '5' {
+		class := int(code[0] - '0')
+
+		return metric{
+			unit:  unitCount,
+			trend: trendIncreasing,
+			value: func(s *stats.Stats) float64 {
+				var c uint64
+				for code, n := range s.StatusCodes {
+					if code/100 == class {
+						c += n
+					}
+				}
+				return float64(c)
+			},
+		}, nil
+	}
+
+	n, err := strconv.Atoi(code)
+	if err != nil || len(code) != 3 {
+		return metric{}, fmt.Errorf("unknown metric '%s'", name)
+	}
+
+	return metric{
+		unit:  unitCount,
+		trend: trendIncreasing,
+		value: func(s *stats.Stats) float64 { return float64(s.StatusCodes[n]) },
+	}, nil
+}
+
+// parseValue parses v in the unit u.
+// Durations are given with a unit suffix, i.e. "800ms", or as plain milliseconds.
+// Ratios are given as percentage, i.e. "1%", or as plain ratio between 0 and 1.
+func parseValue(v string, u unit) (float64, error) {
+	switch u {
+	case unitDuration:
+		if d, err := time.ParseDuration(v); err == nil {
+			return milliseconds(d), nil
+		}
+	case unitRatio:
+		if strings.HasSuffix(v, "%") {
+			f, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid percentage '%s'", v)
+			}
+			return f / 100, nil
+		}
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", v)
+	}
+
+	return f, nil
+}
+
+// formatValue formats v in the unit u.
+func formatValue(v float64, u unit) string {
+	switch u {
+	case unitDuration:
+		return fmt.Sprintf("%.1fms", v)
+	case unitRatio:
+		return stats.FormatPercent(v)
+	default:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+}
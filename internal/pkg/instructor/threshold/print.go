@@ -0,0 +1,32 @@
+package threshold
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// allName is the endpoint name of thresholds applying to all results.
+const allName = "ALL"
+
+// Print writes a human readable table of rs into w.
+func Print(w io.Writer, rs []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "THRESHOLD\tENDPOINT\tACTUAL\tRESULT")
+	for _, r := range rs {
+		endpoint := r.Endpoint
+		if endpoint == "" {
+			endpoint = allName
+		}
+
+		result := "passed"
+		if !r.Passed {
+			result = "FAILED"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Expression, endpoint, r.Actual, result)
+	}
+
+	return tw.Flush()
+}
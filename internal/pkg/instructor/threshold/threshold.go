@@ -0,0 +1,210 @@
+// Package threshold implements pass/fail criteria of a run, like
+// "p95_ttfb < 800ms", which are evaluated against the statistics
+// of all results or of a single endpoint.
+package threshold
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+	"github.com/dkorittki/loago/pkg/instructor/config"
+)
+
+// ErrInvalidExpression is returned, if a threshold expression can't be parsed.
+var ErrInvalidExpression = errors.New("invalid threshold expression")
+
+// operators ordered such that no operator is a prefix of a later one.
+var operators = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// Threshold is a single pass/fail criterion.
+type Threshold struct {
+	// Expression is the threshold as configured, i.e. "p95_ttfb < 800ms".
+	Expression string
+
+	// Endpoint is the URL the threshold applies to.
+	// An empty Endpoint applies to all results.
+	Endpoint string
+
+	metric metric
+	op     string
+	value  float64
+}
+
+// Parse parses expr of the form "<metric> <operator> <value>".
+//
+// Supported metrics are min_ttfb, mean_ttfb, max_ttfb, p50_ttfb, p90_ttfb,
+// p95_ttfb, p99_ttfb, error_rate, cached_ratio, requests, errors and
+// status code counts like status_503 or status_5xx.
+// Supported operators are <, <=, >, >=, == and !=.
+func Parse(expr string) (*Threshold, error) {
+	for _, op := range operators {
+		i := strings.Index(expr, op)
+		if i < 0 {
+			continue
+		}
+
+		name := strings.TrimSpace(expr[:i])
+		v := strings.TrimSpace(expr[i+len(op):])
+
+		m, err := lookupMetric(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w '%s': %v", ErrInvalidExpression, expr, err)
+		}
+
+		value, err := parseValue(v, m.unit)
+		if err != nil {
+			return nil, fmt.Errorf("%w '%s': %v", ErrInvalidExpression, expr, err)
+		}
+
+		return &Threshold{
+			Expression: strings.TrimSpace(expr),
+			metric:     m,
+			op:         op,
+			value:      value,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w '%s': missing operator", ErrInvalidExpression, expr)
+}
+
+// compare reports whether v satisfies the threshold.
+func (t *Threshold) compare(v float64) bool {
+	switch t.op {
+	case "<":
+		return v < t.value
+	case "<=":
+		return v <= t.value
+	case ">":
+		return v > t.value
+	case ">=":
+		return v >= t.value
+	case "==":
+		return v == t.value
+	default:
+		return v != t.value
+	}
+}
+
+// final reports whether a failed threshold can't pass anymore no matter
+// which results are added, given the trend of its metric and its value v.
+// Equality can still be reached, unless v moved past the threshold value.
+func (t *Threshold) final(v float64) bool {
+	switch t.metric.trend {
+	case trendIncreasing:
+		return t.op == "<" || t.op == "<=" || (t.op == "==" && v > t.value)
+	case trendDecreasing:
+		return t.op == ">" || t.op == ">=" || (t.op == "==" && v < t.value)
+	default:
+		return false
+	}
+}
+
+// Result is the outcome of evaluating a Threshold.
+type Result struct {
+	*Threshold
+
+	// Actual is the formatted value of the metric.
+	Actual string
+
+	// Passed is true, if the threshold is satisfied.
+	Passed bool
+
+	// Final is true, if the threshold failed and can't pass anymore.
+	Final bool
+
+	// Unsampled is true, if there are no samples of the metric yet,
+	// i.e. without any results. Such thresholds fail, since a run
+	// without results must not pass.
+	Unsampled bool
+}
+
+// noSamples is the actual value of thresholds, whose metric has no samples.
+const noSamples = "no samples"
+
+// Evaluate evaluates the threshold against s.
+func (t *Threshold) Evaluate(s *stats.Stats) Result {
+	if t.metric.sampled != nil && !t.metric.sampled(s) {
+		return Result{Threshold: t, Actual: noSamples, Unsampled: true}
+	}
+
+	v := t.metric.value(s)
+	r := Result{
+		Threshold: t,
+		Actual:    formatValue(v, t.metric.unit),
+		Passed:    t.compare(v),
+	}
+	r.Final = !r.Passed && t.final(v)
+
+	return r
+}
+
+// Set is a list of thresholds evaluated together.
+type Set []*Threshold
+
+// NewSet parses the global thresholds and the thresholds of every endpoint of cfg.
+func NewSet(cfg *config.InstructorConfig) (Set, error) {
+	var set Set
+
+	for _, expr := range cfg.Thresholds {
+		t, err := Parse(expr)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, t)
+	}
+
+	for _, e := range cfg.Endpoints {
+		// results carry the URL as parsed by the instructor,
+		// so use the same representation for lookups
+		u, err := url.Parse(e.Url)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint url '%s': %w", e.Url, err)
+		}
+
+		for _, expr := range e.Thresholds {
+			t, err := Parse(expr)
+			if err != nil {
+				return nil, err
+			}
+			t.Endpoint = u.String()
+			set = append(set, t)
+		}
+	}
+
+	return set, nil
+}
+
+// Evaluate evaluates every threshold against the matching statistics of s.
+// Thresholds of endpoints without any results are evaluated against empty statistics.
+func (set Set) Evaluate(s *stats.Summary) []Result {
+	results := make([]Result, 0, len(set))
+
+	for _, t := range set {
+		st := s.Total
+		if t.Endpoint != "" {
+			var ok bool
+			if st, ok = s.Endpoints[t.Endpoint]; !ok {
+				st = stats.NewStats()
+			}
+		}
+
+		results = append(results, t.Evaluate(st))
+	}
+
+	return results
+}
+
+// Failed returns the failed results of rs.
+func Failed(rs []Result) []Result {
+	var failed []Result
+	for _, r := range rs {
+		if !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,176 @@
+package threshold
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/dkorittki/loago/internal/pkg/instructor/client"
+	"github.com/dkorittki/loago/internal/pkg/instructor/stats"
+	"github.com/dkorittki/loago/pkg/instructor/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSummary(t *testing.T) *stats.Summary {
+	s := stats.NewSummary()
+
+	add := func(rawurl string, code int, ttfb time.Duration) {
+		u, err := url.Parse(rawurl)
+		require.NoError(t, err)
+		s.Add(&client.Result{URL: u, HttpStatusCode: code, Ttfb: ttfb})
+	}
+
+	for i := 0; i < 98; i++ {
+		add("http://foo.bar/a", 200, 100*time.Millisecond)
+	}
+	add("http://foo.bar/a", 503, 900*time.Millisecond)
+	add("http://foo.bar/b", 404, 50*time.Millisecond)
+
+	return s
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr  string
+		op    string
+		value float64
+	}{
+		{"p95_ttfb < 800ms", "<", 800},
+		{"p95_ttfb<=1s", "<=", 1000},
+		{"mean_ttfb > 250", ">", 250},
+		{"error_rate < 1%", "<", 0.01},
+		{"error_rate < 0.05", "<", 0.05},
+		{"status_5xx == 0", "==", 0},
+		{"status_404 != 3", "!=", 3},
+		{"requests >= 1000", ">=", 1000},
+	}
+
+	for _, tt := range tests {
+		th, err := Parse(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.op, th.op, tt.expr)
+		assert.InDelta(t, tt.value, th.value, 0.0001, tt.expr)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"p95_ttfb 800ms",
+		"p42_ttfb < 800ms",
+		"status_6xx == 0",
+		"status_abc == 0",
+		"error_rate < a%",
+		"p95_ttfb < fast",
+		"",
+	} {
+		_, err := Parse(expr)
+		assert.True(t, errors.Is(err, ErrInvalidExpression), expr)
+	}
+}
+
+func TestSet_Evaluate(t *testing.T) {
+	cfg := &config.InstructorConfig{
+		Thresholds: []string{
+			"p95_ttfb < 800ms",
+			"error_rate < 1%",
+			"status_5xx == 0",
+			"max_ttfb < 1s",
+		},
+		Endpoints: []*config.InstructorEndpoint{
+			{Url: "http://foo.bar/b", Thresholds: []string{"status_404 == 0"}},
+			{Url: "http://foo.bar/c", Thresholds: []string{"requests > 0"}},
+		},
+	}
+
+	set, err := NewSet(cfg)
+	require.NoError(t, err)
+	require.Len(t, set, 6)
+
+	results := set.Evaluate(newTestSummary(t))
+
+	passed := make([]bool, len(results))
+	final := make([]bool, len(results))
+	for i, r := range results {
+		passed[i] = r.Passed
+		final[i] = r.Final
+	}
+
+	assert.Equal(t, []bool{true, false, false, true, false, false}, passed)
+	assert.Equal(t, []bool{false, false, true, false, true, false}, final)
+	assert.Equal(t, "2.0%", results[1].Actual)
+	assert.Equal(t, "0", results[5].Actual)
+	assert.Equal(t, "http://foo.bar/b", results[4].Endpoint)
+	assert.Len(t, Failed(results), 4)
+}
+
+func TestThreshold_Evaluate_Equality(t *testing.T) {
+	s := newTestSummary(t)
+
+	// the summary has 100 results, more results may still reach 110
+	th, err := Parse("requests == 110")
+	require.NoError(t, err)
+	r := th.Evaluate(s.Total)
+	assert.False(t, r.Passed)
+	assert.False(t, r.Final)
+
+	// but they only move further away from 99
+	th, err = Parse("requests == 99")
+	require.NoError(t, err)
+	r = th.Evaluate(s.Total)
+	assert.False(t, r.Passed)
+	assert.True(t, r.Final)
+
+	// min_ttfb is 50ms, slower results never raise it to 100ms
+	th, err = Parse("min_ttfb == 100ms")
+	require.NoError(t, err)
+	assert.True(t, th.Evaluate(s.Total).Final)
+
+	th, err = Parse("min_ttfb == 10ms")
+	require.NoError(t, err)
+	assert.False(t, th.Evaluate(s.Total).Final)
+}
+
+func TestSet_Evaluate_NoSamples(t *testing.T) {
+	set, err := NewSet(&config.InstructorConfig{
+		Thresholds: []string{"p95_ttfb < 800ms", "error_rate < 1%", "requests == 0"},
+	})
+	require.NoError(t, err)
+
+	results := set.Evaluate(stats.NewSummary())
+
+	// metrics without samples fail, counts are zero
+	for _, r := range results[:2] {
+		assert.False(t, r.Passed, r.Expression)
+		assert.False(t, r.Final, r.Expression)
+		assert.True(t, r.Unsampled, r.Expression)
+		assert.Equal(t, "no samples", r.Actual)
+	}
+	assert.True(t, results[2].Passed)
+	assert.False(t, results[2].Unsampled)
+}
+
+func TestNewSet_Invalid(t *testing.T) {
+	_, err := NewSet(&config.InstructorConfig{
+		Endpoints: []*config.InstructorEndpoint{
+			{Url: "http://foo.bar", Thresholds: []string{"p95_ttfb <"}},
+		},
+	})
+
+	assert.True(t, errors.Is(err, ErrInvalidExpression))
+}
+
+func TestPrint(t *testing.T) {
+	set, err := NewSet(&config.InstructorConfig{Thresholds: []string{"error_rate < 1%"}})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Print(&buf, set.Evaluate(newTestSummary(t))))
+
+	assert.Equal(t,
+		"THRESHOLD        ENDPOINT  ACTUAL  RESULT\n"+
+			"error_rate < 1%  ALL       2.0%    FAILED\n",
+		buf.String())
+}
@@ -18,8 +18,29 @@ var (
 	// StatusText used in the simulated network event.
 	StatusText = "Testing OK"
 
+	// DNSStart value used in the simulated network event.
+	DNSStart = float64(5)
+
+	// DNSEnd value used in the simulated network event.
+	DNSEnd = float64(15)
+
 	// ConnectStart value used in the simulated network event.
-	ConnectStart = float64(60)
+	ConnectStart = float64(15)
+
+	// ConnectEnd value used in the simulated network event.
+	ConnectEnd = float64(60)
+
+	// SslStart value used in the simulated network event.
+	SslStart = float64(35)
+
+	// SslEnd value used in the simulated network event.
+	SslEnd = float64(60)
+
+	// SendStart value used in the simulated network event.
+	SendStart = float64(61)
+
+	// SendEnd value used in the simulated network event.
+	SendEnd = float64(62)
 
 	// ReceiveHeadersEnd value used in the simulated network event.
 	ReceiveHeadersEnd = float64(100)
@@ -57,7 +78,14 @@ func (e *EventTestExecutor) ListenTarget(ctx context.Context, fn func(ev interfa
 			Status:     Status,
 			StatusText: StatusText,
 			Timing: &network.ResourceTiming{
+				DNSStart:          DNSStart,
+				DNSEnd:            DNSEnd,
 				ConnectStart:      ConnectStart,
+				ConnectEnd:        ConnectEnd,
+				SslStart:          SslStart,
+				SslEnd:            SslEnd,
+				SendStart:         SendStart,
+				SendEnd:           SendEnd,
 				ReceiveHeadersEnd: ReceiveHeadersEnd,
 			},
 		},
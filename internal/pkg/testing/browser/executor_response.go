@@ -0,0 +1,45 @@
+package browser
+
+import (
+	"context"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/stretchr/testify/mock"
+)
+
+// ResponseTestExecutor implements the Executor interface and can be used in tests as a
+// testify mock object. It simulates a network event with Response while listening for a devtools target event.
+type ResponseTestExecutor struct {
+	mock.Mock
+
+	// Response of the simulated network event.
+	Response *network.Response
+}
+
+// NewResponseTestExecutor returns a new ResponseTestExecutor simulating a network event with res.
+func NewResponseTestExecutor(res *network.Response) *ResponseTestExecutor {
+	return &ResponseTestExecutor{Response: res}
+}
+
+// Run registers the method parameters and returns an error value declared by a testify mock setup.
+// This can be used to assert a correct method call.
+func (e *ResponseTestExecutor) Run(ctx context.Context, actions ...chromedp.Action) error {
+	args := e.Called(ctx, actions)
+	return args.Error(0)
+}
+
+// ListenTarget registers the method parameters, which can be asserted in unit tets.
+// It also generates a network event object containing Response to test correct event listening behaviour.
+func (e *ResponseTestExecutor) ListenTarget(ctx context.Context, fn func(ev interface{})) {
+	e.Called(ctx, fn)
+
+	// Simulate a network event
+	ev := &network.EventResponseReceived{
+		RequestID: "testing",
+		Type:      network.ResourceTypeDocument,
+		Response:  e.Response,
+	}
+
+	fn(ev)
+}
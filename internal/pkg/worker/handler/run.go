@@ -6,15 +6,19 @@ import (
 
 	loadtestservice "github.com/dkorittki/loago/internal/pkg/worker/service/loadtest"
 	"github.com/dkorittki/loago/pkg/api/v1"
+	"github.com/dkorittki/loago/pkg/worker/runner"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
 // Run handles incoming run requests. It starts a new loadtest
 // and sends the response results of the runners
 // as single messages via gRPC stream.
 // Closing the gRPC channel stops the load test and shuts all runners down.
+// If the request contains a duration, the stream is closed by the worker
+// once the loadtest finished.
 func (w *Worker) Run(req *api.RunRequest, srv api.Worker_RunServer) error {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -26,23 +30,42 @@ func (w *Worker) Run(req *api.RunRequest, srv api.Worker_RunServer) error {
 		close(r)
 	}()
 
-	minWait, maxWait, amount, browserType, endpoints, err := toServiceParams(req)
+	cfg, err := toServiceConfig(req)
 	if err != nil {
 		return err
 	}
+	cfg.Chrome = w.Chrome
+	cfg.Restarts = w.Restarts
 
 	s := loadtestservice.New()
 	go func() {
-		errChan <- s.Run(ctx, browserType, endpoints, minWait, maxWait, amount, r)
+		errChan <- s.Run(ctx, cfg, r)
 	}()
 
 	for {
 		select {
 		case err := <-errChan:
 			close(errChan)
-			return status.Error(codes.Aborted, err.Error())
+
+			if err != nil {
+				return status.Error(codes.Aborted, err.Error())
+			}
+
+			// The loadtest finished on its own, send the remaining results.
+			log.Info().
+				Str("component", "worker_handler").
+				Msg("loadtest finished")
+
+			for len(r) > 0 {
+				res := <-r
+				if err := srv.Send(toMessage(&res)); err != nil {
+					return err
+				}
+			}
+
+			return nil
 		case res := <-r:
-			err := srv.Send(toRPCResponse(&res))
+			err := srv.Send(toMessage(&res))
 			if err != nil {
 				errStatus, ok := status.FromError(err)
 				if !ok {
@@ -72,42 +95,285 @@ func (w *Worker) Run(req *api.RunRequest, srv api.Worker_RunServer) error {
 
 }
 
-// toServiceParams converts a gRPC API request data structure to seperate variables.
-func toServiceParams(req *api.RunRequest) (time.Duration, time.Duration, int,
-	loadtestservice.BrowserType, []*loadtestservice.Endpoint, error) {
-	minWait := time.Duration(req.MinWaitTime) * time.Millisecond
-	maxWait := time.Duration(req.MaxWaitTime) * time.Millisecond
-	amount := int(req.Amount)
+// toServiceConfig converts a gRPC API request data structure to a loadtest service config.
+func toServiceConfig(req *api.RunRequest) (*loadtestservice.Config, error) {
+	cfg := &loadtestservice.Config{
+		MinWait:      time.Duration(req.MinWaitTime) * time.Millisecond,
+		MaxWait:      time.Duration(req.MaxWaitTime) * time.Millisecond,
+		Amount:       int(req.Amount),
+		Duration:     time.Duration(req.Duration) * time.Millisecond,
+		Subresources: req.Subresources,
+		Cookies:      req.Cookies,
+	}
 
-	var browserType loadtestservice.BrowserType
 	switch req.Type {
 	case api.RunRequest_FAKE:
-		browserType = loadtestservice.BrowserTypeFake
+		cfg.BrowserType = loadtestservice.BrowserTypeFake
 	case api.RunRequest_CHROME:
-		browserType = loadtestservice.BrowserTypeChrome
+		cfg.BrowserType = loadtestservice.BrowserTypeChrome
+	case api.RunRequest_HTTP:
+		cfg.BrowserType = loadtestservice.BrowserTypeHTTP
 	default:
-		return 0, 0, 0, 0, nil, ErrUnknownBrowser
+		return nil, ErrUnknownBrowser
 	}
 
-	var endpoints []*loadtestservice.Endpoint
 	for _, v := range req.Endpoints {
 		e := &loadtestservice.Endpoint{
 			URL:    v.Url,
 			Weight: uint(v.Weight),
+			Options: runner.RequestOptions{
+				Headers:    v.Headers,
+				UserAgent:  v.UserAgent,
+				Username:   v.Username,
+				Password:   v.Password,
+				Timeout:    timeout(v.Timeout, req.Timeout),
+				OriginOnly: v.OriginOnly,
+			},
+		}
+		cfg.Endpoints = append(cfg.Endpoints, e)
+	}
+
+	for _, v := range req.Journeys {
+		j := &loadtestservice.Journey{
+			Name:   v.Name,
+			Weight: uint(v.Weight),
+		}
+
+		for _, s := range v.Steps {
+			st, err := toStep(s, req.Timeout)
+			if err != nil {
+				return nil, err
+			}
+			j.Steps = append(j.Steps, st)
+		}
+
+		cfg.Journeys = append(cfg.Journeys, j)
+	}
+
+	for _, s := range req.Setup {
+		st, err := toStep(s, req.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Setup = append(cfg.Setup, st)
+	}
+
+	if d := req.UserData; d != nil && len(d.Rows) > 0 {
+		cfg.UserData = &loadtestservice.UserData{Columns: d.Columns}
+		for _, r := range d.Rows {
+			cfg.UserData.Rows = append(cfg.UserData.Rows, r.Values)
 		}
-		endpoints = append(endpoints, e)
 	}
 
-	return minWait, maxWait, amount, browserType, endpoints, nil
+	cfg.ColdVisits = int(req.ColdVisits)
+	cfg.ChromeProcesses = int(req.ChromeProcesses)
+
+	if a := req.Artifacts; a != nil {
+		cfg.Artifacts = &loadtestservice.Artifacts{
+			HTML:    a.Html,
+			Max:     int(a.Max),
+			MaxSize: int(a.MaxSize),
+		}
+	}
+
+	if h := req.Har; h != nil {
+		cfg.HAR = &loadtestservice.HARSampling{
+			First:  int(h.First),
+			Slower: time.Duration(h.Slower) * time.Millisecond,
+			Max:    int(h.Max),
+		}
+	}
+
+	for _, v := range req.Emulations {
+		cfg.Emulations = append(cfg.Emulations, &loadtestservice.Emulation{
+			Weight: uint(v.Weight),
+			Emulation: runner.Emulation{
+				Name:               v.Name,
+				Width:              int64(v.Width),
+				Height:             int64(v.Height),
+				DeviceScaleFactor:  v.DeviceScaleFactor,
+				Mobile:             v.Mobile,
+				UserAgent:          v.UserAgent,
+				CPUThrottling:      v.CpuThrottling,
+				Latency:            time.Duration(v.Latency) * time.Millisecond,
+				DownloadThroughput: int64(v.DownloadThroughput),
+				UploadThroughput:   int64(v.UploadThroughput),
+			},
+		})
+	}
+
+	for _, v := range req.Stages {
+		st := &loadtestservice.Stage{
+			Duration: time.Duration(v.Duration) * time.Millisecond,
+			Target:   int(v.Target),
+		}
+
+		switch v.Ramp {
+		case api.RunRequest_Stage_LINEAR:
+			st.Ramp = loadtestservice.RampLinear
+		case api.RunRequest_Stage_STEP:
+			st.Ramp = loadtestservice.RampStep
+		default:
+			return nil, ErrUnknownRamp
+		}
+
+		cfg.Stages = append(cfg.Stages, st)
+	}
+
+	return cfg, nil
+}
+
+// stepActions maps journey step actions of the gRPC API to runner step actions.
+var stepActions = map[api.RunRequest_Journey_Step_Action]runner.StepAction{
+	api.RunRequest_Journey_Step_NAVIGATE:          runner.StepNavigate,
+	api.RunRequest_Journey_Step_CLICK:             runner.StepClick,
+	api.RunRequest_Journey_Step_TYPE:              runner.StepType,
+	api.RunRequest_Journey_Step_SUBMIT:            runner.StepSubmit,
+	api.RunRequest_Journey_Step_WAIT:              runner.StepWait,
+	api.RunRequest_Journey_Step_SLEEP:             runner.StepSleep,
+	api.RunRequest_Journey_Step_ASSERT_TEXT:       runner.StepAssertText,
+	api.RunRequest_Journey_Step_SET_COOKIE:        runner.StepSetCookie,
+	api.RunRequest_Journey_Step_SET_LOCAL_STORAGE: runner.StepSetLocalStorage,
+}
+
+// toStep converts a gRPC API journey step to a loadtest service step.
+// Navigation steps without a timeout use the navigation timeout of the request.
+func toStep(s *api.RunRequest_Journey_Step, navigationTimeout uint32) (*loadtestservice.Step, error) {
+	action, ok := stepActions[s.Action]
+	if !ok {
+		return nil, ErrUnknownStepAction
+	}
+
+	t := time.Duration(s.Timeout) * time.Millisecond
+	if action == runner.StepNavigate {
+		t = timeout(s.Timeout, navigationTimeout)
+	}
+
+	return &loadtestservice.Step{
+		Name: s.Name,
+		Step: runner.Step{
+			Action:   action,
+			URL:      s.Url,
+			Selector: s.Selector,
+			Key:      s.Key,
+			Value:    s.Value,
+			Duration: time.Duration(s.Duration) * time.Millisecond,
+			Timeout:  t,
+		},
+	}, nil
+}
+
+// timeout returns the timeout t in milliseconds,
+// or the default timeout d, if t is zero.
+func timeout(t, d uint32) time.Duration {
+	if t == 0 {
+		t = d
+	}
+
+	return time.Duration(t) * time.Millisecond
 }
 
 // toRPCResponse converts a service endpoint result data structure to an gRPC API endpointresult
 func toRPCResponse(res *loadtestservice.EndpointResult) *api.EndpointResult {
-	return &api.EndpointResult{
+	r := &api.EndpointResult{
+		User:              int32(res.User),
 		Url:               res.URL,
 		HttpStatusCode:    int32(res.HTTPStatusCode),
 		HttpStatusMessage: res.HTTPStatusMessage,
 		Ttfb:              int32(res.TTFB / time.Millisecond),
 		Cached:            res.Cached,
+
+		DomContentLoaded:       int32(res.Timing.DOMContentLoaded / time.Millisecond),
+		Load:                   int32(res.Timing.Load / time.Millisecond),
+		FirstPaint:             int32(res.Timing.FirstPaint / time.Millisecond),
+		FirstContentfulPaint:   int32(res.Timing.FirstContentfulPaint / time.Millisecond),
+		LargestContentfulPaint: int32(res.Timing.LargestContentfulPaint / time.Millisecond),
+		CumulativeLayoutShift:  float32(res.Timing.CumulativeLayoutShift),
+
+		Dns:     int32(res.Network.DNS / time.Millisecond),
+		Connect: int32(res.Network.Connect / time.Millisecond),
+		Ssl:     int32(res.Network.SSL / time.Millisecond),
+		Send:    int32(res.Network.Send / time.Millisecond),
+		Wait:    int32(res.Network.Wait / time.Millisecond),
+
+		Journey:    res.Journey,
+		Step:       res.Step,
+		Duration:   int32(res.Duration / time.Millisecond),
+		Error:      res.Error,
+		ErrorClass: string(res.ErrorClass),
+		Emulation:  res.Emulation,
+		ColdCache:  res.ColdCache,
+		Event:      res.Event,
+		Har:        res.HAR,
+
+		JsErrors:        int32(res.JSErrors),
+		JsErrorMessages: res.JSErrorMessages,
+	}
+
+	for _, s := range res.Subresources {
+		r.Subresources = append(r.Subresources, &api.EndpointResult_Subresource{
+			Url:               s.URL,
+			Type:              s.Type,
+			HttpStatusCode:    int32(s.StatusCode),
+			HttpStatusMessage: s.StatusMessage,
+			Ttfb:              int32(s.TTFB / time.Millisecond),
+			Size:              s.Size,
+			Cached:            s.Cached,
+			Initiator:         s.Initiator,
+			InitiatorUrl:      s.InitiatorURL,
+			Error:             s.Error,
+		})
+	}
+
+	if a := res.Artifact; a != nil {
+		r.Artifact = &api.EndpointResult_Artifact{
+			Url:        a.URL,
+			Screenshot: a.Screenshot,
+			Html:       a.HTML,
+		}
+	}
+
+	return r
+}
+
+// toMessage converts res to the gRPC API, fitting into a single message.
+func toMessage(res *loadtestservice.EndpointResult) *api.EndpointResult {
+	r := toRPCResponse(res)
+	fitMessage(r, api.MaxMessageSize)
+
+	return r
+}
+
+// fitMessage drops the HAR, the artifact and the subresources of r in this order,
+// until r fits into a gRPC message of max bytes. Each of them is bounded on its
+// own, but together they may exceed the limit, which would fail the stream.
+func fitMessage(r *api.EndpointResult, max int) {
+	size := proto.Size(r)
+	if size <= max {
+		return
+	}
+
+	drops := []struct {
+		name string
+		drop func()
+	}{
+		{"har", func() { r.Har = nil }},
+		{"artifact", func() { r.Artifact = nil }},
+		{"subresources", func() { r.Subresources = nil }},
+	}
+
+	for _, d := range drops {
+		d.drop()
+
+		log.Warn().
+			Str("component", "worker_handler").
+			Str("url", r.Url).
+			Int("size", size).
+			Str("dropped", d.name).
+			Msg("result exceeds the message size limit")
+
+		if size = proto.Size(r); size <= max {
+			return
+		}
 	}
 }
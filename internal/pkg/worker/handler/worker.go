@@ -2,6 +2,8 @@
 package handler
 
 import (
+	loadtestservice "github.com/dkorittki/loago/internal/pkg/worker/service/loadtest"
+	"github.com/dkorittki/loago/pkg/worker/runner"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -12,10 +14,24 @@ const ResultBufferSize = 1000
 var (
 	// ErrUnknownBrowser indicates an error when an unknown browser type is given.
 	ErrUnknownBrowser = status.Error(codes.InvalidArgument, "unknown browser type in request")
+
+	// ErrUnknownRamp indicates an error when an unknown stage ramp is given.
+	ErrUnknownRamp = status.Error(codes.InvalidArgument, "unknown stage ramp in request")
+
+	// ErrUnknownStepAction indicates an error when an unknown journey step action is given.
+	ErrUnknownStepAction = status.Error(codes.InvalidArgument, "unknown journey step action in request")
 )
 
 // Worker implements the gRPC worker service handler.
-type Worker struct{}
+type Worker struct {
+	// Chrome configures how chrome processes of loadtests are launched.
+	// If nil, chrome is launched with the default configuration.
+	Chrome *runner.ChromeConfig
+
+	// Restarts controls how chrome runners with a crashed or wedged browser
+	// are replaced. The zero value disables restarts.
+	Restarts loadtestservice.RestartPolicy
+}
 
 // NewWorker returns a new Worker.
 func NewWorker() *Worker {
@@ -3,7 +3,9 @@ package handler
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -15,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/dkorittki/loago/pkg/api/v1"
+	"github.com/dkorittki/loago/pkg/worker/runner"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc/metadata"
 )
@@ -145,7 +148,7 @@ func TestInvalidBrowserType(t *testing.T) {
 			},
 		},
 		Amount:      1,
-		Type:        2,
+		Type:        3,
 		MinWaitTime: 2000,
 		MaxWaitTime: 1000,
 	}
@@ -232,3 +235,345 @@ func TestGRPCErrorOnSend(t *testing.T) {
 	assert.Len(t, srv.results, 1)
 	assert.Equal(t, sendErr, err)
 }
+
+func TestWorker_RunDuration(t *testing.T) {
+	srv := &RunServerMock{}
+	srv.On("Send", mock.Anything).Return(nil)
+
+	req := &api.RunRequest{
+		Endpoints: []*api.RunRequest_Endpoint{
+			{
+				Url:    "http://foo.bar",
+				Weight: 1,
+			},
+		},
+		Amount:      1,
+		Type:        api.RunRequest_FAKE,
+		MinWaitTime: 1000,
+		MaxWaitTime: 1000,
+		Duration:    2500,
+	}
+
+	h := NewWorker()
+	err := h.Run(req, srv)
+
+	assert.NoError(t, err)
+	assert.Len(t, srv.results, 2)
+}
+
+func TestToServiceConfig_Stages(t *testing.T) {
+	req := &api.RunRequest{
+		Endpoints: []*api.RunRequest_Endpoint{
+			{
+				Url:    "http://foo.bar",
+				Weight: 1,
+			},
+		},
+		Amount:      10,
+		Type:        api.RunRequest_FAKE,
+		MinWaitTime: 1000,
+		MaxWaitTime: 2000,
+		Stages: []*api.RunRequest_Stage{
+			{Duration: 120000, Target: 10, Ramp: api.RunRequest_Stage_LINEAR},
+			{Duration: 60000, Target: 0, Ramp: api.RunRequest_Stage_STEP},
+		},
+	}
+
+	cfg, err := toServiceConfig(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, loadtest.BrowserTypeFake, cfg.BrowserType)
+	assert.Equal(t, time.Second, cfg.MinWait)
+	assert.Equal(t, 2*time.Second, cfg.MaxWait)
+	assert.Equal(t, []*loadtest.Stage{
+		{Duration: 2 * time.Minute, Target: 10, Ramp: loadtest.RampLinear},
+		{Duration: time.Minute, Target: 0, Ramp: loadtest.RampStep},
+	}, cfg.Stages)
+
+	req.Stages[0].Ramp = 5
+	_, err = toServiceConfig(req)
+	assert.Equal(t, ErrUnknownRamp, err)
+}
+
+func TestToServiceConfig_HTTP(t *testing.T) {
+	req := &api.RunRequest{
+		Endpoints: []*api.RunRequest_Endpoint{
+			{
+				Url:    "http://foo.bar",
+				Weight: 1,
+			},
+		},
+		Amount:      2000,
+		Type:        api.RunRequest_HTTP,
+		MinWaitTime: 1000,
+		MaxWaitTime: 2000,
+		Cookies:     true,
+	}
+
+	require.NoError(t, req.Validate())
+
+	cfg, err := toServiceConfig(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, loadtest.BrowserTypeHTTP, cfg.BrowserType)
+	assert.Equal(t, 2000, cfg.Amount)
+	assert.True(t, cfg.Cookies)
+}
+
+func TestToServiceConfig_Timeout(t *testing.T) {
+	req := &api.RunRequest{
+		Endpoints: []*api.RunRequest_Endpoint{
+			{Url: "http://foo.bar", Weight: 1},
+			{Url: "http://foo.bar/search", Weight: 1, Timeout: 30000},
+		},
+		Journeys: []*api.RunRequest_Journey{
+			{
+				Name:   "checkout",
+				Weight: 1,
+				Steps: []*api.RunRequest_Journey_Step{
+					{Name: "home", Action: api.RunRequest_Journey_Step_NAVIGATE, Url: "http://foo.bar"},
+					{Name: "buy", Action: api.RunRequest_Journey_Step_CLICK, Selector: "#buy"},
+				},
+			},
+		},
+		Amount:      1,
+		Type:        api.RunRequest_CHROME,
+		MinWaitTime: 1000,
+		MaxWaitTime: 2000,
+		Timeout:     10000,
+	}
+
+	require.NoError(t, req.Validate())
+
+	cfg, err := toServiceConfig(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, 10*time.Second, cfg.Endpoints[0].Options.Timeout)
+	assert.Equal(t, 30*time.Second, cfg.Endpoints[1].Options.Timeout)
+
+	// only navigation steps load pages
+	assert.Equal(t, 10*time.Second, cfg.Journeys[0].Steps[0].Timeout)
+	assert.Zero(t, cfg.Journeys[0].Steps[1].Timeout)
+}
+
+func TestToServiceConfig_HAR(t *testing.T) {
+	req := &api.RunRequest{
+		Endpoints:   []*api.RunRequest_Endpoint{{Url: "http://foo.bar", Weight: 1}},
+		Amount:      1,
+		Type:        api.RunRequest_CHROME,
+		MinWaitTime: 1000,
+		MaxWaitTime: 2000,
+		Har:         &api.RunRequest_HAR{First: 5, Slower: 3000, Max: 100},
+	}
+
+	require.NoError(t, req.Validate())
+
+	cfg, err := toServiceConfig(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, &loadtest.HARSampling{First: 5, Slower: 3 * time.Second, Max: 100}, cfg.HAR)
+
+	req.Har = nil
+	cfg, err = toServiceConfig(req)
+	require.NoError(t, err)
+	assert.Nil(t, cfg.HAR)
+}
+
+func TestToRPCResponse(t *testing.T) {
+	res := toRPCResponse(&loadtest.EndpointResult{
+		URL:            "http://foo.bar",
+		HTTPStatusCode: 200,
+		TTFB:           40 * time.Millisecond,
+		Timing: runner.PageTiming{
+			DOMContentLoaded:       120 * time.Millisecond,
+			Load:                   250 * time.Millisecond,
+			FirstPaint:             80 * time.Millisecond,
+			FirstContentfulPaint:   90 * time.Millisecond,
+			LargestContentfulPaint: 180 * time.Millisecond,
+			CumulativeLayoutShift:  0.25,
+		},
+		Network: runner.NetworkTiming{
+			DNS:     2 * time.Millisecond,
+			Connect: 8 * time.Millisecond,
+			SSL:     12 * time.Millisecond,
+			Send:    time.Millisecond,
+			Wait:    17 * time.Millisecond,
+		},
+		Subresources: []*runner.Subresource{
+			{URL: "http://foo.bar/app.js", Type: "Script", StatusCode: 200, TTFB: 5 * time.Millisecond, Size: 512},
+		},
+		JSErrors:        3,
+		JSErrorMessages: []string{"Uncaught TypeError: cart is undefined"},
+		HAR:             []byte(`{"log":{"version":"1.2"}}`),
+	})
+
+	assert.Equal(t, int32(40), res.Ttfb)
+	assert.Equal(t, int32(120), res.DomContentLoaded)
+	assert.Equal(t, int32(250), res.Load)
+	assert.Equal(t, int32(80), res.FirstPaint)
+	assert.Equal(t, int32(90), res.FirstContentfulPaint)
+	assert.Equal(t, int32(180), res.LargestContentfulPaint)
+	assert.Equal(t, float32(0.25), res.CumulativeLayoutShift)
+	assert.Equal(t, int32(2), res.Dns)
+	assert.Equal(t, int32(8), res.Connect)
+	assert.Equal(t, int32(12), res.Ssl)
+	assert.Equal(t, int32(1), res.Send)
+	assert.Equal(t, int32(17), res.Wait)
+
+	if assert.Len(t, res.Subresources, 1) {
+		assert.Equal(t, "http://foo.bar/app.js", res.Subresources[0].Url)
+		assert.Equal(t, "Script", res.Subresources[0].Type)
+		assert.Equal(t, int32(200), res.Subresources[0].HttpStatusCode)
+		assert.Equal(t, int32(5), res.Subresources[0].Ttfb)
+		assert.Equal(t, int64(512), res.Subresources[0].Size)
+	}
+
+	assert.Equal(t, int32(3), res.JsErrors)
+	assert.Equal(t, []string{"Uncaught TypeError: cart is undefined"}, res.JsErrorMessages)
+	assert.Equal(t, []byte(`{"log":{"version":"1.2"}}`), res.Har)
+}
+
+func TestToServiceConfig_Journeys(t *testing.T) {
+	req := &api.RunRequest{
+		Amount:      1,
+		Type:        api.RunRequest_CHROME,
+		MinWaitTime: 1000,
+		MaxWaitTime: 2000,
+		Journeys: []*api.RunRequest_Journey{
+			{
+				Name:   "checkout",
+				Weight: 2,
+				Steps: []*api.RunRequest_Journey_Step{
+					{Name: "home", Action: api.RunRequest_Journey_Step_NAVIGATE, Url: "http://foo.bar"},
+					{Name: "email", Action: api.RunRequest_Journey_Step_TYPE, Selector: "#email", Value: "foo@bar"},
+					{Name: "read", Action: api.RunRequest_Journey_Step_SLEEP, Duration: 1500},
+					{Name: "done", Action: api.RunRequest_Journey_Step_WAIT, Selector: "#done", Timeout: 5000},
+				},
+			},
+		},
+	}
+
+	require.NoError(t, req.Validate())
+
+	cfg, err := toServiceConfig(req)
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Journeys, 1)
+	assert.Equal(t, "checkout", cfg.Journeys[0].Name)
+	assert.Equal(t, uint(2), cfg.Journeys[0].Weight)
+	assert.Equal(t, []*loadtest.Step{
+		{Name: "home", Step: runner.Step{Action: runner.StepNavigate, URL: "http://foo.bar"}},
+		{Name: "email", Step: runner.Step{Action: runner.StepType, Selector: "#email", Value: "foo@bar"}},
+		{Name: "read", Step: runner.Step{Action: runner.StepSleep, Duration: 1500 * time.Millisecond}},
+		{Name: "done", Step: runner.Step{Action: runner.StepWait, Selector: "#done", Timeout: 5 * time.Second}},
+	}, cfg.Journeys[0].Steps)
+
+	req.Journeys[0].Steps[0].Action = 42
+	_, err = toServiceConfig(req)
+	assert.Equal(t, ErrUnknownStepAction, err)
+}
+
+func TestToRPCResponse_Step(t *testing.T) {
+	res := toRPCResponse(&loadtest.EndpointResult{
+		URL:      "http://foo.bar/cart",
+		Journey:  "checkout",
+		Step:     "buy",
+		Duration: 350 * time.Millisecond,
+		Error:    runner.ErrStepTimeout.Error(),
+		Artifact: &runner.Artifact{URL: "http://foo.bar/cart", Screenshot: []byte{0xff, 0xd8}},
+	})
+
+	assert.Equal(t, "http://foo.bar/cart", res.Url)
+	assert.Equal(t, "checkout", res.Journey)
+	assert.Equal(t, "buy", res.Step)
+	assert.Equal(t, int32(350), res.Duration)
+	assert.Equal(t, "step timed out", res.Error)
+	assert.Equal(t, &api.EndpointResult_Artifact{
+		Url:        "http://foo.bar/cart",
+		Screenshot: []byte{0xff, 0xd8},
+	}, res.Artifact)
+}
+
+func TestToRPCResponse_Failure(t *testing.T) {
+	res := toRPCResponse(&loadtest.EndpointResult{
+		URL:        "http://foo.bar",
+		Error:      "can't load http://foo.bar: net::ERR_NAME_NOT_RESOLVED",
+		ErrorClass: runner.ErrorClassDNS,
+	})
+
+	assert.Equal(t, "http://foo.bar", res.Url)
+	assert.Equal(t, "can't load http://foo.bar: net::ERR_NAME_NOT_RESOLVED", res.Error)
+	assert.Equal(t, "dns", res.ErrorClass)
+	assert.Zero(t, res.HttpStatusCode)
+}
+
+func TestToRPCResponse_Event(t *testing.T) {
+	res := toRPCResponse(&loadtest.EndpointResult{
+		User:  3,
+		Event: loadtest.EventRestart,
+		Error: "browser closed unexpectedly",
+	})
+
+	assert.Equal(t, int32(3), res.User)
+	assert.Equal(t, "restart", res.Event)
+	assert.Equal(t, "browser closed unexpectedly", res.Error)
+	assert.Empty(t, res.Url)
+}
+
+func TestFitMessage(t *testing.T) {
+	r := &api.EndpointResult{
+		Url:          "http://foo.bar",
+		Har:          make([]byte, 1000),
+		Artifact:     &api.EndpointResult_Artifact{Html: strings.Repeat("a", 1000)},
+		Subresources: []*api.EndpointResult_Subresource{{Url: "http://foo.bar/app.js"}},
+	}
+
+	// results within the limit stay untouched
+	fitMessage(r, 10000)
+	assert.NotNil(t, r.Har)
+	assert.NotNil(t, r.Artifact)
+
+	// the HAR is dropped first
+	fitMessage(r, 1500)
+	assert.Nil(t, r.Har)
+	assert.NotNil(t, r.Artifact)
+	assert.Len(t, r.Subresources, 1)
+
+	fitMessage(r, 100)
+	assert.Nil(t, r.Artifact)
+	assert.Len(t, r.Subresources, 1)
+	assert.Equal(t, "http://foo.bar", r.Url)
+}
+
+func TestToServiceConfig_Setup(t *testing.T) {
+	req := &api.RunRequest{
+		Endpoints:   []*api.RunRequest_Endpoint{{Url: "http://foo.bar/account", Weight: 1}},
+		Amount:      2,
+		Type:        api.RunRequest_CHROME,
+		MinWaitTime: 1000,
+		MaxWaitTime: 2000,
+		Setup: []*api.RunRequest_Journey_Step{
+			{Name: "token", Action: api.RunRequest_Journey_Step_SET_LOCAL_STORAGE, Key: "token", Value: "${token}"},
+		},
+		UserData: &api.RunRequest_UserData{
+			Columns: []string{"user", "token"},
+			Rows: []*api.RunRequest_UserData_Row{
+				{Values: []string{"jane", "t1"}},
+				{Values: []string{"john", "t2"}},
+			},
+		},
+	}
+
+	require.NoError(t, req.Validate())
+
+	cfg, err := toServiceConfig(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*loadtest.Step{
+		{Name: "token", Step: runner.Step{Action: runner.StepSetLocalStorage, Key: "token", Value: "${token}"}},
+	}, cfg.Setup)
+	assert.Equal(t, &loadtest.UserData{
+		Columns: []string{"user", "token"},
+		Rows:    [][]string{{"jane", "t1"}, {"john", "t2"}},
+	}, cfg.UserData)
+}
@@ -7,7 +7,9 @@ import (
 	"net"
 
 	"github.com/dkorittki/loago/internal/pkg/worker/handler"
+	loadtestservice "github.com/dkorittki/loago/internal/pkg/worker/service/loadtest"
 	"github.com/dkorittki/loago/pkg/api/v1"
+	"github.com/dkorittki/loago/pkg/worker/runner"
 	grpcmiddleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpcauth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
 	grpcvalidator "github.com/grpc-ecosystem/go-grpc-middleware/validator"
@@ -36,6 +38,14 @@ type Config struct {
 	// ListenAdress contains the interface ip and port to listen on,
 	// i.e. "127.0.0.1:50051".
 	ListenAdress string
+
+	// Chrome configures how chrome processes are launched.
+	// If nil, chrome is launched with the default configuration.
+	Chrome *runner.ChromeConfig
+
+	// Restarts controls how chrome runners with a crashed or wedged browser
+	// are replaced.
+	Restarts loadtestservice.RestartPolicy
 }
 
 // WorkerServer is a server for handling worker gRPC requests.
@@ -66,7 +76,11 @@ func NewWorkerServer(cfg Config) (*WorkerServer, error) {
 		}
 	}
 
-	return newWorkerServer(&cert, cfg.Secret, handler.NewWorker(), lis)
+	w := handler.NewWorker()
+	w.Chrome = cfg.Chrome
+	w.Restarts = cfg.Restarts
+
+	return newWorkerServer(&cert, cfg.Secret, w, lis)
 }
 
 func newWorkerServer(cert *tls.Certificate, secret string, handler api.WorkerServer,
@@ -74,7 +88,10 @@ func newWorkerServer(cert *tls.Certificate, secret string, handler api.WorkerSer
 	s := &WorkerServer{}
 	s.listener = listener
 
-	var opts []grpc.ServerOption
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(api.MaxMessageSize),
+		grpc.MaxSendMsgSize(api.MaxMessageSize),
+	}
 
 	if cert != nil && len(cert.Certificate) != 0 {
 		opts = append(opts, grpc.Creds(credentials.NewServerTLSFromCert(cert)))
@@ -0,0 +1,62 @@
+package loadtest
+
+import (
+	"context"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/dkorittki/loago/pkg/worker/runner"
+	"github.com/rs/zerolog/log"
+)
+
+// artifactBudget captures artifacts for every runner of a loadtest,
+// until cfg.Max artifacts are captured.
+type artifactBudget struct {
+	cfg *Artifacts
+
+	// captured counts the captures, including failed ones.
+	captured int32
+}
+
+func newArtifactBudget(cfg *Artifacts) *artifactBudget {
+	if cfg == nil || cfg.Max <= 0 {
+		return nil
+	}
+
+	return &artifactBudget{cfg: cfg}
+}
+
+// capture captures the page of the runner of ctx, unless the budget is spent.
+// It returns nil, if there is no budget or capturing failed.
+func (b *artifactBudget) capture(ctx context.Context) *runner.Artifact {
+	if b == nil || atomic.AddInt32(&b.captured, 1) > int32(b.cfg.Max) {
+		return nil
+	}
+
+	a, err := runner.Capture(ctx, b.cfg.HTML)
+	if err != nil {
+		log.Debug().
+			Str("component", "schedule").
+			Err(err).
+			Msg("can't capture artifact")
+
+		return nil
+	}
+
+	if b.cfg.MaxSize > 0 {
+		if len(a.Screenshot) > b.cfg.MaxSize {
+			a.Screenshot = nil
+		}
+
+		if len(a.HTML) > b.cfg.MaxSize {
+			// never split a rune, since protobuf strings must be valid UTF-8
+			n := b.cfg.MaxSize
+			for n > 0 && !utf8.RuneStart(a.HTML[n]) {
+				n--
+			}
+			a.HTML = a.HTML[:n]
+		}
+	}
+
+	return a
+}
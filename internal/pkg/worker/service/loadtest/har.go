@@ -0,0 +1,83 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/dkorittki/loago/pkg/worker/runner"
+	"github.com/rs/zerolog/log"
+)
+
+// maxHARSize limits the size of a HAR in bytes, which keeps results
+// along with artifacts and subresources within api.MaxMessageSize.
+const maxHARSize = 2 * 1024 * 1024
+
+// harSampler samples HARs of page loads for every runner of a loadtest,
+// until cfg.Max HARs are sampled.
+type harSampler struct {
+	cfg *HARSampling
+
+	mu sync.Mutex
+
+	// loads counts the page loads per endpoint URL.
+	loads map[string]int
+
+	// sampled counts the sampled HARs.
+	sampled int
+}
+
+func newHARSampler(cfg *HARSampling) *harSampler {
+	if cfg == nil || cfg.Max <= 0 {
+		return nil
+	}
+
+	return &harSampler{cfg: cfg, loads: make(map[string]int)}
+}
+
+// sample returns the HAR of res as JSON, if the page load at url is one
+// of the first loads of the endpoint or slower than the threshold
+// and the budget isn't spent. Otherwise it returns nil.
+func (s *harSampler) sample(url string, res *runner.Result) []byte {
+	if s == nil || res.HAR == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.loads[url]++
+	first := s.loads[url] <= s.cfg.First
+	slow := s.cfg.Slower > 0 && loadTime(res) >= s.cfg.Slower
+	ok := (first || slow) && s.sampled < s.cfg.Max
+	if ok {
+		s.sampled++
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	b, err := json.Marshal(res.HAR)
+	if err != nil || len(b) > maxHARSize {
+		log.Debug().
+			Str("component", "schedule").
+			Str("url", url).
+			Int("size", len(b)).
+			Err(err).
+			Msg("can't sample HAR")
+
+		return nil
+	}
+
+	return b
+}
+
+// loadTime returns how long the page took to load,
+// or its TTFB, if the browser didn't report the load event.
+func loadTime(res *runner.Result) time.Duration {
+	if res.Timing.Load > 0 {
+		return res.Timing.Load
+	}
+
+	return res.TTFB
+}
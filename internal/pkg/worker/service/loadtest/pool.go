@@ -0,0 +1,281 @@
+package loadtest
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync"
+
+	chromedpexecutor "github.com/dkorittki/loago/internal/pkg/worker/executor/browser"
+	"github.com/dkorittki/loago/pkg/worker/runner"
+	"github.com/rs/zerolog/log"
+)
+
+// pool manages the runners of a loadtest and their schedules.
+type pool struct {
+	ctx       context.Context
+	cfg       *Config
+	endpoints []*Endpoint
+	journeys  []*Journey
+	results   chan EndpointResult
+
+	// emulations contains every emulation as often as its weight.
+	emulations []*Emulation
+
+	// chrome hosts chrome runners in shared chrome processes, if set.
+	chrome *runner.ChromePool
+
+	// artifacts is shared by every chrome runner, if artifacts are enabled.
+	artifacts *artifactBudget
+
+	// har is shared by every chrome runner, if HARs are enabled.
+	har *harSampler
+
+	// newExecutor returns the executor of a new chrome runner.
+	newExecutor func() chromedpexecutor.Executor
+
+	// active contains the cancel functions of running runners in start order.
+	active []context.CancelFunc
+
+	// nextID is the ID of the next runner. IDs are never reused,
+	// so a new runner never shares the cache dir of a stopped one.
+	nextID int
+
+	// errs receives the first error of any schedule.
+	errs chan error
+	wg   sync.WaitGroup
+}
+
+// user is the state of the simulated user of a runner.
+type user struct {
+	id int
+
+	// vars replaces references to user data in steps, if given.
+	vars *strings.Replacer
+
+	// emulation is the name of the emulation of the runner, if any,
+	// and profile the emulation itself.
+	emulation string
+	profile   *runner.Emulation
+
+	// cold is true during an iteration, which started with a cleared cache.
+	cold bool
+
+	// artifacts captures the page of failed requests, if set.
+	artifacts *artifactBudget
+
+	// har samples page loads of endpoints, if set.
+	har *harSampler
+}
+
+func newPool(ctx context.Context, cfg *Config, endpoints []*Endpoint, journeys []*Journey, results chan EndpointResult) *pool {
+	var emulations []*Emulation
+	for _, v := range cfg.Emulations {
+		for j := 0; j < int(v.Weight); j++ {
+			emulations = append(emulations, v)
+		}
+	}
+
+	return &pool{
+		ctx:        ctx,
+		cfg:        cfg,
+		endpoints:  endpoints,
+		journeys:   journeys,
+		results:    results,
+		emulations: emulations,
+		artifacts:  newArtifactBudget(cfg.Artifacts),
+		har:        newHARSampler(cfg.HAR),
+		errs:       make(chan error, 1),
+
+		newExecutor: func() chromedpexecutor.Executor { return chromedpexecutor.New() },
+	}
+}
+
+// size returns the amount of running runners.
+func (p *pool) size() int {
+	return len(p.active)
+}
+
+// scale starts or stops runners until n runners are running.
+func (p *pool) scale(n int) {
+	if n == p.size() {
+		return
+	}
+
+	log.Debug().
+		Str("component", "loadtest_service").
+		Int("from", p.size()).
+		Int("to", n).
+		Msg("scale runners")
+
+	for p.size() < n {
+		if err := p.start(); err != nil {
+			p.fail(err)
+			return
+		}
+	}
+
+	for p.size() > n {
+		p.stop()
+	}
+}
+
+// start starts a new runner with its own schedule.
+func (p *pool) start() error {
+	u := &user{id: p.nextID}
+	p.nextID++
+
+	if p.cfg.BrowserType == BrowserTypeChrome {
+		u.artifacts = p.artifacts
+		u.har = p.har
+
+		if len(p.emulations) > 0 {
+			em := p.emulations[rand.Intn(len(p.emulations))].Emulation
+			u.profile = &em
+			u.emulation = em.Name
+		}
+	}
+
+	r, err := p.newRunner(u, 0)
+	if err != nil {
+		return err
+	}
+
+	// runners are stopped in reverse start order, so the position of a runner
+	// never changes and every running runner has a distinct row of user data,
+	// as long as there are enough rows.
+	u.vars = p.cfg.UserData.vars(p.size())
+
+	ctx, cancel := context.WithCancel(p.ctx)
+	p.active = append(p.active, cancel)
+
+	p.wg.Add(1)
+	go p.run(ctx, u, r)
+
+	return nil
+}
+
+// newRunner returns a new runner for user u, which replaces
+// the runners of u, whose browser broke, after they restarted n times.
+func (p *pool) newRunner(u *user, n int) (runner.Runner, error) {
+	switch p.cfg.BrowserType {
+	case BrowserTypeChrome:
+		c := runner.NewChromeRunner(u.id, p.newExecutor())
+		c.Subresources = p.cfg.Subresources
+		c.HAR = p.har != nil
+		c.Config = p.cfg.Chrome
+		c.Emulation = u.profile
+		c.Restarts = n
+
+		if p.chrome != nil {
+			bc, err := p.chrome.NewBrowserContext(p.ctx)
+			if err != nil {
+				return nil, err
+			}
+			c.BrowserContext = bc
+		}
+
+		return c, nil
+	case BrowserTypeHTTP:
+		return runner.NewHTTPRunner(u.id, p.cfg.Cookies), nil
+	}
+
+	return runner.NewFakeRunner(u.id), nil
+}
+
+// run runs the schedule of user u with runner r until ctx is canceled.
+// If the browser of a chrome runner breaks, the runner is replaced
+// according to the restart policy. Otherwise the error of the schedule fails the pool.
+func (p *pool) run(ctx context.Context, u *user, r runner.Runner) {
+	defer p.wg.Done()
+
+	for n := 0; ; n++ {
+		if n > 0 {
+			if ctx.Err() != nil {
+				return
+			}
+
+			var err error
+			r, err = p.newRunner(u, n)
+			if err != nil {
+				p.fail(err)
+				return
+			}
+		}
+
+		// the runner's browser is closed along with its context
+		runnerCtx, cancel := context.WithCancel(ctx)
+		err := p.schedule(r.WithContext(runnerCtx), u)
+		cancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		// a schedule only returns without an error, once its context ended,
+		// which happens before ctx only, if the browser closed.
+		if err == nil {
+			err = ErrBrowserClosed
+		}
+
+		if !p.restart(ctx, u, n, err) {
+			p.fail(err)
+			return
+		}
+	}
+}
+
+// restart reports the restart of the runner of user u, which restarted n times before,
+// and waits for its backoff. It returns false, if the runner can't be restarted,
+// because it's no chrome runner or the restart budget of the user is exhausted.
+// The budget is per user, so a crash of a chrome process shared by many users
+// doesn't exhaust the budget of the whole loadtest at once.
+func (p *pool) restart(ctx context.Context, u *user, n int, err error) bool {
+	if p.cfg.BrowserType != BrowserTypeChrome || n >= p.cfg.Restarts.Max {
+		return false
+	}
+
+	d := p.cfg.Restarts.backoff(n)
+
+	log.Warn().
+		Str("component", "schedule").
+		Int("id", u.id).
+		Int("restarts", n+1).
+		Dur("backoff", d).
+		Err(err).
+		Msg("restart runner")
+
+	send(ctx, p.results, EndpointResult{
+		User:      u.id,
+		Event:     EventRestart,
+		Error:     err.Error(),
+		Emulation: u.emulation,
+	})
+
+	_ = sleepBetween(ctx, d, d)
+	return true
+}
+
+// fail reports err as the first error of the pool, unless there is one already.
+func (p *pool) fail(err error) {
+	select {
+	case p.errs <- err:
+	default:
+	}
+}
+
+// stop stops the most recently started runner.
+func (p *pool) stop() {
+	last := len(p.active) - 1
+	p.active[last]()
+	p.active = p.active[:last]
+}
+
+// stopAll stops every runner and waits for their schedules to return.
+func (p *pool) stopAll() {
+	for p.size() > 0 {
+		p.stop()
+	}
+
+	p.wg.Wait()
+}
@@ -0,0 +1,55 @@
+package loadtest
+
+import (
+	"math"
+	"time"
+)
+
+// profile is a load profile consisting of consecutive stages.
+type profile []*Stage
+
+// staticProfile returns a profile holding amount users for duration.
+func staticProfile(amount int, duration time.Duration) profile {
+	return profile{{Duration: duration, Target: amount, Ramp: RampStep}}
+}
+
+// users returns the amount of users at elapsed time t since the start of the profile
+// and false, or zero and true, if every stage of the profile finished.
+func (p profile) users(t time.Duration) (int, bool) {
+	var (
+		start time.Duration
+		prev  int
+	)
+
+	for _, s := range p {
+		if s.Duration == 0 {
+			return s.Target, false
+		}
+
+		if t < start+s.Duration {
+			if s.Ramp == RampStep {
+				return s.Target, false
+			}
+
+			frac := float64(t-start) / float64(s.Duration)
+			return prev + int(math.Round(frac*float64(s.Target-prev))), false
+		}
+
+		start += s.Duration
+		prev = s.Target
+	}
+
+	return 0, true
+}
+
+// max returns the highest amount of users in the profile.
+func (p profile) max() int {
+	var m int
+	for _, s := range p {
+		if s.Target > m {
+			m = s.Target
+		}
+	}
+
+	return m
+}
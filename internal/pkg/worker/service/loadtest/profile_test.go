@@ -0,0 +1,52 @@
+package loadtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfile_Users(t *testing.T) {
+	p := profile{
+		{Duration: 2 * time.Minute, Target: 50, Ramp: RampLinear},
+		{Duration: 10 * time.Minute, Target: 50, Ramp: RampLinear},
+		{Duration: time.Minute, Target: 0, Ramp: RampLinear},
+		{Duration: time.Minute, Target: 80, Ramp: RampStep},
+		{Duration: time.Minute, Target: 10, Ramp: RampStep},
+	}
+
+	vars := []struct {
+		elapsed  time.Duration
+		users    int
+		finished bool
+	}{
+		{0, 0, false},
+		{time.Minute, 25, false},
+		{2 * time.Minute, 50, false},
+		{7 * time.Minute, 50, false},
+		{12*time.Minute + 30*time.Second, 25, false},
+		{13 * time.Minute, 80, false},
+		{14*time.Minute - time.Second, 80, false},
+		{14 * time.Minute, 10, false},
+		{15 * time.Minute, 0, true},
+		{time.Hour, 0, true},
+	}
+
+	for _, v := range vars {
+		users, finished := p.users(v.elapsed)
+		assert.Equal(t, v.users, users, "users after %s", v.elapsed)
+		assert.Equal(t, v.finished, finished, "finished after %s", v.elapsed)
+	}
+
+	assert.Equal(t, 80, p.max())
+}
+
+func TestStaticProfile(t *testing.T) {
+	p := staticProfile(10, 0)
+
+	users, finished := p.users(1000 * time.Hour)
+	assert.Equal(t, 10, users)
+	assert.False(t, finished)
+	assert.Equal(t, 10, p.max())
+}
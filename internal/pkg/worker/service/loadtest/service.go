@@ -5,10 +5,8 @@ import (
 	"context"
 	"errors"
 	"math/rand"
-	"sync"
 	"time"
 
-	chromedpexecutor "github.com/dkorittki/loago/internal/pkg/worker/executor/browser"
 	"github.com/dkorittki/loago/pkg/worker/runner"
 	"github.com/rs/zerolog/log"
 )
@@ -17,8 +15,14 @@ var (
 	// ErrInvalidRunnerType indicates an error when an unknown runner type is given.
 	ErrInvalidRunnerType = errors.New("invalid runner type")
 
+	// ErrNoEndpoints indicates an error when neither endpoints nor journeys are given.
+	ErrNoEndpoints = errors.New("no endpoints or journeys given")
+
 	// ErrInvalidWaitBoundaries indicates an error when the minimum wait duration takes longer than the max duration.
 	ErrInvalidWaitBoundaries = errors.New("min wait duration is longer than max wait duration")
+
+	// ErrBrowserClosed indicates an error when the browser of a runner closed unexpectedly.
+	ErrBrowserClosed = errors.New("browser closed unexpectedly")
 )
 
 // Service handles the execution of load tests.
@@ -29,26 +33,64 @@ func New() *Service {
 	return &Service{}
 }
 
+// profileInterval is the interval in which the amount of runners
+// is adjusted to the load profile.
+const profileInterval = 100 * time.Millisecond
+
 // Run performs continues requests on endpoints.
-// It starts a given amount of runners of type browserType (i.e. Chrome or Fake).
-// amount controls how many runners are spawned,
-// endpoints control where and how often to perform requests,
+// It starts runners of type cfg.BrowserType (i.e. Chrome, HTTP or Fake).
+// cfg.Amount controls how many runners are spawned, unless cfg.Stages
+// contains a load profile, in which case runners are started and stopped
+// over time to follow the profile.
+// cfg.Endpoints and cfg.Journeys control where and how often to perform requests,
+// cfg.Duration limits how long the loadtest runs,
 // results is a channel on which response metrics are written into.
 //
-// This function runs as long as the context ctx is not closed.
+// This function runs as long as the context ctx is not closed,
+// if cfg.Duration is greater than zero, until it elapsed and,
+// if a load profile is given, until its last stage finished.
 // Closing the context aborts running request and closes each runner.
-func (s *Service) Run(ctx context.Context,
-	browserType BrowserType,
-	endpoints []*Endpoint,
-	minWait, maxWait time.Duration,
-	amount int,
-	results chan EndpointResult) error {
-	log.Info().Str("component", "loadtest_service").Msg("starting a new loadtest")
-
-	ctx, cancel := context.WithCancel(ctx)
+func (s *Service) Run(ctx context.Context, cfg *Config, results chan EndpointResult) error {
+	switch cfg.BrowserType {
+	case BrowserTypeFake, BrowserTypeChrome, BrowserTypeHTTP:
+	default:
+		return ErrInvalidRunnerType
+	}
+
+	if len(cfg.Endpoints) == 0 && len(cfg.Journeys) == 0 {
+		return ErrNoEndpoints
+	}
+
+	if cfg.MinWait > cfg.MaxWait {
+		return ErrInvalidWaitBoundaries
+	}
+
+	p := profile(cfg.Stages)
+	if len(p) == 0 {
+		p = staticProfile(cfg.Amount, 0)
+	}
+
+	log.Info().
+		Str("component", "loadtest_service").
+		Dur("duration", cfg.Duration).
+		Int("stages", len(cfg.Stages)).
+		Int("max_runners", p.max()).
+		Msg("starting a new loadtest")
+
+	var cancel context.CancelFunc
+	if cfg.Duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, cfg.Duration)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
 	defer cancel()
 
-	// create temporary slice random selection of endpoints.
+	endpoints := cfg.Endpoints
+	if cfg.BrowserType == BrowserTypeChrome {
+		endpoints = cfg.Restarts.withTimeouts(endpoints)
+	}
+
+	// create temporary slices for random selection of endpoints and journeys.
 	var e []*Endpoint
 	for i, v := range endpoints {
 		for j := 0; j < int(v.Weight); j++ {
@@ -56,102 +98,150 @@ func (s *Service) Run(ctx context.Context,
 		}
 	}
 
-	errChan := make(chan error, amount)
-	wgDone := make(chan bool)
-	var wg sync.WaitGroup
-	wg.Add(amount)
+	var jo []*Journey
+	for i, v := range cfg.Journeys {
+		for j := 0; j < int(v.Weight); j++ {
+			jo = append(jo, cfg.Journeys[i])
+		}
+	}
 
-	for i := 0; i < amount; i++ {
-		var r runner.Runner
-		switch browserType {
-		case BrowserTypeFake:
-			r = runner.NewFakeRunner(i)
-		case BrowserTypeChrome:
-			e := chromedpexecutor.New()
-			r = runner.NewChromeRunner(i, e)
-		default:
-			return ErrInvalidRunnerType
+	chrome, err := newChromePool(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if chrome != nil {
+		defer chrome.Close()
+	}
+
+	runners := newPool(ctx, cfg, e, jo, results)
+	runners.chrome = chrome
+	defer runners.stopAll()
+
+	start := time.Now()
+	ticker := time.NewTicker(profileInterval)
+	defer ticker.Stop()
+
+	for {
+		users, finished := p.users(time.Since(start))
+		if finished {
+			log.Info().Msg("load profile finished")
+			return nil
 		}
 
-		runnerCtx := r.WithContext(ctx)
+		runners.scale(users)
 
-		// Start a new schedule for this specific runner.
-		id := i
-		go func() {
-			err := schedule(runnerCtx, id, e, minWait, maxWait, results, &wg)
-			if err != nil {
-				errChan <- err
-			}
-		}()
+		select {
+		case <-ticker.C:
+		case err := <-runners.errs:
+			return err
+		case <-ctx.Done():
+			log.Info().Msg("schedules finished work successfully")
+			return nil
+		}
 	}
+}
 
-	go func() {
-		wg.Wait()
-		close(wgDone)
-	}()
+// newChromePool returns the chrome pool hosting the chrome runners of cfg, if any.
+// Runners are hosted in remote chrome processes of the worker, if it has any,
+// or in cfg.ChromeProcesses shared chrome processes. Otherwise every runner
+// starts its own chrome process and the pool is nil.
+func newChromePool(ctx context.Context, cfg *Config) (*runner.ChromePool, error) {
+	if cfg.BrowserType != BrowserTypeChrome {
+		return nil, nil
+	}
 
-	select {
-	case <-wgDone:
-		log.Info().Msg("schedules finished work successfully")
-		return nil
-	case err := <-errChan:
-		return err
+	if cfg.Chrome != nil && len(cfg.Chrome.RemoteURLs) > 0 {
+		return runner.NewRemoteChromePool(ctx, cfg.Chrome.RemoteURLs)
+	}
+
+	if cfg.ChromeProcesses > 0 {
+		return runner.NewChromePool(ctx, cfg.ChromeProcesses, cfg.Chrome)
 	}
 
+	return nil, nil
 }
 
-// schedule repeatedly runs one runner, writing it's result in results.
+// schedule repeatedly runs the runner of user u, writing it's result in p.results.
+// Before any endpoint or journey, the runner performs the setup steps until they succeed.
+// A share of p.cfg.ColdVisits percent of the iterations start with a cleared cache,
+// like the visit of a first-time visitor.
 // It is meant to be used in it's own goroutine and stops
 // when the context is canceled.
-func schedule(ctx context.Context, id int, endpoints []*Endpoint, minWait, maxWait time.Duration, results chan EndpointResult, wg *sync.WaitGroup) error {
+func (p *pool) schedule(ctx context.Context, u *user) error {
 	log.Info().
 		Str("component", "schedule").
-		Int("id", id).
+		Int("id", u.id).
 		Msg("start new schedule")
 
-	defer wg.Done()
+	setup := &Journey{Name: SetupJourney, Steps: p.cfg.Setup}
+	ready := len(setup.Steps) == 0
 
 	for {
-		err := sleepBetween(minWait, maxWait)
+		err := sleepBetween(ctx, p.cfg.MinWait, p.cfg.MaxWait)
 		if err != nil {
 			return err
 		}
 
 		select {
 		default:
-			url := endpoints[rand.Intn(len(endpoints))].URL
-			ttfb, code, msg, cached, err := runner.Call(ctx, url)
+			var err error
+
+			// visit is true, if this iteration calls an endpoint or performs a journey
+			visit := ready
+
+			u.cold = rand.Intn(100) < p.cfg.ColdVisits
+			if u.cold {
+				// first-time visitors have neither a cache nor a session,
+				// so they perform the setup as part of their visit
+				err = runner.ClearCache(ctx)
+				if err == nil && len(setup.Steps) > 0 {
+					ready, err = walk(ctx, u, setup, p.results)
+				}
+				visit = ready
+			} else if !ready {
+				ready, err = walk(ctx, u, setup, p.results)
+			}
+
+			if err == nil && visit {
+				n := rand.Intn(len(p.endpoints) + len(p.journeys))
+				if n < len(p.endpoints) {
+					err = call(ctx, u, p.endpoints[n], p.results)
+				} else {
+					_, err = walk(ctx, u, p.journeys[n-len(p.endpoints)], p.results)
+				}
+			}
 
 			if err != nil {
 				if err == context.Canceled {
 					log.Debug().
 						Str("component", "schedule").
-						Int("id", id).
+						Int("id", u.id).
 						Msg("context canceld mid request")
 
 					return nil
 				} else if err == context.DeadlineExceeded {
+					if ctx.Err() != nil {
+						log.Debug().
+							Str("component", "schedule").
+							Int("id", u.id).
+							Msg("loadtest duration elapsed mid request")
+
+						return nil
+					}
+
 					log.Warn().
 						Str("component", "schedule").
-						Int("id", id).
+						Int("id", u.id).
 						Msg("request timed out")
 					continue
 				}
 
 				return err
 			}
-
-			results <- EndpointResult{
-				URL:               url,
-				HTTPStatusCode:    code,
-				HTTPStatusMessage: msg,
-				TTFB:              ttfb,
-				Cached:            cached,
-			}
 		case <-ctx.Done():
 			log.Info().
 				Str("component", "schedule").
-				Int("id", id).
+				Int("id", u.id).
 				Msg("stop schedule")
 
 			return nil
@@ -159,8 +249,147 @@ func schedule(ctx context.Context, id int, endpoints []*Endpoint, minWait, maxWa
 	}
 }
 
-// Block for something between min and max duration.
-func sleepBetween(min, max time.Duration) error {
+// call requests endpoint e for user u and writes the result into results.
+// A failed request is reported as result, only errors caused by the end of ctx
+// or a broken runner are returned.
+func call(ctx context.Context, u *user, e *Endpoint, results chan EndpointResult) error {
+	start := time.Now()
+
+	res, err := runner.CallWithOptions(ctx, e.URL, &e.Options)
+	if err != nil {
+		class := runner.ClassifyError(err)
+		if class == "" || ctx.Err() != nil {
+			return err
+		}
+
+		log.Debug().
+			Str("component", "schedule").
+			Int("id", u.id).
+			Str("url", e.URL).
+			Str("class", string(class)).
+			Err(err).
+			Msg("request failed")
+
+		send(ctx, results, EndpointResult{
+			User:       u.id,
+			URL:        e.URL,
+			Duration:   time.Since(start),
+			Error:      err.Error(),
+			ErrorClass: class,
+			Emulation:  u.emulation,
+			ColdCache:  u.cold,
+			Artifact:   u.artifacts.capture(ctx),
+		})
+		return nil
+	}
+
+	r := newEndpointResult(u, e.URL, res)
+	r.HAR = u.har.sample(e.URL, res)
+	if res.StatusCode >= 400 {
+		r.Artifact = u.artifacts.capture(ctx)
+	}
+
+	send(ctx, results, r)
+	return nil
+}
+
+// walk performs every step of journey j for user u and writes a result per step into results.
+// A failed step is reported as result and ends the journey, only errors caused by
+// the end of ctx or a broken runner are returned. It returns true, if every step succeeded.
+func walk(ctx context.Context, u *user, j *Journey, results chan EndpointResult) (bool, error) {
+	for _, s := range j.Steps {
+		step := s.Step
+		if u.vars != nil {
+			step.URL = u.vars.Replace(step.URL)
+			step.Selector = u.vars.Replace(step.Selector)
+			step.Key = u.vars.Replace(step.Key)
+			step.Value = u.vars.Replace(step.Value)
+		}
+
+		start := time.Now()
+
+		res, err := runner.Perform(ctx, &step)
+		if err != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+
+			class := runner.ClassifyError(err)
+			if class == "" && !errors.Is(err, runner.ErrUnsupportedStep) {
+				return false, err
+			}
+
+			log.Debug().
+				Str("component", "schedule").
+				Int("id", u.id).
+				Str("journey", j.Name).
+				Str("step", s.Name).
+				Err(err).
+				Msg("journey step failed")
+
+			send(ctx, results, EndpointResult{
+				User:       u.id,
+				URL:        step.URL,
+				Journey:    j.Name,
+				Step:       s.Name,
+				Duration:   time.Since(start),
+				Error:      err.Error(),
+				ErrorClass: class,
+				Emulation:  u.emulation,
+				ColdCache:  u.cold,
+				Artifact:   u.artifacts.capture(ctx),
+			})
+			return false, nil
+		}
+
+		r := newEndpointResult(u, res.URL, res)
+		r.Journey = j.Name
+		r.Step = s.Name
+		r.Duration = res.Duration
+
+		if res.StatusCode >= 400 {
+			r.Artifact = u.artifacts.capture(ctx)
+		}
+
+		if !send(ctx, results, r) {
+			return false, ctx.Err()
+		}
+	}
+
+	return true, nil
+}
+
+func newEndpointResult(u *user, url string, res *runner.Result) EndpointResult {
+	return EndpointResult{
+		User:              u.id,
+		URL:               url,
+		HTTPStatusCode:    res.StatusCode,
+		HTTPStatusMessage: res.StatusMessage,
+		TTFB:              res.TTFB,
+		Cached:            res.Cached,
+		Timing:            res.Timing,
+		Network:           res.Network,
+		Subresources:      res.Subresources,
+		JSErrors:          res.JSErrors,
+		JSErrorMessages:   res.JSErrorMessages,
+		Emulation:         u.emulation,
+		ColdCache:         u.cold,
+	}
+}
+
+// send writes r into results. It returns false, if ctx ended first.
+func send(ctx context.Context, results chan EndpointResult, r EndpointResult) bool {
+	select {
+	case results <- r:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Block for something between min and max duration
+// or until ctx is closed.
+func sleepBetween(ctx context.Context, min, max time.Duration) error {
 	var z time.Duration
 
 	if min == max {
@@ -171,6 +400,13 @@ func sleepBetween(min, max time.Duration) error {
 		z = time.Duration(int64(min) + rand.Int63n(int64(max-min)))
 	}
 
-	time.Sleep(z)
+	t := time.NewTimer(z)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+
 	return nil
 }
@@ -2,10 +2,17 @@ package loadtest
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/dkorittki/loago/internal/pkg/testing/browser"
+	chromedpexecutor "github.com/dkorittki/loago/internal/pkg/worker/executor/browser"
+	"github.com/dkorittki/loago/pkg/worker/runner"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNew(t *testing.T) {
@@ -120,7 +127,7 @@ func TestService_Run(t *testing.T) {
 					},
 				},
 				amount:      1,
-				browserType: 2,
+				browserType: 3,
 			},
 			out: output{
 				greaterOrEqual: 0,
@@ -158,7 +165,13 @@ func TestService_Run(t *testing.T) {
 			s := New()
 
 			go func() {
-				errChan <- s.Run(ctx, v.in.browserType, v.in.endpoints, v.in.minWait, v.in.maxWait, v.in.amount, results)
+				errChan <- s.Run(ctx, &Config{
+					BrowserType: v.in.browserType,
+					Endpoints:   v.in.endpoints,
+					MinWait:     v.in.minWait,
+					MaxWait:     v.in.maxWait,
+					Amount:      v.in.amount,
+				}, results)
 			}()
 
 			go func() {
@@ -183,3 +196,596 @@ func TestService_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestService_RunDuration(t *testing.T) {
+	results := make(chan EndpointResult, 1000)
+	endpoints := []*Endpoint{
+		{
+			URL:    "http://localhost:8080/url1",
+			Weight: 1,
+		},
+	}
+
+	s := New()
+	start := time.Now()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeFake,
+		Endpoints:   endpoints,
+		MinWait:     100 * time.Millisecond,
+		MaxWait:     100 * time.Millisecond,
+		Amount:      2,
+		Duration:    time.Second,
+	}, results)
+	elapsed := time.Since(start)
+	close(results)
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, int64(elapsed), int64(time.Second))
+	assert.Less(t, int64(elapsed), int64(2*time.Second))
+	assert.GreaterOrEqual(t, len(results), 10)
+	assert.LessOrEqual(t, len(results), 14)
+}
+
+func TestService_RunStages(t *testing.T) {
+	results := make(chan EndpointResult, 1000)
+	endpoints := []*Endpoint{
+		{
+			URL:    "http://localhost:8080/url1",
+			Weight: 1,
+		},
+	}
+
+	s := New()
+	start := time.Now()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeFake,
+		Endpoints:   endpoints,
+		MinWait:     100 * time.Millisecond,
+		MaxWait:     100 * time.Millisecond,
+		Stages: []*Stage{
+			{Duration: time.Second, Target: 4, Ramp: RampStep},
+			{Duration: time.Second, Target: 0, Ramp: RampLinear},
+		},
+	}, results)
+	elapsed := time.Since(start)
+	close(results)
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, int64(elapsed), int64(2*time.Second))
+	assert.Less(t, int64(elapsed), int64(3*time.Second))
+
+	// 4 users during the first second, 2 users on average during the second one,
+	// each performing a request every 150ms.
+	assert.GreaterOrEqual(t, len(results), 30)
+	assert.LessOrEqual(t, len(results), 50)
+}
+
+func TestService_RunHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	results := make(chan EndpointResult, 1000)
+	endpoints := []*Endpoint{
+		{
+			URL:    srv.URL,
+			Weight: 1,
+		},
+	}
+
+	s := New()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeHTTP,
+		Endpoints:   endpoints,
+		MinWait:     100 * time.Millisecond,
+		MaxWait:     100 * time.Millisecond,
+		Amount:      50,
+		Duration:    time.Second,
+	}, results)
+	close(results)
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, len(results), 250)
+
+	for res := range results {
+		assert.Equal(t, srv.URL, res.URL)
+		assert.Equal(t, http.StatusAccepted, res.HTTPStatusCode)
+		assert.Greater(t, int64(res.TTFB), int64(0))
+	}
+}
+
+func TestService_RunHTTP_FailedRequests(t *testing.T) {
+	// a closed server refuses connections
+	srv := httptest.NewServer(nil)
+	srv.Close()
+
+	results := make(chan EndpointResult, 1000)
+	endpoints := []*Endpoint{
+		{
+			URL:    srv.URL,
+			Weight: 1,
+		},
+	}
+
+	s := New()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeHTTP,
+		Endpoints:   endpoints,
+		MinWait:     100 * time.Millisecond,
+		MaxWait:     100 * time.Millisecond,
+		Amount:      2,
+		Duration:    time.Second,
+	}, results)
+	close(results)
+
+	// failed requests are results and runners keep going
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, len(results), 10)
+
+	for res := range results {
+		assert.Equal(t, srv.URL, res.URL)
+		assert.Equal(t, runner.ErrorClassConnect, res.ErrorClass)
+		assert.NotEmpty(t, res.Error)
+		assert.Zero(t, res.HTTPStatusCode)
+	}
+}
+
+func TestService_RunHTTP_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	results := make(chan EndpointResult, 1000)
+	endpoints := []*Endpoint{
+		{
+			URL:     srv.URL,
+			Weight:  1,
+			Options: runner.RequestOptions{Timeout: 50 * time.Millisecond},
+		},
+	}
+
+	s := New()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeHTTP,
+		Endpoints:   endpoints,
+		MinWait:     100 * time.Millisecond,
+		MaxWait:     100 * time.Millisecond,
+		Amount:      1,
+		Duration:    time.Second,
+	}, results)
+	close(results)
+
+	// a hanging backend doesn't stall the runner
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, len(results), 4)
+
+	for res := range results {
+		assert.Equal(t, runner.ErrorClassTimeout, res.ErrorClass)
+		assert.Equal(t, runner.ErrRequestTimeout.Error(), res.Error)
+		assert.GreaterOrEqual(t, int64(res.Duration), int64(50*time.Millisecond))
+		assert.Less(t, int64(res.Duration), int64(300*time.Millisecond))
+	}
+}
+
+func TestService_RunChrome_Restarts(t *testing.T) {
+	results := make(chan EndpointResult, 1000)
+	endpoints := []*Endpoint{
+		{
+			URL:    "http://foo.bar",
+			Weight: 1,
+		},
+	}
+
+	s := New()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeChrome,
+		Endpoints:   endpoints,
+		// every browser breaks, since chrome can't be launched
+		Chrome:   &runner.ChromeConfig{ExecPath: "/nonexistent/chrome", CacheRoot: t.TempDir()},
+		Restarts: RestartPolicy{Max: 2, Backoff: 10 * time.Millisecond},
+		MinWait:  10 * time.Millisecond,
+		MaxWait:  10 * time.Millisecond,
+		Amount:   1,
+		Duration: 5 * time.Second,
+	}, results)
+	close(results)
+
+	// the loadtest fails, once the restart budget is exhausted
+	assert.Error(t, err)
+	assert.Len(t, results, 2)
+
+	for res := range results {
+		assert.Equal(t, EventRestart, res.Event)
+		assert.NotEmpty(t, res.Error)
+		assert.Empty(t, res.URL)
+	}
+}
+
+func TestPool_Restart(t *testing.T) {
+	results := make(chan EndpointResult, 10)
+	p := newPool(context.Background(), &Config{
+		BrowserType: BrowserTypeChrome,
+		Restarts:    RestartPolicy{Max: 2},
+	}, nil, nil, results)
+
+	// every user has its own restart budget
+	assert.True(t, p.restart(context.Background(), &user{id: 1}, 0, ErrBrowserClosed))
+	assert.True(t, p.restart(context.Background(), &user{id: 1}, 1, ErrBrowserClosed))
+	assert.False(t, p.restart(context.Background(), &user{id: 1}, 2, ErrBrowserClosed))
+	assert.True(t, p.restart(context.Background(), &user{id: 2}, 0, ErrBrowserClosed))
+	assert.Len(t, results, 3)
+	assert.Equal(t, 1, (<-results).User)
+
+	// other runners are never restarted
+	p.cfg.BrowserType = BrowserTypeHTTP
+	assert.False(t, p.restart(context.Background(), &user{id: 3}, 0, ErrBrowserClosed))
+}
+
+func TestPool_RestartBrokenJourney(t *testing.T) {
+	results := make(chan EndpointResult, 10)
+	journeys := []*Journey{
+		{
+			Name:   "checkout",
+			Weight: 1,
+			Steps: []*Step{
+				{Name: "read", Step: runner.Step{Action: runner.StepSleep, Duration: time.Millisecond}},
+				{Name: "buy", Step: runner.Step{Action: runner.StepClick, Selector: "#buy"}},
+			},
+		},
+	}
+
+	p := newPool(context.Background(), &Config{
+		BrowserType: BrowserTypeChrome,
+		Chrome:      &runner.ChromeConfig{CacheRoot: t.TempDir()},
+		Restarts:    RestartPolicy{Max: 1, Backoff: time.Millisecond},
+		MinWait:     time.Millisecond,
+		MaxWait:     time.Millisecond,
+	}, nil, journeys, results)
+
+	// the browser of every runner breaks mid-journey
+	broken := errors.New("websocket: close 1006")
+	executors := 0
+	p.newExecutor = func() chromedpexecutor.Executor {
+		executors++
+
+		e := browser.NewTestExecutor()
+		e.On("ListenTarget", mock.Anything, mock.Anything)
+		e.On("Run", mock.Anything, mock.Anything).Return(broken)
+		return e
+	}
+
+	u := &user{id: 1}
+	r, err := p.newRunner(u, 0)
+	assert.NoError(t, err)
+
+	p.wg.Add(1)
+	p.run(context.Background(), u, r)
+	close(results)
+
+	// the runner is restarted once, then the restart budget is exhausted
+	assert.Equal(t, 2, executors)
+	assert.Equal(t, broken, <-p.errs)
+
+	var steps, restarts int
+	for res := range results {
+		// the restarted runner keeps the ID of its user
+		assert.Equal(t, 1, res.User)
+
+		switch {
+		case res.Event == EventRestart:
+			restarts++
+			assert.Equal(t, broken.Error(), res.Error)
+		case res.Step == "read":
+			steps++
+		default:
+			t.Errorf("unexpected result %+v", res)
+		}
+	}
+
+	assert.Equal(t, 2, steps)
+	assert.Equal(t, 1, restarts)
+}
+
+func TestRestartPolicy_WithTimeouts(t *testing.T) {
+	endpoints := []*Endpoint{
+		{URL: "http://foo.bar", Weight: 1},
+		{URL: "http://foo.bar/slow", Weight: 1, Options: runner.RequestOptions{Timeout: time.Minute}},
+	}
+
+	// without restarts, pages may load forever
+	assert.Equal(t, endpoints, RestartPolicy{}.withTimeouts(endpoints))
+
+	e := RestartPolicy{Max: 1}.withTimeouts(endpoints)
+	assert.Equal(t, RestartTimeout, e[0].Options.Timeout)
+	assert.Equal(t, time.Minute, e[1].Options.Timeout)
+
+	// the endpoints of the config stay untouched
+	assert.Zero(t, endpoints[0].Options.Timeout)
+}
+
+func TestRestartPolicy_Backoff(t *testing.T) {
+	p := RestartPolicy{Max: 10, Backoff: time.Second}
+
+	assert.Equal(t, time.Second, p.backoff(0))
+	assert.Equal(t, 2*time.Second, p.backoff(1))
+	assert.Equal(t, 8*time.Second, p.backoff(3))
+	assert.Equal(t, maxRestartBackoff, p.backoff(10))
+	assert.Equal(t, maxRestartBackoff, p.backoff(100))
+	assert.Zero(t, RestartPolicy{}.backoff(3))
+}
+
+func TestService_RunJourneys(t *testing.T) {
+	results := make(chan EndpointResult, 1000)
+	journeys := []*Journey{
+		{
+			Name:   "checkout",
+			Weight: 1,
+			Steps: []*Step{
+				{Name: "home", Step: runner.Step{Action: runner.StepNavigate, URL: "http://localhost:8080/"}},
+				{Name: "buy", Step: runner.Step{Action: runner.StepClick, Selector: "#buy"}},
+				{Name: "read", Step: runner.Step{Action: runner.StepSleep, Duration: 100 * time.Millisecond}},
+			},
+		},
+	}
+
+	s := New()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeFake,
+		Journeys:    journeys,
+		MinWait:     100 * time.Millisecond,
+		MaxWait:     100 * time.Millisecond,
+		Amount:      1,
+		Duration:    time.Second,
+	}, results)
+	close(results)
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, len(results), 6)
+
+	var steps []string
+	for res := range results {
+		assert.Equal(t, "checkout", res.Journey)
+		assert.Empty(t, res.Error)
+		steps = append(steps, res.Step)
+	}
+
+	assert.Equal(t, []string{"home", "buy", "read", "home", "buy", "read"}, steps[:6])
+}
+
+func TestService_RunJourneys_FailedStep(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	results := make(chan EndpointResult, 1000)
+	journeys := []*Journey{
+		{
+			Name:   "login",
+			Weight: 1,
+			Steps: []*Step{
+				{Name: "home", Step: runner.Step{Action: runner.StepNavigate, URL: srv.URL}},
+				{Name: "submit", Step: runner.Step{Action: runner.StepSubmit, Selector: "form"}},
+				{Name: "never", Step: runner.Step{Action: runner.StepNavigate, URL: srv.URL}},
+			},
+		},
+	}
+
+	s := New()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeHTTP,
+		Journeys:    journeys,
+		MinWait:     100 * time.Millisecond,
+		MaxWait:     100 * time.Millisecond,
+		Amount:      1,
+		Duration:    500 * time.Millisecond,
+	}, results)
+	close(results)
+
+	assert.NoError(t, err)
+
+	for res := range results {
+		switch res.Step {
+		case "home":
+			assert.Equal(t, http.StatusOK, res.HTTPStatusCode)
+			assert.Equal(t, srv.URL, res.URL)
+			assert.Greater(t, int64(res.Duration), int64(0))
+		case "submit":
+			assert.Equal(t, runner.ErrUnsupportedStep.Error(), res.Error)
+		default:
+			t.Errorf("unexpected step %s", res.Step)
+		}
+	}
+}
+
+func TestService_RunNoEndpoints(t *testing.T) {
+	err := New().Run(context.Background(), &Config{
+		BrowserType: BrowserTypeFake,
+		Amount:      1,
+	}, make(chan EndpointResult))
+
+	assert.Equal(t, ErrNoEndpoints, err)
+}
+
+func TestService_RunSetup(t *testing.T) {
+	results := make(chan EndpointResult, 1000)
+
+	s := New()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeFake,
+		Endpoints:   []*Endpoint{{URL: "http://localhost:8080/", Weight: 1}},
+		Setup: []*Step{
+			{Name: "login", Step: runner.Step{Action: runner.StepNavigate, URL: "http://localhost:8080/login?user=${user}"}},
+		},
+		UserData: &UserData{
+			Columns: []string{"user", "password"},
+			Rows:    [][]string{{"jane", "secret"}, {"john", "secret"}},
+		},
+		MinWait:  100 * time.Millisecond,
+		MaxWait:  100 * time.Millisecond,
+		Amount:   2,
+		Duration: time.Second,
+	}, results)
+	close(results)
+
+	assert.NoError(t, err)
+
+	logins := make(map[string]int)
+	for res := range results {
+		if res.Journey == SetupJourney {
+			logins[res.URL]++
+		}
+	}
+
+	// every user logs in once with its own account
+	assert.Equal(t, map[string]int{
+		"http://localhost:8080/login?user=jane": 1,
+		"http://localhost:8080/login?user=john": 1,
+	}, logins)
+}
+
+func TestService_RunSetup_Failed(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	results := make(chan EndpointResult, 1000)
+
+	s := New()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeHTTP,
+		Endpoints:   []*Endpoint{{URL: srv.URL, Weight: 1}},
+		Setup: []*Step{
+			{Name: "login", Step: runner.Step{Action: runner.StepSubmit, Selector: "form"}},
+		},
+		MinWait:  100 * time.Millisecond,
+		MaxWait:  100 * time.Millisecond,
+		Amount:   1,
+		Duration: 500 * time.Millisecond,
+	}, results)
+	close(results)
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, len(results), 3)
+
+	// the setup is repeated and endpoints are never requested
+	for res := range results {
+		assert.Equal(t, SetupJourney, res.Journey)
+		assert.Equal(t, runner.ErrUnsupportedStep.Error(), res.Error)
+	}
+}
+
+func TestService_RunColdVisits(t *testing.T) {
+	results := make(chan EndpointResult, 1000)
+
+	s := New()
+	err := s.Run(context.Background(), &Config{
+		BrowserType: BrowserTypeFake,
+		Endpoints:   []*Endpoint{{URL: "http://localhost:8080/", Weight: 1}},
+		Setup: []*Step{
+			{Name: "login", Step: runner.Step{Action: runner.StepNavigate, URL: "http://localhost:8080/login"}},
+		},
+		ColdVisits: 100,
+		MinWait:    100 * time.Millisecond,
+		MaxWait:    100 * time.Millisecond,
+		Amount:     1,
+		Duration:   time.Second,
+	}, results)
+	close(results)
+
+	assert.NoError(t, err)
+
+	var logins, visits int
+	for res := range results {
+		assert.True(t, res.ColdCache)
+
+		if res.Journey == SetupJourney {
+			logins++
+		} else {
+			visits++
+		}
+	}
+
+	// every cold visit starts with a new login
+	assert.Greater(t, visits, 1)
+	assert.GreaterOrEqual(t, logins, visits)
+}
+
+func TestUserData_Vars(t *testing.T) {
+	var d *UserData
+	assert.Nil(t, d.vars(0))
+
+	d = &UserData{
+		Columns: []string{"user", "password"},
+		Rows:    [][]string{{"jane", "secret"}, {"john"}},
+	}
+
+	assert.Equal(t, "jane:secret", d.vars(0).Replace("${user}:${password}"))
+	assert.Equal(t, "john:${password}", d.vars(1).Replace("${user}:${password}"))
+	assert.Equal(t, "jane", d.vars(2).Replace("${user}"))
+}
+
+func TestArtifactBudget(t *testing.T) {
+	assert.Nil(t, newArtifactBudget(nil))
+	assert.Nil(t, newArtifactBudget(&Artifacts{HTML: true}))
+
+	var b *artifactBudget
+	assert.Nil(t, b.capture(context.Background()))
+
+	// runners without capture support spend the budget, but capture nothing
+	b = newArtifactBudget(&Artifacts{Max: 1})
+	ctx := runner.NewHTTPRunner(1, false).WithContext(context.Background())
+	assert.Nil(t, b.capture(ctx))
+	assert.Equal(t, int32(1), b.captured)
+}
+
+func TestHARSampler(t *testing.T) {
+	assert.Nil(t, newHARSampler(nil))
+	assert.Nil(t, newHARSampler(&HARSampling{First: 1}))
+
+	var s *harSampler
+	assert.Nil(t, s.sample("http://foo.bar", &runner.Result{HAR: &runner.HAR{}}))
+
+	load := func(d time.Duration) *runner.Result {
+		return &runner.Result{
+			Timing: runner.PageTiming{Load: d},
+			HAR:    &runner.HAR{Log: runner.HARLog{Version: "1.2"}},
+		}
+	}
+
+	s = newHARSampler(&HARSampling{First: 2, Slower: time.Second, Max: 4})
+
+	// the first loads of every endpoint are sampled
+	assert.NotNil(t, s.sample("http://foo.bar", load(100*time.Millisecond)))
+	assert.NotNil(t, s.sample("http://foo.bar", load(100*time.Millisecond)))
+	assert.Nil(t, s.sample("http://foo.bar", load(100*time.Millisecond)))
+	assert.NotNil(t, s.sample("http://foo.bar/cart", load(100*time.Millisecond)))
+
+	// slow loads are sampled, until the budget is spent
+	b := s.sample("http://foo.bar", load(2*time.Second))
+	assert.JSONEq(t, `{"log":{"version":"1.2","creator":{"name":"","version":""},"pages":null,"entries":null}}`, string(b))
+	assert.Nil(t, s.sample("http://foo.bar", load(2*time.Second)))
+
+	// results without HAR aren't sampled
+	s = newHARSampler(&HARSampling{First: 1, Max: 1})
+	assert.Nil(t, s.sample("http://foo.bar", &runner.Result{}))
+}
+
+func TestNewChromePool(t *testing.T) {
+	ctx := context.Background()
+
+	// runners without a pool start their own chrome process
+	p, err := newChromePool(ctx, &Config{BrowserType: BrowserTypeChrome})
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	remote := &runner.ChromeConfig{RemoteURLs: []string{"ws://127.0.0.1:1/devtools/browser/none"}}
+
+	p, err = newChromePool(ctx, &Config{BrowserType: BrowserTypeHTTP, Chrome: remote})
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	// remote chrome processes take precedence over launched ones
+	p, err = newChromePool(ctx, &Config{BrowserType: BrowserTypeChrome, ChromeProcesses: 2, Chrome: remote})
+	assert.Error(t, err)
+	assert.Nil(t, p)
+}
@@ -1,7 +1,10 @@
 package loadtest
 
 import (
+	"strings"
 	"time"
+
+	"github.com/dkorittki/loago/pkg/worker/runner"
 )
 
 // An Endpoint represents a URL and a weight indicating the "importance" of the URL.
@@ -12,6 +15,253 @@ type Endpoint struct {
 	// The "importance" of the URL. The higher the number,
 	// the more often a request on the endpoint will be made.
 	Weight uint
+
+	// Options applied to every request on the endpoint,
+	// i.e. extra headers, a user agent or basic auth credentials.
+	Options runner.RequestOptions
+}
+
+// A Journey is a scripted sequence of steps, which a runner performs one after another.
+type Journey struct {
+	// Name under which the results of the journey are reported.
+	Name string
+
+	// The "importance" of the journey, on the same scale as the weight of endpoints.
+	Weight uint
+
+	// Steps of the journey.
+	Steps []*Step
+}
+
+// A Step is a single action of a journey.
+type Step struct {
+	runner.Step
+
+	// Name under which the results of the step are reported.
+	Name string
+}
+
+// SetupJourney is the journey name of results of setup steps.
+const SetupJourney = "setup"
+
+// UserData provides a distinct row of values per runner.
+// Steps reference the values of the row of their runner as ${column}.
+type UserData struct {
+	// Columns contains the names of the values of each row.
+	Columns []string
+
+	// Rows contains the values of each user in the order of Columns.
+	Rows [][]string
+}
+
+// vars returns a replacer of the references to the values of row i.
+// Rows are reused, if there are less rows than runners.
+func (d *UserData) vars(i int) *strings.Replacer {
+	if d == nil || len(d.Rows) == 0 {
+		return nil
+	}
+
+	row := d.Rows[i%len(d.Rows)]
+
+	var oldnew []string
+	for j, c := range d.Columns {
+		if j < len(row) {
+			oldnew = append(oldnew, "${"+c+"}", row[j])
+		}
+	}
+
+	return strings.NewReplacer(oldnew...)
+}
+
+// An Emulation makes chrome runners emulate a device on a network.
+type Emulation struct {
+	runner.Emulation
+
+	// The "importance" of the emulation. The higher the number,
+	// the more runners emulate it.
+	Weight uint
+}
+
+// Ramp controls how the amount of users changes during a stage.
+type Ramp int
+
+const (
+	// RampLinear changes the amount of users linearly over the duration of a stage.
+	RampLinear Ramp = 0
+
+	// RampStep changes the amount of users at the beginning of a stage
+	// and holds it for the duration of the stage.
+	RampStep Ramp = 1
+)
+
+// A Stage is a part of a load profile.
+// It changes the amount of users from the target of the previous stage
+// (or zero for the first stage) to its own target.
+type Stage struct {
+	// Duration of the stage. A zero duration lasts forever.
+	Duration time.Duration
+
+	// Target is the amount of users at the end of the stage.
+	Target int
+
+	// Ramp controls how the amount of users changes towards the target.
+	Ramp Ramp
+}
+
+// Config configures a loadtest.
+type Config struct {
+	// BrowserType is the type of runners performing requests.
+	BrowserType BrowserType
+
+	// Endpoints control where and how often to perform requests.
+	Endpoints []*Endpoint
+
+	// Journeys are performed alongside endpoints, chosen by their weight.
+	Journeys []*Journey
+
+	// Setup steps are performed once by every runner before any endpoint or journey.
+	// A failed setup is repeated until it succeeds.
+	Setup []*Step
+
+	// UserData provides the values referenced by steps.
+	UserData *UserData
+
+	// ColdVisits is the percentage of iterations, before which a runner clears
+	// its cache and cookies to simulate a first-time visitor. Zero keeps
+	// the cache warm for the whole life of a runner, 100 keeps it cold.
+	ColdVisits int
+
+	// ChromeProcesses hosts chrome runners as browser contexts in this many
+	// shared chrome processes. Zero starts a chrome process per runner.
+	ChromeProcesses int
+
+	// Chrome configures how chrome processes are launched.
+	// It is a setting of the worker, not of the loadtest.
+	Chrome *runner.ChromeConfig
+
+	// Restarts controls how chrome runners with a crashed or wedged browser
+	// are replaced. It is a setting of the worker, not of the loadtest.
+	Restarts RestartPolicy
+
+	// Emulations are assigned to chrome runners by weight.
+	// Without emulations, runners use the plain browser.
+	Emulations []*Emulation
+
+	// MinWait is the minimum time a runner waits between two requests.
+	MinWait time.Duration
+
+	// MaxWait is the maximum time a runner waits between two requests.
+	MaxWait time.Duration
+
+	// Amount is the static amount of runners.
+	// It is ignored, if Stages are given.
+	Amount int
+
+	// Duration limits how long the loadtest runs.
+	// Zero runs the loadtest until it's canceled or every stage finished.
+	Duration time.Duration
+
+	// Stages form a load profile, which changes
+	// the amount of runners over time.
+	Stages []*Stage
+
+	// Subresources enables reporting every resource loaded by a page.
+	Subresources bool
+
+	// Cookies enables keeping cookies between requests of a HTTP runner.
+	Cookies bool
+
+	// Artifacts enables capturing the page of failed requests of chrome runners.
+	Artifacts *Artifacts
+
+	// HAR enables sampling page loads of endpoints by chrome runners as HAR.
+	HAR *HARSampling
+}
+
+// Artifacts configures capturing the page of failed requests,
+// i.e. responses with a HTTP status of 400 or above and failed steps.
+type Artifacts struct {
+	// HTML enables capturing the HTML of the page besides a screenshot.
+	HTML bool
+
+	// Max limits the amount of artifacts captured during the loadtest.
+	Max int
+
+	// MaxSize limits the size of screenshots and HTML in bytes.
+	// Larger screenshots are dropped, larger HTML is truncated.
+	MaxSize int
+}
+
+// RestartPolicy controls the replacement of chrome runners, whose browser
+// crashed, disconnected or stopped responding.
+type RestartPolicy struct {
+	// Max limits the amount of restarts of the runner of every user.
+	// Zero disables restarts, so a broken browser fails the loadtest.
+	Max int
+
+	// Backoff is the time to wait before the first restart of a runner.
+	// It doubles with every further restart of the same runner.
+	Backoff time.Duration
+}
+
+// maxRestartBackoff limits the backoff of a restart.
+const maxRestartBackoff = time.Minute
+
+// RestartTimeout is the timeout of loading the pages of endpoints without a
+// timeout, if restarts are enabled. A browser, which stops responding, is
+// only noticed once a page load times out, so it would hang forever otherwise.
+const RestartTimeout = 2 * time.Minute
+
+// withTimeouts returns endpoints, but endpoints without a timeout
+// are replaced by copies with RestartTimeout, if restarts are enabled.
+func (p RestartPolicy) withTimeouts(endpoints []*Endpoint) []*Endpoint {
+	if p.Max <= 0 {
+		return endpoints
+	}
+
+	e := make([]*Endpoint, len(endpoints))
+	for i, v := range endpoints {
+		e[i] = v
+		if v.Options.Timeout <= 0 {
+			endpoint := *v
+			endpoint.Options.Timeout = RestartTimeout
+			e[i] = &endpoint
+		}
+	}
+
+	return e
+}
+
+// backoff returns the time to wait before restarting a runner,
+// which was restarted n times before.
+func (p RestartPolicy) backoff(n int) time.Duration {
+	d := p.Backoff
+	for i := 0; i < n && d < maxRestartBackoff; i++ {
+		d *= 2
+	}
+
+	if d > maxRestartBackoff {
+		return maxRestartBackoff
+	}
+
+	return d
+}
+
+// EventRestart is the event of a result, which reports the restart of a runner.
+const EventRestart = "restart"
+
+// HARSampling configures which page loads of endpoints are sampled as HAR,
+// which are the first loads of every endpoint and every slow load.
+type HARSampling struct {
+	// First is the amount of page loads per endpoint, which are sampled.
+	First int
+
+	// Slower samples every page load, which took at least this long
+	// until the load event, if it's greater than zero.
+	Slower time.Duration
+
+	// Max limits the amount of HARs sampled during the loadtest.
+	Max int
 }
 
 // EndpointResult contains all necessary information of a runners response results.
@@ -30,6 +280,58 @@ type EndpointResult struct {
 
 	// Cached indicates if the browser cache was used instead of performing a real request.
 	Cached bool
+
+	// Timing contains the page load timings measured by the runners browser.
+	Timing runner.PageTiming
+
+	// Network contains the network phases of the runners request.
+	Network runner.NetworkTiming
+
+	// Subresources contains every resource loaded by the page, if enabled.
+	Subresources []*runner.Subresource
+
+	// Journey and Step name the step of a journey, which the result belongs to.
+	// URL is the page URL once the step finished.
+	Journey string
+	Step    string
+
+	// Duration is how long the step took, or how long
+	// a failed request took until it failed.
+	Duration time.Duration
+
+	// Error describes why the request or step failed, if it did.
+	Error string
+
+	// ErrorClass classifies why the request or step failed, i.e. "dns",
+	// "timeout" or "assertion". It's empty for successful requests and steps,
+	// and steps failing for other reasons, like an unsupported action.
+	ErrorClass runner.ErrorClass
+
+	// Emulation is the name of the emulation of the runner, if any.
+	Emulation string
+
+	// ColdCache indicates that the runner cleared its cache and cookies
+	// before the iteration, which the result belongs to.
+	ColdCache bool
+
+	// Artifact shows the page of a failed request, if it was captured.
+	Artifact *runner.Artifact
+
+	// JSErrors is the amount of JavaScript errors of the page
+	// and JSErrorMessages their distinct messages.
+	JSErrors        int
+	JSErrorMessages []string
+
+	// HAR contains every request of the page load as HAR 1.2 in JSON, if it was sampled.
+	HAR []byte
+
+	// Event reports something that happened to the runner instead
+	// of a request, i.e. EventRestart. Error describes its cause.
+	Event string
+
+	// User is the ID of the user, whose runner requested the page, performed
+	// the step or restarted. A restarted runner keeps the ID of its user.
+	User int
 }
 
 // BrowserType represents a type of browser.
@@ -41,4 +343,7 @@ const (
 
 	// BrowserTypeChrome represents a chrome browser type.
 	BrowserTypeChrome BrowserType = 1
+
+	// BrowserTypeHTTP represents plain HTTP requests without a browser.
+	BrowserTypeHTTP BrowserType = 2
 )
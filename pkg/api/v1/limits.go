@@ -0,0 +1,6 @@
+package api
+
+// MaxMessageSize limits the size of gRPC messages between instructors and workers
+// in bytes. Results may carry HARs, artifacts and subresources, which exceed the
+// default limit of 4 MiB of gRPC, so both ends set this limit explicitly.
+const MaxMessageSize = 16 * 1024 * 1024
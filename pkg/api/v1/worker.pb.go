@@ -35,6 +35,8 @@ type RunRequest_BrowserType int32
 const (
 	RunRequest_FAKE   RunRequest_BrowserType = 0
 	RunRequest_CHROME RunRequest_BrowserType = 1
+	// HTTP performs plain HTTP requests without a browser.
+	RunRequest_HTTP RunRequest_BrowserType = 2
 )
 
 // Enum value maps for RunRequest_BrowserType.
@@ -42,10 +44,12 @@ var (
 	RunRequest_BrowserType_name = map[int32]string{
 		0: "FAKE",
 		1: "CHROME",
+		2: "HTTP",
 	}
 	RunRequest_BrowserType_value = map[string]int32{
 		"FAKE":   0,
 		"CHROME": 1,
+		"HTTP":   2,
 	}
 )
 
@@ -76,16 +80,163 @@ func (RunRequest_BrowserType) EnumDescriptor() ([]byte, []int) {
 	return file_worker_proto_rawDescGZIP(), []int{0, 0}
 }
 
+type RunRequest_Stage_Ramp int32
+
+const (
+	RunRequest_Stage_LINEAR RunRequest_Stage_Ramp = 0
+	RunRequest_Stage_STEP   RunRequest_Stage_Ramp = 1
+)
+
+// Enum value maps for RunRequest_Stage_Ramp.
+var (
+	RunRequest_Stage_Ramp_name = map[int32]string{
+		0: "LINEAR",
+		1: "STEP",
+	}
+	RunRequest_Stage_Ramp_value = map[string]int32{
+		"LINEAR": 0,
+		"STEP":   1,
+	}
+)
+
+func (x RunRequest_Stage_Ramp) Enum() *RunRequest_Stage_Ramp {
+	p := new(RunRequest_Stage_Ramp)
+	*p = x
+	return p
+}
+
+func (x RunRequest_Stage_Ramp) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RunRequest_Stage_Ramp) Descriptor() protoreflect.EnumDescriptor {
+	return file_worker_proto_enumTypes[1].Descriptor()
+}
+
+func (RunRequest_Stage_Ramp) Type() protoreflect.EnumType {
+	return &file_worker_proto_enumTypes[1]
+}
+
+func (x RunRequest_Stage_Ramp) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RunRequest_Stage_Ramp.Descriptor instead.
+func (RunRequest_Stage_Ramp) EnumDescriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0, 1, 0}
+}
+
+type RunRequest_Journey_Step_Action int32
+
+const (
+	RunRequest_Journey_Step_NAVIGATE          RunRequest_Journey_Step_Action = 0
+	RunRequest_Journey_Step_CLICK             RunRequest_Journey_Step_Action = 1
+	RunRequest_Journey_Step_TYPE              RunRequest_Journey_Step_Action = 2
+	RunRequest_Journey_Step_SUBMIT            RunRequest_Journey_Step_Action = 3
+	RunRequest_Journey_Step_WAIT              RunRequest_Journey_Step_Action = 4
+	RunRequest_Journey_Step_SLEEP             RunRequest_Journey_Step_Action = 5
+	RunRequest_Journey_Step_ASSERT_TEXT       RunRequest_Journey_Step_Action = 6
+	RunRequest_Journey_Step_SET_COOKIE        RunRequest_Journey_Step_Action = 7
+	RunRequest_Journey_Step_SET_LOCAL_STORAGE RunRequest_Journey_Step_Action = 8
+)
+
+// Enum value maps for RunRequest_Journey_Step_Action.
+var (
+	RunRequest_Journey_Step_Action_name = map[int32]string{
+		0: "NAVIGATE",
+		1: "CLICK",
+		2: "TYPE",
+		3: "SUBMIT",
+		4: "WAIT",
+		5: "SLEEP",
+		6: "ASSERT_TEXT",
+		7: "SET_COOKIE",
+		8: "SET_LOCAL_STORAGE",
+	}
+	RunRequest_Journey_Step_Action_value = map[string]int32{
+		"NAVIGATE":          0,
+		"CLICK":             1,
+		"TYPE":              2,
+		"SUBMIT":            3,
+		"WAIT":              4,
+		"SLEEP":             5,
+		"ASSERT_TEXT":       6,
+		"SET_COOKIE":        7,
+		"SET_LOCAL_STORAGE": 8,
+	}
+)
+
+func (x RunRequest_Journey_Step_Action) Enum() *RunRequest_Journey_Step_Action {
+	p := new(RunRequest_Journey_Step_Action)
+	*p = x
+	return p
+}
+
+func (x RunRequest_Journey_Step_Action) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RunRequest_Journey_Step_Action) Descriptor() protoreflect.EnumDescriptor {
+	return file_worker_proto_enumTypes[2].Descriptor()
+}
+
+func (RunRequest_Journey_Step_Action) Type() protoreflect.EnumType {
+	return &file_worker_proto_enumTypes[2]
+}
+
+func (x RunRequest_Journey_Step_Action) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RunRequest_Journey_Step_Action.Descriptor instead.
+func (RunRequest_Journey_Step_Action) EnumDescriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0, 2, 0, 0}
+}
+
 type RunRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// Endpoints and Journeys are chosen by weight, at least one of both is required.
 	Endpoints   []*RunRequest_Endpoint `protobuf:"bytes,1,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
 	Amount      uint32                 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
 	Type        RunRequest_BrowserType `protobuf:"varint,3,opt,name=type,proto3,enum=v1.RunRequest_BrowserType" json:"type,omitempty"`
 	MinWaitTime uint32                 `protobuf:"varint,4,opt,name=minWaitTime,proto3" json:"minWaitTime,omitempty"`
 	MaxWaitTime uint32                 `protobuf:"varint,5,opt,name=maxWaitTime,proto3" json:"maxWaitTime,omitempty"`
+	// Duration of the loadtest in milliseconds.
+	// The worker stops the loadtest on its own once it elapsed.
+	// Zero runs the loadtest until the request is canceled.
+	Duration uint32 `protobuf:"varint,6,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Stages form a load profile, which replaces the static amount of users.
+	// Stages run one after another and the loadtest ends after the last one.
+	Stages []*RunRequest_Stage `protobuf:"bytes,7,rep,name=stages,proto3" json:"stages,omitempty"`
+	// Subresources enables reporting every resource loaded by a page
+	// as part of the result of the page.
+	Subresources bool `protobuf:"varint,8,opt,name=subresources,proto3" json:"subresources,omitempty"`
+	// Cookies enables keeping cookies between the requests of a user.
+	// Only used by the HTTP browser type, browsers always keep cookies.
+	Cookies  bool                  `protobuf:"varint,9,opt,name=cookies,proto3" json:"cookies,omitempty"`
+	Journeys []*RunRequest_Journey `protobuf:"bytes,10,rep,name=journeys,proto3" json:"journeys,omitempty"`
+	// Setup steps are performed once by every user before any endpoint or journey,
+	// i.e. to log in. Failed setups are repeated, until they succeed.
+	Setup    []*RunRequest_Journey_Step `protobuf:"bytes,11,rep,name=setup,proto3" json:"setup,omitempty"`
+	UserData *RunRequest_UserData       `protobuf:"bytes,12,opt,name=userData,proto3" json:"userData,omitempty"`
+	// Emulations are assigned to users by weight, only used by the chrome browser type.
+	Emulations []*RunRequest_Emulation `protobuf:"bytes,13,rep,name=emulations,proto3" json:"emulations,omitempty"`
+	// ColdVisits is the percentage of iterations, before which a user clears its
+	// cache and cookies like a first-time visitor. Zero keeps the cache warm.
+	ColdVisits uint32 `protobuf:"varint,14,opt,name=coldVisits,proto3" json:"coldVisits,omitempty"`
+	// ChromeProcesses hosts chrome users as isolated browser contexts in this many
+	// shared chrome processes. Zero starts a chrome process per user.
+	ChromeProcesses uint32 `protobuf:"varint,15,opt,name=chromeProcesses,proto3" json:"chromeProcesses,omitempty"`
+	// No artifacts are captured, if unset.
+	Artifacts *RunRequest_Artifacts `protobuf:"bytes,16,opt,name=artifacts,proto3" json:"artifacts,omitempty"`
+	// Timeout of loading a page in milliseconds, for endpoints and navigation
+	// steps without their own timeout. Zero waits until the page loaded.
+	Timeout uint32 `protobuf:"varint,17,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// No HARs are sampled, if unset.
+	Har *RunRequest_HAR `protobuf:"bytes,18,opt,name=har,proto3" json:"har,omitempty"`
 }
 
 func (x *RunRequest) Reset() {
@@ -155,6 +306,97 @@ func (x *RunRequest) GetMaxWaitTime() uint32 {
 	return 0
 }
 
+func (x *RunRequest) GetDuration() uint32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *RunRequest) GetStages() []*RunRequest_Stage {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+func (x *RunRequest) GetSubresources() bool {
+	if x != nil {
+		return x.Subresources
+	}
+	return false
+}
+
+func (x *RunRequest) GetCookies() bool {
+	if x != nil {
+		return x.Cookies
+	}
+	return false
+}
+
+func (x *RunRequest) GetJourneys() []*RunRequest_Journey {
+	if x != nil {
+		return x.Journeys
+	}
+	return nil
+}
+
+func (x *RunRequest) GetSetup() []*RunRequest_Journey_Step {
+	if x != nil {
+		return x.Setup
+	}
+	return nil
+}
+
+func (x *RunRequest) GetUserData() *RunRequest_UserData {
+	if x != nil {
+		return x.UserData
+	}
+	return nil
+}
+
+func (x *RunRequest) GetEmulations() []*RunRequest_Emulation {
+	if x != nil {
+		return x.Emulations
+	}
+	return nil
+}
+
+func (x *RunRequest) GetColdVisits() uint32 {
+	if x != nil {
+		return x.ColdVisits
+	}
+	return 0
+}
+
+func (x *RunRequest) GetChromeProcesses() uint32 {
+	if x != nil {
+		return x.ChromeProcesses
+	}
+	return 0
+}
+
+func (x *RunRequest) GetArtifacts() *RunRequest_Artifacts {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+func (x *RunRequest) GetTimeout() uint32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *RunRequest) GetHar() *RunRequest_HAR {
+	if x != nil {
+		return x.Har
+	}
+	return nil
+}
+
 type EndpointResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -165,6 +407,54 @@ type EndpointResult struct {
 	HttpStatusMessage string `protobuf:"bytes,3,opt,name=httpStatusMessage,proto3" json:"httpStatusMessage,omitempty"`
 	Ttfb              int32  `protobuf:"varint,4,opt,name=ttfb,proto3" json:"ttfb,omitempty"`
 	Cached            bool   `protobuf:"varint,5,opt,name=cached,proto3" json:"cached,omitempty"`
+	// Page load timings in milliseconds since navigation start,
+	// zero if the browser didn't report them.
+	DomContentLoaded       int32   `protobuf:"varint,6,opt,name=domContentLoaded,proto3" json:"domContentLoaded,omitempty"`
+	Load                   int32   `protobuf:"varint,7,opt,name=load,proto3" json:"load,omitempty"`
+	FirstPaint             int32   `protobuf:"varint,8,opt,name=firstPaint,proto3" json:"firstPaint,omitempty"`
+	FirstContentfulPaint   int32   `protobuf:"varint,9,opt,name=firstContentfulPaint,proto3" json:"firstContentfulPaint,omitempty"`
+	LargestContentfulPaint int32   `protobuf:"varint,10,opt,name=largestContentfulPaint,proto3" json:"largestContentfulPaint,omitempty"`
+	CumulativeLayoutShift  float32 `protobuf:"fixed32,11,opt,name=cumulativeLayoutShift,proto3" json:"cumulativeLayoutShift,omitempty"`
+	// Network phases of the request in milliseconds,
+	// zero if they didn't happen, i.e. DNS of a reused connection.
+	Dns     int32 `protobuf:"varint,12,opt,name=dns,proto3" json:"dns,omitempty"`
+	Connect int32 `protobuf:"varint,13,opt,name=connect,proto3" json:"connect,omitempty"`
+	Ssl     int32 `protobuf:"varint,14,opt,name=ssl,proto3" json:"ssl,omitempty"`
+	Send    int32 `protobuf:"varint,15,opt,name=send,proto3" json:"send,omitempty"`
+	Wait    int32 `protobuf:"varint,16,opt,name=wait,proto3" json:"wait,omitempty"`
+	// Subresources loaded by the page, only reported if requested.
+	Subresources []*EndpointResult_Subresource `protobuf:"bytes,17,rep,name=subresources,proto3" json:"subresources,omitempty"`
+	// Journey and step, if the result belongs to a step of a journey.
+	// Its url is the page url once the step finished.
+	Journey string `protobuf:"bytes,18,opt,name=journey,proto3" json:"journey,omitempty"`
+	Step    string `protobuf:"bytes,19,opt,name=step,proto3" json:"step,omitempty"`
+	// Duration of the step in milliseconds.
+	Duration int32 `protobuf:"varint,20,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Error describes why the request or step failed, if it did.
+	// A failed step ends the journey.
+	Error string `protobuf:"bytes,21,opt,name=error,proto3" json:"error,omitempty"`
+	// Emulation of the user, if any.
+	Emulation string `protobuf:"bytes,22,opt,name=emulation,proto3" json:"emulation,omitempty"`
+	// ColdCache is true, if the user cleared its cache and cookies
+	// before the visit, which the result belongs to.
+	ColdCache bool `protobuf:"varint,23,opt,name=coldCache,proto3" json:"coldCache,omitempty"`
+	// Artifact of the page, if the request failed and it was captured.
+	Artifact *EndpointResult_Artifact `protobuf:"bytes,24,opt,name=artifact,proto3" json:"artifact,omitempty"`
+	// Amount of JavaScript errors of the page, which are uncaught exceptions,
+	// console errors and errors logged by the browser, and their distinct messages.
+	JsErrors        int32    `protobuf:"varint,25,opt,name=jsErrors,proto3" json:"jsErrors,omitempty"`
+	JsErrorMessages []string `protobuf:"bytes,26,rep,name=jsErrorMessages,proto3" json:"jsErrorMessages,omitempty"`
+	// ErrorClass classifies why the request failed, one of "dns", "connect",
+	// "tls", "timeout", "navigation", "no-response" or "assertion".
+	ErrorClass string `protobuf:"bytes,27,opt,name=errorClass,proto3" json:"errorClass,omitempty"`
+	// Event reports something that happened to the runner instead of a request,
+	// i.e. "restart", if it replaced a crashed browser. Error describes its cause.
+	Event string `protobuf:"bytes,28,opt,name=event,proto3" json:"event,omitempty"`
+	// HAR 1.2 of the page load in JSON, if it was sampled.
+	Har []byte `protobuf:"bytes,29,opt,name=har,proto3" json:"har,omitempty"`
+	// ID of the user of the worker, which requested the page, performed
+	// the step or restarted. A restarted user keeps its ID.
+	User int32 `protobuf:"varint,30,opt,name=user,proto3" json:"user,omitempty"`
 }
 
 func (x *EndpointResult) Reset() {
@@ -234,6 +524,181 @@ func (x *EndpointResult) GetCached() bool {
 	return false
 }
 
+func (x *EndpointResult) GetDomContentLoaded() int32 {
+	if x != nil {
+		return x.DomContentLoaded
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetLoad() int32 {
+	if x != nil {
+		return x.Load
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetFirstPaint() int32 {
+	if x != nil {
+		return x.FirstPaint
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetFirstContentfulPaint() int32 {
+	if x != nil {
+		return x.FirstContentfulPaint
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetLargestContentfulPaint() int32 {
+	if x != nil {
+		return x.LargestContentfulPaint
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetCumulativeLayoutShift() float32 {
+	if x != nil {
+		return x.CumulativeLayoutShift
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetDns() int32 {
+	if x != nil {
+		return x.Dns
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetConnect() int32 {
+	if x != nil {
+		return x.Connect
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetSsl() int32 {
+	if x != nil {
+		return x.Ssl
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetSend() int32 {
+	if x != nil {
+		return x.Send
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetWait() int32 {
+	if x != nil {
+		return x.Wait
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetSubresources() []*EndpointResult_Subresource {
+	if x != nil {
+		return x.Subresources
+	}
+	return nil
+}
+
+func (x *EndpointResult) GetJourney() string {
+	if x != nil {
+		return x.Journey
+	}
+	return ""
+}
+
+func (x *EndpointResult) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *EndpointResult) GetDuration() int32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *EndpointResult) GetEmulation() string {
+	if x != nil {
+		return x.Emulation
+	}
+	return ""
+}
+
+func (x *EndpointResult) GetColdCache() bool {
+	if x != nil {
+		return x.ColdCache
+	}
+	return false
+}
+
+func (x *EndpointResult) GetArtifact() *EndpointResult_Artifact {
+	if x != nil {
+		return x.Artifact
+	}
+	return nil
+}
+
+func (x *EndpointResult) GetJsErrors() int32 {
+	if x != nil {
+		return x.JsErrors
+	}
+	return 0
+}
+
+func (x *EndpointResult) GetJsErrorMessages() []string {
+	if x != nil {
+		return x.JsErrorMessages
+	}
+	return nil
+}
+
+func (x *EndpointResult) GetErrorClass() string {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return ""
+}
+
+func (x *EndpointResult) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *EndpointResult) GetHar() []byte {
+	if x != nil {
+		return x.Har
+	}
+	return nil
+}
+
+func (x *EndpointResult) GetUser() int32 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
 type PingRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -334,6 +799,19 @@ type RunRequest_Endpoint struct {
 
 	Url    string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
 	Weight uint32 `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	// Headers are sent in addition to the headers of the browser.
+	Headers map[string]string `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// UserAgent overrides the user agent of the browser, if not empty.
+	UserAgent string `protobuf:"bytes,4,opt,name=userAgent,proto3" json:"userAgent,omitempty"`
+	// Basic auth credentials, sent if not empty.
+	Username string `protobuf:"bytes,5,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,6,opt,name=password,proto3" json:"password,omitempty"`
+	// Timeout of loading the page in milliseconds,
+	// zero uses the timeout of the request.
+	Timeout uint32 `protobuf:"varint,7,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// OriginOnly sends headers and credentials only to the origin of url.
+	// Chrome intercepts these requests, which skews their timings.
+	OriginOnly bool `protobuf:"varint,8,opt,name=originOnly,proto3" json:"originOnly,omitempty"`
 }
 
 func (x *RunRequest_Endpoint) Reset() {
@@ -382,62 +860,1114 @@ func (x *RunRequest_Endpoint) GetWeight() uint32 {
 	return 0
 }
 
-var File_worker_proto protoreflect.FileDescriptor
+func (x *RunRequest_Endpoint) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
 
-var file_worker_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
-	0x76, 0x31, 0x1a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d,
-	0x77, 0x69, 0x74, 0x6b, 0x6f, 0x77, 0x2f, 0x67, 0x6f, 0x2d, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2d,
-	0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64,
-	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8a, 0x03, 0x0a, 0x0a, 0x52,
-	0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x09, 0x65, 0x6e, 0x64,
-	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76,
-	0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x6e, 0x64,
-	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x09, 0xe2, 0xdf, 0x1f, 0x05, 0x60, 0x01, 0x68, 0xe8, 0x07,
-	0x52, 0x09, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x06, 0x61,
-	0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x09, 0xe2, 0xdf, 0x1f,
-	0x05, 0x10, 0x00, 0x18, 0xf4, 0x03, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x37,
-	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x76,
-	0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x42, 0x72, 0x6f,
-	0x77, 0x73, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x42, 0x07, 0xe2, 0xdf, 0x1f, 0x03, 0x88, 0x01,
-	0x01, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2d, 0x0a, 0x0b, 0x6d, 0x69, 0x6e, 0x57, 0x61,
-	0x69, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x0b, 0xe2, 0xdf,
-	0x1f, 0x07, 0x10, 0x00, 0x18, 0x80, 0xdd, 0xdb, 0x01, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x57, 0x61,
-	0x69, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x57, 0x61, 0x69,
-	0x74, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x0b, 0xe2, 0xdf, 0x1f,
-	0x07, 0x10, 0x00, 0x18, 0x80, 0xdd, 0xdb, 0x01, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x57, 0x61, 0x69,
-	0x74, 0x54, 0x69, 0x6d, 0x65, 0x1a, 0x5b, 0x0a, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
-	0x74, 0x12, 0x2c, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x1a,
-	0xe2, 0xdf, 0x1f, 0x16, 0x0a, 0x14, 0x5e, 0x28, 0x68, 0x74, 0x74, 0x70, 0x7c, 0x68, 0x74, 0x74,
-	0x70, 0x73, 0x29, 0x3a, 0x2f, 0x2f, 0x28, 0x2e, 0x2a, 0x29, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12,
-	0x21, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x42,
+func (x *RunRequest_Endpoint) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *RunRequest_Endpoint) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *RunRequest_Endpoint) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *RunRequest_Endpoint) GetTimeout() uint32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *RunRequest_Endpoint) GetOriginOnly() bool {
+	if x != nil {
+		return x.OriginOnly
+	}
+	return false
+}
+
+// A Stage changes the amount of users to target over its duration.
+type RunRequest_Stage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Duration uint32                `protobuf:"varint,1,opt,name=duration,proto3" json:"duration,omitempty"`
+	Target   uint32                `protobuf:"varint,2,opt,name=target,proto3" json:"target,omitempty"`
+	Ramp     RunRequest_Stage_Ramp `protobuf:"varint,3,opt,name=ramp,proto3,enum=v1.RunRequest_Stage_Ramp" json:"ramp,omitempty"`
+}
+
+func (x *RunRequest_Stage) Reset() {
+	*x = RunRequest_Stage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_worker_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunRequest_Stage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRequest_Stage) ProtoMessage() {}
+
+func (x *RunRequest_Stage) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRequest_Stage.ProtoReflect.Descriptor instead.
+func (*RunRequest_Stage) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *RunRequest_Stage) GetDuration() uint32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *RunRequest_Stage) GetTarget() uint32 {
+	if x != nil {
+		return x.Target
+	}
+	return 0
+}
+
+func (x *RunRequest_Stage) GetRamp() RunRequest_Stage_Ramp {
+	if x != nil {
+		return x.Ramp
+	}
+	return RunRequest_Stage_LINEAR
+}
+
+// A Journey is a scripted sequence of steps performed by a user,
+// i.e. searching a product, adding it to the cart and checking out.
+type RunRequest_Journey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string                     `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Weight uint32                     `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	Steps  []*RunRequest_Journey_Step `protobuf:"bytes,3,rep,name=steps,proto3" json:"steps,omitempty"`
+}
+
+func (x *RunRequest_Journey) Reset() {
+	*x = RunRequest_Journey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_worker_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunRequest_Journey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRequest_Journey) ProtoMessage() {}
+
+func (x *RunRequest_Journey) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRequest_Journey.ProtoReflect.Descriptor instead.
+func (*RunRequest_Journey) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0, 2}
+}
+
+func (x *RunRequest_Journey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RunRequest_Journey) GetWeight() uint32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *RunRequest_Journey) GetSteps() []*RunRequest_Journey_Step {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+// UserData provides a distinct row of values per user. Journey and setup steps
+// reference the values of the row of their user as ${column}.
+type RunRequest_UserData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Columns []string                   `protobuf:"bytes,1,rep,name=columns,proto3" json:"columns,omitempty"`
+	Rows    []*RunRequest_UserData_Row `protobuf:"bytes,2,rep,name=rows,proto3" json:"rows,omitempty"`
+}
+
+func (x *RunRequest_UserData) Reset() {
+	*x = RunRequest_UserData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_worker_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunRequest_UserData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRequest_UserData) ProtoMessage() {}
+
+func (x *RunRequest_UserData) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRequest_UserData.ProtoReflect.Descriptor instead.
+func (*RunRequest_UserData) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0, 3}
+}
+
+func (x *RunRequest_UserData) GetColumns() []string {
+	if x != nil {
+		return x.Columns
+	}
+	return nil
+}
+
+func (x *RunRequest_UserData) GetRows() []*RunRequest_UserData_Row {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+// An Emulation makes a chrome browser emulate a device on a network.
+type RunRequest_Emulation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the emulation, under which results are reported.
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Weight uint32 `protobuf:"varint,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	// Viewport in CSS pixels, zero keeps the window size.
+	Width             uint32  `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height            uint32  `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	DeviceScaleFactor float64 `protobuf:"fixed64,5,opt,name=deviceScaleFactor,proto3" json:"deviceScaleFactor,omitempty"`
+	Mobile            bool    `protobuf:"varint,6,opt,name=mobile,proto3" json:"mobile,omitempty"`
+	UserAgent         string  `protobuf:"bytes,7,opt,name=userAgent,proto3" json:"userAgent,omitempty"`
+	// CPU slowdown factor, one or less disables throttling.
+	CpuThrottling float64 `protobuf:"fixed64,8,opt,name=cpuThrottling,proto3" json:"cpuThrottling,omitempty"`
+	// Latency in milliseconds and throughput in bytes per second,
+	// zero disables the limit.
+	Latency            uint32 `protobuf:"varint,9,opt,name=latency,proto3" json:"latency,omitempty"`
+	DownloadThroughput uint32 `protobuf:"varint,10,opt,name=downloadThroughput,proto3" json:"downloadThroughput,omitempty"`
+	UploadThroughput   uint32 `protobuf:"varint,11,opt,name=uploadThroughput,proto3" json:"uploadThroughput,omitempty"`
+}
+
+func (x *RunRequest_Emulation) Reset() {
+	*x = RunRequest_Emulation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_worker_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunRequest_Emulation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRequest_Emulation) ProtoMessage() {}
+
+func (x *RunRequest_Emulation) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRequest_Emulation.ProtoReflect.Descriptor instead.
+func (*RunRequest_Emulation) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0, 4}
+}
+
+func (x *RunRequest_Emulation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RunRequest_Emulation) GetWeight() uint32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *RunRequest_Emulation) GetWidth() uint32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *RunRequest_Emulation) GetHeight() uint32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *RunRequest_Emulation) GetDeviceScaleFactor() float64 {
+	if x != nil {
+		return x.DeviceScaleFactor
+	}
+	return 0
+}
+
+func (x *RunRequest_Emulation) GetMobile() bool {
+	if x != nil {
+		return x.Mobile
+	}
+	return false
+}
+
+func (x *RunRequest_Emulation) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *RunRequest_Emulation) GetCpuThrottling() float64 {
+	if x != nil {
+		return x.CpuThrottling
+	}
+	return 0
+}
+
+func (x *RunRequest_Emulation) GetLatency() uint32 {
+	if x != nil {
+		return x.Latency
+	}
+	return 0
+}
+
+func (x *RunRequest_Emulation) GetDownloadThroughput() uint32 {
+	if x != nil {
+		return x.DownloadThroughput
+	}
+	return 0
+}
+
+func (x *RunRequest_Emulation) GetUploadThroughput() uint32 {
+	if x != nil {
+		return x.UploadThroughput
+	}
+	return 0
+}
+
+// Artifacts configures capturing the page of failed chrome requests.
+type RunRequest_Artifacts struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Capture the HTML of the page besides a screenshot.
+	Html bool `protobuf:"varint,1,opt,name=html,proto3" json:"html,omitempty"`
+	// Maximum number of artifacts captured by this worker.
+	Max uint32 `protobuf:"varint,2,opt,name=max,proto3" json:"max,omitempty"`
+	// Maximum size of a screenshot or HTML in bytes. Larger screenshots are
+	// dropped, larger HTML is truncated. Bounded to stay within gRPC message limits.
+	MaxSize uint32 `protobuf:"varint,3,opt,name=maxSize,proto3" json:"maxSize,omitempty"`
+}
+
+func (x *RunRequest_Artifacts) Reset() {
+	*x = RunRequest_Artifacts{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_worker_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunRequest_Artifacts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRequest_Artifacts) ProtoMessage() {}
+
+func (x *RunRequest_Artifacts) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRequest_Artifacts.ProtoReflect.Descriptor instead.
+func (*RunRequest_Artifacts) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0, 5}
+}
+
+func (x *RunRequest_Artifacts) GetHtml() bool {
+	if x != nil {
+		return x.Html
+	}
+	return false
+}
+
+func (x *RunRequest_Artifacts) GetMax() uint32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *RunRequest_Artifacts) GetMaxSize() uint32 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+// HAR configures sampling page loads of endpoints by chrome users as HAR.
+type RunRequest_HAR struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Number of page loads per endpoint, which are sampled.
+	First uint32 `protobuf:"varint,1,opt,name=first,proto3" json:"first,omitempty"`
+	// Sample every page load, which took at least this many milliseconds
+	// until the load event. Zero disables sampling slow page loads.
+	Slower uint32 `protobuf:"varint,2,opt,name=slower,proto3" json:"slower,omitempty"`
+	// Maximum number of HARs sampled by this worker.
+	Max uint32 `protobuf:"varint,3,opt,name=max,proto3" json:"max,omitempty"`
+}
+
+func (x *RunRequest_HAR) Reset() {
+	*x = RunRequest_HAR{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_worker_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunRequest_HAR) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRequest_HAR) ProtoMessage() {}
+
+func (x *RunRequest_HAR) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRequest_HAR.ProtoReflect.Descriptor instead.
+func (*RunRequest_HAR) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0, 6}
+}
+
+func (x *RunRequest_HAR) GetFirst() uint32 {
+	if x != nil {
+		return x.First
+	}
+	return 0
+}
+
+func (x *RunRequest_HAR) GetSlower() uint32 {
+	if x != nil {
+		return x.Slower
+	}
+	return 0
+}
+
+func (x *RunRequest_HAR) GetMax() uint32 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+// A Step is a single action of a journey. Selectors are CSS selectors.
+type RunRequest_Journey_Step struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Name of the step, under which its results are reported.
+	Name     string                         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Action   RunRequest_Journey_Step_Action `protobuf:"varint,2,opt,name=action,proto3,enum=v1.RunRequest_Journey_Step_Action" json:"action,omitempty"`
+	Url      string                         `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Selector string                         `protobuf:"bytes,4,opt,name=selector,proto3" json:"selector,omitempty"`
+	Value    string                         `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	// Duration of a sleep step in milliseconds.
+	Duration uint32 `protobuf:"varint,6,opt,name=duration,proto3" json:"duration,omitempty"`
+	// Timeout of waiting for an element in milliseconds, zero uses
+	// the default of the worker. For navigation steps, it's the timeout
+	// of loading the page and zero uses the timeout of the request.
+	Timeout uint32 `protobuf:"varint,7,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Name of the cookie or local storage item to set.
+	Key string `protobuf:"bytes,8,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *RunRequest_Journey_Step) Reset() {
+	*x = RunRequest_Journey_Step{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_worker_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunRequest_Journey_Step) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRequest_Journey_Step) ProtoMessage() {}
+
+func (x *RunRequest_Journey_Step) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRequest_Journey_Step.ProtoReflect.Descriptor instead.
+func (*RunRequest_Journey_Step) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0, 2, 0}
+}
+
+func (x *RunRequest_Journey_Step) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RunRequest_Journey_Step) GetAction() RunRequest_Journey_Step_Action {
+	if x != nil {
+		return x.Action
+	}
+	return RunRequest_Journey_Step_NAVIGATE
+}
+
+func (x *RunRequest_Journey_Step) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *RunRequest_Journey_Step) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *RunRequest_Journey_Step) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *RunRequest_Journey_Step) GetDuration() uint32 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *RunRequest_Journey_Step) GetTimeout() uint32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *RunRequest_Journey_Step) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type RunRequest_UserData_Row struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Values []string `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+}
+
+func (x *RunRequest_UserData_Row) Reset() {
+	*x = RunRequest_UserData_Row{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_worker_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RunRequest_UserData_Row) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunRequest_UserData_Row) ProtoMessage() {}
+
+func (x *RunRequest_UserData_Row) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunRequest_UserData_Row.ProtoReflect.Descriptor instead.
+func (*RunRequest_UserData_Row) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{0, 3, 0}
+}
+
+func (x *RunRequest_UserData_Row) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// A Subresource is a resource loaded by the page, i.e. a script or XHR request.
+type EndpointResult_Subresource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url               string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Type              string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	HttpStatusCode    int32  `protobuf:"varint,3,opt,name=httpStatusCode,proto3" json:"httpStatusCode,omitempty"`
+	HttpStatusMessage string `protobuf:"bytes,4,opt,name=httpStatusMessage,proto3" json:"httpStatusMessage,omitempty"`
+	Ttfb              int32  `protobuf:"varint,5,opt,name=ttfb,proto3" json:"ttfb,omitempty"`
+	Size              int64  `protobuf:"varint,6,opt,name=size,proto3" json:"size,omitempty"`
+	Cached            bool   `protobuf:"varint,7,opt,name=cached,proto3" json:"cached,omitempty"`
+	Initiator         string `protobuf:"bytes,8,opt,name=initiator,proto3" json:"initiator,omitempty"`
+	InitiatorUrl      string `protobuf:"bytes,9,opt,name=initiatorUrl,proto3" json:"initiatorUrl,omitempty"`
+	Error             string `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *EndpointResult_Subresource) Reset() {
+	*x = EndpointResult_Subresource{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_worker_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EndpointResult_Subresource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndpointResult_Subresource) ProtoMessage() {}
+
+func (x *EndpointResult_Subresource) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndpointResult_Subresource.ProtoReflect.Descriptor instead.
+func (*EndpointResult_Subresource) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{1, 0}
+}
+
+func (x *EndpointResult_Subresource) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *EndpointResult_Subresource) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *EndpointResult_Subresource) GetHttpStatusCode() int32 {
+	if x != nil {
+		return x.HttpStatusCode
+	}
+	return 0
+}
+
+func (x *EndpointResult_Subresource) GetHttpStatusMessage() string {
+	if x != nil {
+		return x.HttpStatusMessage
+	}
+	return ""
+}
+
+func (x *EndpointResult_Subresource) GetTtfb() int32 {
+	if x != nil {
+		return x.Ttfb
+	}
+	return 0
+}
+
+func (x *EndpointResult_Subresource) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *EndpointResult_Subresource) GetCached() bool {
+	if x != nil {
+		return x.Cached
+	}
+	return false
+}
+
+func (x *EndpointResult_Subresource) GetInitiator() string {
+	if x != nil {
+		return x.Initiator
+	}
+	return ""
+}
+
+func (x *EndpointResult_Subresource) GetInitiatorUrl() string {
+	if x != nil {
+		return x.InitiatorUrl
+	}
+	return ""
+}
+
+func (x *EndpointResult_Subresource) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// An Artifact shows the page of a failed request.
+type EndpointResult_Artifact struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// JPEG screenshot of the viewport.
+	Screenshot []byte `protobuf:"bytes,2,opt,name=screenshot,proto3" json:"screenshot,omitempty"`
+	Html       string `protobuf:"bytes,3,opt,name=html,proto3" json:"html,omitempty"`
+}
+
+func (x *EndpointResult_Artifact) Reset() {
+	*x = EndpointResult_Artifact{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_worker_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EndpointResult_Artifact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndpointResult_Artifact) ProtoMessage() {}
+
+func (x *EndpointResult_Artifact) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndpointResult_Artifact.ProtoReflect.Descriptor instead.
+func (*EndpointResult_Artifact) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{1, 1}
+}
+
+func (x *EndpointResult_Artifact) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *EndpointResult_Artifact) GetScreenshot() []byte {
+	if x != nil {
+		return x.Screenshot
+	}
+	return nil
+}
+
+func (x *EndpointResult_Artifact) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+var File_worker_proto protoreflect.FileDescriptor
+
+var file_worker_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x77, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x02,
+	0x76, 0x31, 0x1a, 0x36, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6d,
+	0x77, 0x69, 0x74, 0x6b, 0x6f, 0x77, 0x2f, 0x67, 0x6f, 0x2d, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2d,
+	0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x6f, 0x72, 0x73, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc8, 0x14, 0x0a, 0x0a, 0x52,
+	0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3e, 0x0a, 0x09, 0x65, 0x6e, 0x64,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x45, 0x6e, 0x64,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x42, 0x07, 0xe2, 0xdf, 0x1f, 0x03, 0x68, 0xe8, 0x07, 0x52, 0x09,
+	0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x21, 0x0a, 0x06, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x09, 0xe2, 0xdf, 0x1f, 0x05, 0x10,
+	0x00, 0x18, 0x90, 0x4e, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x37, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x42, 0x72, 0x6f, 0x77, 0x73,
+	0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x42, 0x07, 0xe2, 0xdf, 0x1f, 0x03, 0x88, 0x01, 0x01, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2d, 0x0a, 0x0b, 0x6d, 0x69, 0x6e, 0x57, 0x61, 0x69, 0x74,
+	0x54, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x0b, 0xe2, 0xdf, 0x1f, 0x07,
+	0x10, 0x00, 0x18, 0x80, 0xdd, 0xdb, 0x01, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x57, 0x61, 0x69, 0x74,
+	0x54, 0x69, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x57, 0x61, 0x69, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x0b, 0xe2, 0xdf, 0x1f, 0x07, 0x10,
+	0x00, 0x18, 0x80, 0xdd, 0xdb, 0x01, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x57, 0x61, 0x69, 0x74, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x34, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x67, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e,
+	0x53, 0x74, 0x61, 0x67, 0x65, 0x42, 0x06, 0xe2, 0xdf, 0x1f, 0x02, 0x68, 0x64, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x67, 0x65, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x72, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x73, 0x75, 0x62,
+	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6f,
+	0x6b, 0x69, 0x65, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x63, 0x6f, 0x6f, 0x6b,
+	0x69, 0x65, 0x73, 0x12, 0x3a, 0x0a, 0x08, 0x6a, 0x6f, 0x75, 0x72, 0x6e, 0x65, 0x79, 0x73, 0x18,
+	0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x65, 0x79, 0x42, 0x06, 0xe2,
+	0xdf, 0x1f, 0x02, 0x68, 0x64, 0x52, 0x08, 0x6a, 0x6f, 0x75, 0x72, 0x6e, 0x65, 0x79, 0x73, 0x12,
+	0x39, 0x0a, 0x05, 0x73, 0x65, 0x74, 0x75, 0x70, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4a,
+	0x6f, 0x75, 0x72, 0x6e, 0x65, 0x79, 0x2e, 0x53, 0x74, 0x65, 0x70, 0x42, 0x06, 0xe2, 0xdf, 0x1f,
+	0x02, 0x68, 0x64, 0x52, 0x05, 0x73, 0x65, 0x74, 0x75, 0x70, 0x12, 0x33, 0x0a, 0x08, 0x75, 0x73,
+	0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x44, 0x61, 0x74, 0x61, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12,
+	0x40, 0x0a, 0x0a, 0x65, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0d, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x45, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x06, 0xe2,
+	0xdf, 0x1f, 0x02, 0x68, 0x64, 0x52, 0x0a, 0x65, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x26, 0x0a, 0x0a, 0x63, 0x6f, 0x6c, 0x64, 0x56, 0x69, 0x73, 0x69, 0x74, 0x73, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x06, 0xe2, 0xdf, 0x1f, 0x02, 0x18, 0x65, 0x52, 0x0a, 0x63,
+	0x6f, 0x6c, 0x64, 0x56, 0x69, 0x73, 0x69, 0x74, 0x73, 0x12, 0x31, 0x0a, 0x0f, 0x63, 0x68, 0x72,
+	0x6f, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x0d, 0x42, 0x07, 0xe2, 0xdf, 0x1f, 0x03, 0x18, 0xe8, 0x07, 0x52, 0x0f, 0x63, 0x68, 0x72,
+	0x6f, 0x6d, 0x65, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x09,
+	0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e,
+	0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x52, 0x09, 0x61, 0x72, 0x74, 0x69, 0x66,
+	0x61, 0x63, 0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18,
+	0x11, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x24,
+	0x0a, 0x03, 0x68, 0x61, 0x72, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x48, 0x41, 0x52, 0x52,
+	0x03, 0x68, 0x61, 0x72, 0x1a, 0xe7, 0x02, 0x0a, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x12, 0x2c, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x1a,
+	0xe2, 0xdf, 0x1f, 0x16, 0x0a, 0x14, 0x5e, 0x28, 0x68, 0x74, 0x74, 0x70, 0x7c, 0x68, 0x74, 0x74,
+	0x70, 0x73, 0x29, 0x3a, 0x2f, 0x2f, 0x28, 0x2e, 0x2a, 0x29, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12,
+	0x21, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x42,
 	0x09, 0xe2, 0xdf, 0x1f, 0x05, 0x10, 0x00, 0x18, 0xe8, 0x07, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67,
-	0x68, 0x74, 0x22, 0x23, 0x0a, 0x0b, 0x42, 0x72, 0x6f, 0x77, 0x73, 0x65, 0x72, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x41, 0x4b, 0x45, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x43,
-	0x48, 0x52, 0x4f, 0x4d, 0x45, 0x10, 0x01, 0x22, 0xa4, 0x01, 0x0a, 0x0e, 0x45, 0x6e, 0x64, 0x70,
-	0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72,
-	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x26, 0x0a, 0x0e,
-	0x68, 0x74, 0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x68, 0x74, 0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x43, 0x6f, 0x64, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x68, 0x74, 0x74, 0x70, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x68, 0x74, 0x12, 0x3e, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x2e, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x73, 0x65, 0x72, 0x41, 0x67, 0x65, 0x6e, 0x74,
+	0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f,
+	0x75, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x4f, 0x6e, 0x6c, 0x79,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x4f, 0x6e,
+	0x6c, 0x79, 0x1a, 0x3a, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0xa2,
+	0x01, 0x0a, 0x05, 0x53, 0x74, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x06, 0xe2, 0xdf, 0x1f, 0x02,
+	0x10, 0x00, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x07, 0xe2, 0xdf,
+	0x1f, 0x03, 0x18, 0x90, 0x4e, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12, 0x36, 0x0a,
+	0x04, 0x72, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x53, 0x74, 0x61, 0x67,
+	0x65, 0x2e, 0x52, 0x61, 0x6d, 0x70, 0x42, 0x07, 0xe2, 0xdf, 0x1f, 0x03, 0x88, 0x01, 0x01, 0x52,
+	0x04, 0x72, 0x61, 0x6d, 0x70, 0x22, 0x1c, 0x0a, 0x04, 0x52, 0x61, 0x6d, 0x70, 0x12, 0x0a, 0x0a,
+	0x06, 0x4c, 0x49, 0x4e, 0x45, 0x41, 0x52, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x54, 0x45,
+	0x50, 0x10, 0x01, 0x1a, 0x82, 0x04, 0x0a, 0x07, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x65, 0x79, 0x12,
+	0x1a, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0xe2,
+	0xdf, 0x1f, 0x02, 0x58, 0x01, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x06, 0x77,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x09, 0xe2, 0xdf, 0x1f,
+	0x05, 0x10, 0x00, 0x18, 0xe8, 0x07, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x3b,
+	0x0a, 0x05, 0x73, 0x74, 0x65, 0x70, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4a, 0x6f,
+	0x75, 0x72, 0x6e, 0x65, 0x79, 0x2e, 0x53, 0x74, 0x65, 0x70, 0x42, 0x08, 0xe2, 0xdf, 0x1f, 0x04,
+	0x60, 0x01, 0x68, 0x64, 0x52, 0x05, 0x73, 0x74, 0x65, 0x70, 0x73, 0x1a, 0xfa, 0x02, 0x0a, 0x04,
+	0x53, 0x74, 0x65, 0x70, 0x12, 0x1a, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x06, 0xe2, 0xdf, 0x1f, 0x02, 0x58, 0x01, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x43, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x22, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x2e, 0x4a, 0x6f, 0x75, 0x72, 0x6e, 0x65, 0x79, 0x2e, 0x53, 0x74, 0x65, 0x70, 0x2e, 0x41, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x42, 0x07, 0xe2, 0xdf, 0x1f, 0x03, 0x88, 0x01, 0x01, 0x52, 0x06, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x22, 0x84, 0x01, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0c, 0x0a, 0x08,
+	0x4e, 0x41, 0x56, 0x49, 0x47, 0x41, 0x54, 0x45, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x4c,
+	0x49, 0x43, 0x4b, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x59, 0x50, 0x45, 0x10, 0x02, 0x12,
+	0x0a, 0x0a, 0x06, 0x53, 0x55, 0x42, 0x4d, 0x49, 0x54, 0x10, 0x03, 0x12, 0x08, 0x0a, 0x04, 0x57,
+	0x41, 0x49, 0x54, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x53, 0x4c, 0x45, 0x45, 0x50, 0x10, 0x05,
+	0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x53, 0x53, 0x45, 0x52, 0x54, 0x5f, 0x54, 0x45, 0x58, 0x54, 0x10,
+	0x06, 0x12, 0x0e, 0x0a, 0x0a, 0x53, 0x45, 0x54, 0x5f, 0x43, 0x4f, 0x4f, 0x4b, 0x49, 0x45, 0x10,
+	0x07, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x45, 0x54, 0x5f, 0x4c, 0x4f, 0x43, 0x41, 0x4c, 0x5f, 0x53,
+	0x54, 0x4f, 0x52, 0x41, 0x47, 0x45, 0x10, 0x08, 0x1a, 0x74, 0x0a, 0x08, 0x55, 0x73, 0x65, 0x72,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x73, 0x12, 0x2f,
+	0x0a, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x44, 0x61, 0x74, 0x61, 0x2e, 0x52, 0x6f, 0x77, 0x52, 0x04, 0x72, 0x6f, 0x77, 0x73, 0x1a,
+	0x1d, 0x0a, 0x03, 0x52, 0x6f, 0x77, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x1a, 0xf8,
+	0x02, 0x0a, 0x09, 0x45, 0x6d, 0x75, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x06, 0xe2, 0xdf, 0x1f, 0x02,
+	0x58, 0x01, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x09, 0xe2, 0xdf, 0x1f, 0x05, 0x10, 0x00,
+	0x18, 0xe8, 0x07, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74,
+	0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x2c, 0x0a, 0x11, 0x64, 0x65, 0x76,
+	0x69, 0x63, 0x65, 0x53, 0x63, 0x61, 0x6c, 0x65, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x11, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x53, 0x63, 0x61, 0x6c,
+	0x65, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x6f, 0x62, 0x69, 0x6c,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6d, 0x6f, 0x62, 0x69, 0x6c, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x75, 0x73, 0x65, 0x72, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x75, 0x73, 0x65, 0x72, 0x41, 0x67, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a,
+	0x0d, 0x63, 0x70, 0x75, 0x54, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x63, 0x70, 0x75, 0x54, 0x68, 0x72, 0x6f, 0x74, 0x74, 0x6c,
+	0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x2e, 0x0a,
+	0x12, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68,
+	0x70, 0x75, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x12, 0x64, 0x6f, 0x77, 0x6e, 0x6c,
+	0x6f, 0x61, 0x64, 0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75, 0x74, 0x12, 0x2a, 0x0a,
+	0x10, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x54, 0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75,
+	0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x54,
+	0x68, 0x72, 0x6f, 0x75, 0x67, 0x68, 0x70, 0x75, 0x74, 0x1a, 0x60, 0x0a, 0x09, 0x41, 0x72, 0x74,
+	0x69, 0x66, 0x61, 0x63, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x74, 0x6d, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x68, 0x74, 0x6d, 0x6c, 0x12, 0x19, 0x0a, 0x03, 0x6d, 0x61,
+	0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x07, 0xe2, 0xdf, 0x1f, 0x03, 0x18, 0x90, 0x4e,
+	0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x24, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x0a, 0xe2, 0xdf, 0x1f, 0x06, 0x10, 0x00, 0x18, 0x81,
+	0x80, 0x40, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65, 0x1a, 0x59, 0x0a, 0x03, 0x48,
+	0x41, 0x52, 0x12, 0x1d, 0x0a, 0x05, 0x66, 0x69, 0x72, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x42, 0x07, 0xe2, 0xdf, 0x1f, 0x03, 0x18, 0x90, 0x4e, 0x52, 0x05, 0x66, 0x69, 0x72, 0x73,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x06, 0x73, 0x6c, 0x6f, 0x77, 0x65, 0x72, 0x12, 0x1b, 0x0a, 0x03, 0x6d, 0x61, 0x78,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x42, 0x09, 0xe2, 0xdf, 0x1f, 0x05, 0x10, 0x00, 0x18, 0x90,
+	0x4e, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x22, 0x2d, 0x0a, 0x0b, 0x42, 0x72, 0x6f, 0x77, 0x73, 0x65,
+	0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x41, 0x4b, 0x45, 0x10, 0x00, 0x12,
+	0x0a, 0x0a, 0x06, 0x43, 0x48, 0x52, 0x4f, 0x4d, 0x45, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x48,
+	0x54, 0x54, 0x50, 0x10, 0x02, 0x22, 0xbd, 0x0a, 0x0a, 0x0e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x26, 0x0a, 0x0e, 0x68, 0x74,
+	0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0e, 0x68, 0x74, 0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f,
+	0x64, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x68, 0x74, 0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x68,
+	0x74, 0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x74, 0x66, 0x62, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x74, 0x74, 0x66, 0x62, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x2a, 0x0a, 0x10,
+	0x64, 0x6f, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x4c, 0x6f, 0x61, 0x64, 0x65, 0x64,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x64, 0x6f, 0x6d, 0x43, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x4c, 0x6f, 0x61, 0x64, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x6f, 0x61, 0x64,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x1e, 0x0a, 0x0a,
+	0x66, 0x69, 0x72, 0x73, 0x74, 0x50, 0x61, 0x69, 0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x66, 0x69, 0x72, 0x73, 0x74, 0x50, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x32, 0x0a, 0x14,
+	0x66, 0x69, 0x72, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x66, 0x75, 0x6c, 0x50,
+	0x61, 0x69, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14, 0x66, 0x69, 0x72, 0x73,
+	0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x66, 0x75, 0x6c, 0x50, 0x61, 0x69, 0x6e, 0x74,
+	0x12, 0x36, 0x0a, 0x16, 0x6c, 0x61, 0x72, 0x67, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x66, 0x75, 0x6c, 0x50, 0x61, 0x69, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x16, 0x6c, 0x61, 0x72, 0x67, 0x65, 0x73, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74,
+	0x66, 0x75, 0x6c, 0x50, 0x61, 0x69, 0x6e, 0x74, 0x12, 0x34, 0x0a, 0x15, 0x63, 0x75, 0x6d, 0x75,
+	0x6c, 0x61, 0x74, 0x69, 0x76, 0x65, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x53, 0x68, 0x69, 0x66,
+	0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x02, 0x52, 0x15, 0x63, 0x75, 0x6d, 0x75, 0x6c, 0x61, 0x74,
+	0x69, 0x76, 0x65, 0x4c, 0x61, 0x79, 0x6f, 0x75, 0x74, 0x53, 0x68, 0x69, 0x66, 0x74, 0x12, 0x10,
+	0x0a, 0x03, 0x64, 0x6e, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x64, 0x6e, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x73, 0x73,
+	0x6c, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x73, 0x73, 0x6c, 0x12, 0x12, 0x0a, 0x04,
+	0x73, 0x65, 0x6e, 0x64, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x65, 0x6e, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x77, 0x61, 0x69, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x77, 0x61, 0x69, 0x74, 0x12, 0x42, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x72, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x76, 0x31, 0x2e,
+	0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x53,
+	0x75, 0x62, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x72,
+	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6a, 0x6f, 0x75, 0x72,
+	0x6e, 0x65, 0x79, 0x18, 0x12, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6a, 0x6f, 0x75, 0x72, 0x6e,
+	0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x73, 0x74, 0x65, 0x70, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x14, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x15, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x6d, 0x75, 0x6c,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x16, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x6d, 0x75,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6c, 0x64, 0x43, 0x61,
+	0x63, 0x68, 0x65, 0x18, 0x17, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x63, 0x6f, 0x6c, 0x64, 0x43,
+	0x61, 0x63, 0x68, 0x65, 0x12, 0x37, 0x0a, 0x08, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74,
+	0x18, 0x18, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x64, 0x70,
+	0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x2e, 0x41, 0x72, 0x74, 0x69, 0x66,
+	0x61, 0x63, 0x74, 0x52, 0x08, 0x61, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x12, 0x1a, 0x0a,
+	0x08, 0x6a, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x19, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x08, 0x6a, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x12, 0x28, 0x0a, 0x0f, 0x6a, 0x73, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x1a, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0f, 0x6a, 0x73, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6c, 0x61, 0x73,
+	0x73, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6c,
+	0x61, 0x73, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x1c, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x68, 0x61, 0x72,
+	0x18, 0x1d, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x68, 0x61, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x75,
+	0x73, 0x65, 0x72, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x1a,
+	0xa1, 0x02, 0x0a, 0x0b, 0x53, 0x75, 0x62, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12,
+	0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72,
+	0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x68, 0x74, 0x74, 0x70, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x68,
+	0x74, 0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x2c, 0x0a,
 	0x11, 0x68, 0x74, 0x74, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x74, 0x66, 0x62, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x04, 0x74, 0x74, 0x66, 0x62, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x22, 0x0d,
-	0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x3e, 0x0a,
-	0x0c, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a,
-	0x05, 0x73, 0x72, 0x63, 0x49, 0x50, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x72,
-	0x63, 0x49, 0x50, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x32, 0x64, 0x0a,
-	0x06, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x2b, 0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12,
-	0x0f, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x00, 0x12, 0x2d, 0x0a, 0x03, 0x52, 0x75, 0x6e, 0x12, 0x0e, 0x2e, 0x76, 0x31,
-	0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x76, 0x31,
-	0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22,
-	0x00, 0x30, 0x01, 0x42, 0x07, 0x5a, 0x05, 0x2e, 0x3b, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x68, 0x74, 0x74, 0x70, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x74, 0x66, 0x62, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x74, 0x74, 0x66, 0x62, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73,
+	0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x06, 0x63, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x69,
+	0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x69, 0x6e, 0x69,
+	0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x55, 0x72, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x6f, 0x72, 0x55, 0x72, 0x6c, 0x12, 0x14, 0x0a,
+	0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x1a, 0x50, 0x0a, 0x08, 0x41, 0x72, 0x74, 0x69, 0x66, 0x61, 0x63, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72,
+	0x6c, 0x12, 0x1e, 0x0a, 0x0a, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x73, 0x68, 0x6f, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x73, 0x63, 0x72, 0x65, 0x65, 0x6e, 0x73, 0x68, 0x6f,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x74, 0x6d, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x68, 0x74, 0x6d, 0x6c, 0x22, 0x0d, 0x0a, 0x0b, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x3e, 0x0a, 0x0c, 0x50, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x72, 0x63, 0x49, 0x50, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x72, 0x63, 0x49, 0x50, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x32, 0x64, 0x0a, 0x06, 0x57, 0x6f, 0x72, 0x6b, 0x65, 0x72, 0x12, 0x2b,
+	0x0a, 0x04, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x0f, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x6e, 0x67,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x69, 0x6e,
+	0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x2d, 0x0a, 0x03, 0x52,
+	0x75, 0x6e, 0x12, 0x0e, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x75, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x12, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x22, 0x00, 0x30, 0x01, 0x42, 0x07, 0x5a, 0x05, 0x2e, 0x3b,
+	0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -452,28 +1982,55 @@ func file_worker_proto_rawDescGZIP() []byte {
 	return file_worker_proto_rawDescData
 }
 
-var file_worker_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_worker_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_worker_proto_goTypes = []interface{}{
-	(RunRequest_BrowserType)(0), // 0: v1.RunRequest.BrowserType
-	(*RunRequest)(nil),          // 1: v1.RunRequest
-	(*EndpointResult)(nil),      // 2: v1.EndpointResult
-	(*PingRequest)(nil),         // 3: v1.PingRequest
-	(*PingResponse)(nil),        // 4: v1.PingResponse
-	(*RunRequest_Endpoint)(nil), // 5: v1.RunRequest.Endpoint
+	(RunRequest_BrowserType)(0),         // 0: v1.RunRequest.BrowserType
+	(RunRequest_Stage_Ramp)(0),          // 1: v1.RunRequest.Stage.Ramp
+	(RunRequest_Journey_Step_Action)(0), // 2: v1.RunRequest.Journey.Step.Action
+	(*RunRequest)(nil),                  // 3: v1.RunRequest
+	(*EndpointResult)(nil),              // 4: v1.EndpointResult
+	(*PingRequest)(nil),                 // 5: v1.PingRequest
+	(*PingResponse)(nil),                // 6: v1.PingResponse
+	(*RunRequest_Endpoint)(nil),         // 7: v1.RunRequest.Endpoint
+	(*RunRequest_Stage)(nil),            // 8: v1.RunRequest.Stage
+	(*RunRequest_Journey)(nil),          // 9: v1.RunRequest.Journey
+	(*RunRequest_UserData)(nil),         // 10: v1.RunRequest.UserData
+	(*RunRequest_Emulation)(nil),        // 11: v1.RunRequest.Emulation
+	(*RunRequest_Artifacts)(nil),        // 12: v1.RunRequest.Artifacts
+	(*RunRequest_HAR)(nil),              // 13: v1.RunRequest.HAR
+	nil,                                 // 14: v1.RunRequest.Endpoint.HeadersEntry
+	(*RunRequest_Journey_Step)(nil),     // 15: v1.RunRequest.Journey.Step
+	(*RunRequest_UserData_Row)(nil),     // 16: v1.RunRequest.UserData.Row
+	(*EndpointResult_Subresource)(nil),  // 17: v1.EndpointResult.Subresource
+	(*EndpointResult_Artifact)(nil),     // 18: v1.EndpointResult.Artifact
 }
 var file_worker_proto_depIdxs = []int32{
-	5, // 0: v1.RunRequest.endpoints:type_name -> v1.RunRequest.Endpoint
-	0, // 1: v1.RunRequest.type:type_name -> v1.RunRequest.BrowserType
-	3, // 2: v1.Worker.Ping:input_type -> v1.PingRequest
-	1, // 3: v1.Worker.Run:input_type -> v1.RunRequest
-	4, // 4: v1.Worker.Ping:output_type -> v1.PingResponse
-	2, // 5: v1.Worker.Run:output_type -> v1.EndpointResult
-	4, // [4:6] is the sub-list for method output_type
-	2, // [2:4] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	7,  // 0: v1.RunRequest.endpoints:type_name -> v1.RunRequest.Endpoint
+	0,  // 1: v1.RunRequest.type:type_name -> v1.RunRequest.BrowserType
+	8,  // 2: v1.RunRequest.stages:type_name -> v1.RunRequest.Stage
+	9,  // 3: v1.RunRequest.journeys:type_name -> v1.RunRequest.Journey
+	15, // 4: v1.RunRequest.setup:type_name -> v1.RunRequest.Journey.Step
+	10, // 5: v1.RunRequest.userData:type_name -> v1.RunRequest.UserData
+	11, // 6: v1.RunRequest.emulations:type_name -> v1.RunRequest.Emulation
+	12, // 7: v1.RunRequest.artifacts:type_name -> v1.RunRequest.Artifacts
+	13, // 8: v1.RunRequest.har:type_name -> v1.RunRequest.HAR
+	17, // 9: v1.EndpointResult.subresources:type_name -> v1.EndpointResult.Subresource
+	18, // 10: v1.EndpointResult.artifact:type_name -> v1.EndpointResult.Artifact
+	14, // 11: v1.RunRequest.Endpoint.headers:type_name -> v1.RunRequest.Endpoint.HeadersEntry
+	1,  // 12: v1.RunRequest.Stage.ramp:type_name -> v1.RunRequest.Stage.Ramp
+	15, // 13: v1.RunRequest.Journey.steps:type_name -> v1.RunRequest.Journey.Step
+	16, // 14: v1.RunRequest.UserData.rows:type_name -> v1.RunRequest.UserData.Row
+	2,  // 15: v1.RunRequest.Journey.Step.action:type_name -> v1.RunRequest.Journey.Step.Action
+	5,  // 16: v1.Worker.Ping:input_type -> v1.PingRequest
+	3,  // 17: v1.Worker.Run:input_type -> v1.RunRequest
+	6,  // 18: v1.Worker.Ping:output_type -> v1.PingResponse
+	4,  // 19: v1.Worker.Run:output_type -> v1.EndpointResult
+	18, // [18:20] is the sub-list for method output_type
+	16, // [16:18] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_worker_proto_init() }
@@ -542,14 +2099,134 @@ func file_worker_proto_init() {
 				return nil
 			}
 		}
+		file_worker_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunRequest_Stage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_worker_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunRequest_Journey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_worker_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunRequest_UserData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_worker_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunRequest_Emulation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_worker_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunRequest_Artifacts); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_worker_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunRequest_HAR); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_worker_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunRequest_Journey_Step); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_worker_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RunRequest_UserData_Row); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_worker_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EndpointResult_Subresource); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_worker_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EndpointResult_Artifact); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_worker_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   5,
+			NumEnums:      3,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
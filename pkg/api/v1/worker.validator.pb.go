@@ -18,9 +18,6 @@ var _ = fmt.Errorf
 var _ = math.Inf
 
 func (this *RunRequest) Validate() error {
-	if len(this.Endpoints) < 1 {
-		return github_com_mwitkow_go_proto_validators.FieldError("Endpoints", fmt.Errorf(`value '%v' must contain at least 1 elements`, this.Endpoints))
-	}
 	if len(this.Endpoints) > 1000 {
 		return github_com_mwitkow_go_proto_validators.FieldError("Endpoints", fmt.Errorf(`value '%v' must contain at most 1000 elements`, this.Endpoints))
 	}
@@ -34,8 +31,8 @@ func (this *RunRequest) Validate() error {
 	if !(this.Amount > 0) {
 		return github_com_mwitkow_go_proto_validators.FieldError("Amount", fmt.Errorf(`value '%v' must be greater than '0'`, this.Amount))
 	}
-	if !(this.Amount < 500) {
-		return github_com_mwitkow_go_proto_validators.FieldError("Amount", fmt.Errorf(`value '%v' must be less than '500'`, this.Amount))
+	if !(this.Amount < 10000) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Amount", fmt.Errorf(`value '%v' must be less than '10000'`, this.Amount))
 	}
 	if _, ok := RunRequest_BrowserType_name[int32(this.Type)]; !ok {
 		return github_com_mwitkow_go_proto_validators.FieldError("Type", fmt.Errorf(`value '%v' must be a valid RunRequest_BrowserType field`, this.Type))
@@ -52,6 +49,67 @@ func (this *RunRequest) Validate() error {
 	if !(this.MaxWaitTime < 3600000) {
 		return github_com_mwitkow_go_proto_validators.FieldError("MaxWaitTime", fmt.Errorf(`value '%v' must be less than '3600000'`, this.MaxWaitTime))
 	}
+	if len(this.Stages) > 100 {
+		return github_com_mwitkow_go_proto_validators.FieldError("Stages", fmt.Errorf(`value '%v' must contain at most 100 elements`, this.Stages))
+	}
+	for _, item := range this.Stages {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Stages", err)
+			}
+		}
+	}
+	if len(this.Journeys) > 100 {
+		return github_com_mwitkow_go_proto_validators.FieldError("Journeys", fmt.Errorf(`value '%v' must contain at most 100 elements`, this.Journeys))
+	}
+	for _, item := range this.Journeys {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Journeys", err)
+			}
+		}
+	}
+	if len(this.Setup) > 100 {
+		return github_com_mwitkow_go_proto_validators.FieldError("Setup", fmt.Errorf(`value '%v' must contain at most 100 elements`, this.Setup))
+	}
+	for _, item := range this.Setup {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Setup", err)
+			}
+		}
+	}
+	if this.UserData != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.UserData); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("UserData", err)
+		}
+	}
+	if len(this.Emulations) > 100 {
+		return github_com_mwitkow_go_proto_validators.FieldError("Emulations", fmt.Errorf(`value '%v' must contain at most 100 elements`, this.Emulations))
+	}
+	for _, item := range this.Emulations {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Emulations", err)
+			}
+		}
+	}
+	if !(this.ColdVisits < 101) {
+		return github_com_mwitkow_go_proto_validators.FieldError("ColdVisits", fmt.Errorf(`value '%v' must be less than '101'`, this.ColdVisits))
+	}
+	if !(this.ChromeProcesses < 1000) {
+		return github_com_mwitkow_go_proto_validators.FieldError("ChromeProcesses", fmt.Errorf(`value '%v' must be less than '1000'`, this.ChromeProcesses))
+	}
+	if this.Artifacts != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Artifacts); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Artifacts", err)
+		}
+	}
+	if this.Har != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Har); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Har", err)
+		}
+	}
 	return nil
 }
 
@@ -67,9 +125,123 @@ func (this *RunRequest_Endpoint) Validate() error {
 	if !(this.Weight < 1000) {
 		return github_com_mwitkow_go_proto_validators.FieldError("Weight", fmt.Errorf(`value '%v' must be less than '1000'`, this.Weight))
 	}
+	// Validation of proto3 map<> fields is unsupported.
+	return nil
+}
+func (this *RunRequest_Stage) Validate() error {
+	if !(this.Duration > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Duration", fmt.Errorf(`value '%v' must be greater than '0'`, this.Duration))
+	}
+	if !(this.Target < 10000) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Target", fmt.Errorf(`value '%v' must be less than '10000'`, this.Target))
+	}
+	if _, ok := RunRequest_Stage_Ramp_name[int32(this.Ramp)]; !ok {
+		return github_com_mwitkow_go_proto_validators.FieldError("Ramp", fmt.Errorf(`value '%v' must be a valid RunRequest_Stage_Ramp field`, this.Ramp))
+	}
+	return nil
+}
+func (this *RunRequest_Journey) Validate() error {
+	if this.Name == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Name", fmt.Errorf(`value '%v' must not be an empty string`, this.Name))
+	}
+	if !(this.Weight > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Weight", fmt.Errorf(`value '%v' must be greater than '0'`, this.Weight))
+	}
+	if !(this.Weight < 1000) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Weight", fmt.Errorf(`value '%v' must be less than '1000'`, this.Weight))
+	}
+	if len(this.Steps) < 1 {
+		return github_com_mwitkow_go_proto_validators.FieldError("Steps", fmt.Errorf(`value '%v' must contain at least 1 elements`, this.Steps))
+	}
+	if len(this.Steps) > 100 {
+		return github_com_mwitkow_go_proto_validators.FieldError("Steps", fmt.Errorf(`value '%v' must contain at most 100 elements`, this.Steps))
+	}
+	for _, item := range this.Steps {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Steps", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *RunRequest_Journey_Step) Validate() error {
+	if this.Name == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Name", fmt.Errorf(`value '%v' must not be an empty string`, this.Name))
+	}
+	if _, ok := RunRequest_Journey_Step_Action_name[int32(this.Action)]; !ok {
+		return github_com_mwitkow_go_proto_validators.FieldError("Action", fmt.Errorf(`value '%v' must be a valid RunRequest_Journey_Step_Action field`, this.Action))
+	}
+	return nil
+}
+func (this *RunRequest_UserData) Validate() error {
+	for _, item := range this.Rows {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Rows", err)
+			}
+		}
+	}
+	return nil
+}
+func (this *RunRequest_UserData_Row) Validate() error {
+	return nil
+}
+func (this *RunRequest_Emulation) Validate() error {
+	if this.Name == "" {
+		return github_com_mwitkow_go_proto_validators.FieldError("Name", fmt.Errorf(`value '%v' must not be an empty string`, this.Name))
+	}
+	if !(this.Weight > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Weight", fmt.Errorf(`value '%v' must be greater than '0'`, this.Weight))
+	}
+	if !(this.Weight < 1000) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Weight", fmt.Errorf(`value '%v' must be less than '1000'`, this.Weight))
+	}
+	return nil
+}
+func (this *RunRequest_Artifacts) Validate() error {
+	if !(this.Max < 10000) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Max", fmt.Errorf(`value '%v' must be less than '10000'`, this.Max))
+	}
+	if !(this.MaxSize > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("MaxSize", fmt.Errorf(`value '%v' must be greater than '0'`, this.MaxSize))
+	}
+	if !(this.MaxSize < 1048577) {
+		return github_com_mwitkow_go_proto_validators.FieldError("MaxSize", fmt.Errorf(`value '%v' must be less than '1048577'`, this.MaxSize))
+	}
+	return nil
+}
+func (this *RunRequest_HAR) Validate() error {
+	if !(this.First < 10000) {
+		return github_com_mwitkow_go_proto_validators.FieldError("First", fmt.Errorf(`value '%v' must be less than '10000'`, this.First))
+	}
+	if !(this.Max > 0) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Max", fmt.Errorf(`value '%v' must be greater than '0'`, this.Max))
+	}
+	if !(this.Max < 10000) {
+		return github_com_mwitkow_go_proto_validators.FieldError("Max", fmt.Errorf(`value '%v' must be less than '10000'`, this.Max))
+	}
 	return nil
 }
 func (this *EndpointResult) Validate() error {
+	for _, item := range this.Subresources {
+		if item != nil {
+			if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(item); err != nil {
+				return github_com_mwitkow_go_proto_validators.FieldError("Subresources", err)
+			}
+		}
+	}
+	if this.Artifact != nil {
+		if err := github_com_mwitkow_go_proto_validators.CallValidatorIfExists(this.Artifact); err != nil {
+			return github_com_mwitkow_go_proto_validators.FieldError("Artifact", err)
+		}
+	}
+	return nil
+}
+func (this *EndpointResult_Subresource) Validate() error {
+	return nil
+}
+func (this *EndpointResult_Artifact) Validate() error {
 	return nil
 }
 func (this *PingRequest) Validate() error {
@@ -0,0 +1,102 @@
+package config
+
+import "time"
+
+// Device presets of emulations.
+const (
+	// DeviceMotoG4 is a mid-range Android phone, the reference device of Lighthouse.
+	DeviceMotoG4 = "moto_g4"
+
+	// DeviceIPhoneX is an iPhone X with Safari.
+	DeviceIPhoneX = "iphone_x"
+
+	// DeviceDesktop is a desktop browser with a common laptop viewport.
+	DeviceDesktop = "desktop"
+)
+
+// Network presets of emulations.
+const (
+	NetworkSlow3G = "slow_3g"
+	NetworkFast3G = "fast_3g"
+	Network4G     = "4g"
+	NetworkDSL    = "dsl"
+	NetworkCable  = "cable"
+)
+
+var devices = map[string]InstructorEmulation{
+	DeviceMotoG4: {
+		Width:         360,
+		Height:        640,
+		ScaleFactor:   3,
+		Mobile:        true,
+		UserAgent:     "Mozilla/5.0 (Linux; Android 7.0; Moto G (4)) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/84.0.4147.89 Mobile Safari/537.36",
+		CPUThrottling: 4,
+	},
+	DeviceIPhoneX: {
+		Width:         375,
+		Height:        812,
+		ScaleFactor:   3,
+		Mobile:        true,
+		UserAgent:     "Mozilla/5.0 (iPhone; CPU iPhone OS 13_2_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/13.0.3 Mobile/15E148 Safari/604.1",
+		CPUThrottling: 2,
+	},
+	DeviceDesktop: {
+		Width:       1366,
+		Height:      768,
+		ScaleFactor: 1,
+	},
+}
+
+// networks match the throttling presets of the Chrome DevTools and WebPageTest.
+var networks = map[string]InstructorEmulation{
+	NetworkSlow3G: {Latency: 2000 * time.Millisecond, Download: 400, Upload: 400},
+	NetworkFast3G: {Latency: 563 * time.Millisecond, Download: 1440, Upload: 675},
+	Network4G:     {Latency: 170 * time.Millisecond, Download: 9000, Upload: 9000},
+	NetworkDSL:    {Latency: 50 * time.Millisecond, Download: 1500, Upload: 384},
+	NetworkCable:  {Latency: 28 * time.Millisecond, Download: 5000, Upload: 1000},
+}
+
+// Resolve returns a copy of e with the values of its device and network
+// presets, unless e sets them explicitly, and a weight of at least 1.
+func (e *InstructorEmulation) Resolve() *InstructorEmulation {
+	r := devices[e.Device]
+	n := networks[e.Network]
+
+	r.Name, r.Device, r.Network = e.Name, e.Device, e.Network
+	r.Weight = e.Weight
+	if r.Weight == 0 {
+		r.Weight = 1
+	}
+
+	r.Latency, r.Download, r.Upload = n.Latency, n.Download, n.Upload
+
+	if e.Width > 0 {
+		r.Width = e.Width
+	}
+	if e.Height > 0 {
+		r.Height = e.Height
+	}
+	if e.ScaleFactor > 0 {
+		r.ScaleFactor = e.ScaleFactor
+	}
+	if e.Mobile {
+		r.Mobile = true
+	}
+	if e.UserAgent != "" {
+		r.UserAgent = e.UserAgent
+	}
+	if e.CPUThrottling > 0 {
+		r.CPUThrottling = e.CPUThrottling
+	}
+	if e.Latency > 0 {
+		r.Latency = e.Latency
+	}
+	if e.Download > 0 {
+		r.Download = e.Download
+	}
+	if e.Upload > 0 {
+		r.Upload = e.Upload
+	}
+
+	return &r
+}
@@ -1,28 +1,295 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 // InstructorWorkerConfig specified a worker destination service
 type InstructorWorkerConfig struct {
 	// Human readable name for the worker target
-	Alias string
+	Alias string `json:"alias"`
 
 	// IP or DNS resolvable hostname of the worker
-	Adress string
+	Adress string `json:"adress"`
 
 	// TCP port of the worker
-	Port int
+	Port int `json:"port"`
+
+	Certificate string `json:"certificate"`
+
+	// Secret is never serialized, since the config is
+	// written into result files.
+	Secret string `json:"-"`
 
-	Certificate string
+	// Browser overrides the browser of the loadtest for this worker,
+	// i.e. to mix a cheap HTTP background load with real browser users.
+	Browser string `json:"browser,omitempty"`
 
-	Secret string
+	// Amount overrides the amount of users simulated by this worker.
+	// Like the global amount, it is ignored if stages are given.
+	Amount int `json:"amount,omitempty"`
+
+	// ChromeProcesses overrides the amount of shared chrome processes of this worker.
+	ChromeProcesses int `json:"chrome_processes,omitempty"`
 }
 
 type InstructorEndpoint struct {
-	Url    string
-	Weight int
+	Url    string `json:"url"`
+	Weight int    `json:"weight"`
+
+	// Thresholds which only apply to results of this endpoint.
+	Thresholds []string `json:"thresholds"`
+
+	// Headers are sent with every request of the endpoint, including the
+	// subresources of the page, i.e. to mark the traffic as synthetic.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// UserAgent overrides the user agent of the browser.
+	UserAgent string `json:"user_agent,omitempty"`
+
+	// BasicAuth credentials are sent with every request of the endpoint.
+	BasicAuth *InstructorBasicAuth `json:"basic_auth,omitempty"`
+
+	// OriginOnly sends headers and credentials only with requests to the
+	// origin of the endpoint, not to third-party origins. Chrome intercepts
+	// these requests to add them, which delays every one of them.
+	OriginOnly bool `json:"origin_only,omitempty"`
+
+	// Timeout of loading the page, i.e. "30s", overrides the global timeout.
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// InstructorBasicAuth contains credentials for HTTP basic auth.
+type InstructorBasicAuth struct {
+	Username string `json:"username"`
+
+	// Password is never serialized, since the config is
+	// written into result files.
+	Password string `json:"-"`
+}
+
+// Browsers simulating users.
+const (
+	// BrowserChrome loads pages in a headless Chrome, which is the default.
+	BrowserChrome = "chrome"
+
+	// BrowserHTTP performs plain HTTP requests without a browser, which allows
+	// simulating far more users, but doesn't load subresources or execute scripts.
+	BrowserHTTP = "http"
+)
+
+// Actions of a journey step.
+const (
+	ActionNavigate   = "navigate"
+	ActionClick      = "click"
+	ActionType       = "type"
+	ActionSubmit     = "submit"
+	ActionWait       = "wait"
+	ActionSleep      = "sleep"
+	ActionAssertText = "assert_text"
+
+	// ActionSetCookie sets the cookie Key to Value for URL.
+	ActionSetCookie = "set_cookie"
+
+	// ActionSetLocalStorage sets the local storage item Key to Value
+	// for the origin of the current page.
+	ActionSetLocalStorage = "set_local_storage"
+)
+
+// InstructorStep is a single action of a journey.
+// Selectors are CSS selectors.
+type InstructorStep struct {
+	// Name under which the results of the step are reported,
+	// defaults to the position and action of the step, i.e. "2 click".
+	Name string `json:"name,omitempty"`
+
+	// Action of the step, one of the Action constants.
+	Action string `json:"action"`
+
+	// URL to navigate to.
+	URL string `json:"url,omitempty"`
+
+	// Selector of the element to click, type into, submit, wait for or assert the text of.
+	Selector string `json:"selector,omitempty"`
+
+	// Key is the name of the cookie or local storage item to set.
+	Key string `json:"key,omitempty"`
+
+	// Value to type, the text to assert or the value to set.
+	// Values of the user data file are referenced as ${column}.
+	Value string `json:"value,omitempty"`
+
+	// Duration to sleep, i.e. "2s".
+	Duration time.Duration `json:"duration,omitempty"`
+
+	// Timeout of waiting for the element, defaults to 30s.
+	// For navigation steps, it's the timeout of loading the page,
+	// which defaults to the global timeout.
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// StepName returns the name of the i-th step s of a journey.
+func StepName(i int, s *InstructorStep) string {
+	if s.Name != "" {
+		return s.Name
+	}
+
+	return fmt.Sprintf("%d %s", i+1, s.Action)
+}
+
+// InstructorJourney is a scripted sequence of steps performed by a user.
+type InstructorJourney struct {
+	// Name under which the results of the journey are reported.
+	Name string `json:"name"`
+
+	// Weight of the journey, on the same scale as the weight of endpoints.
+	Weight int `json:"weight"`
+
+	Steps []*InstructorStep `json:"steps"`
+}
+
+// InstructorEmulation is a profile of a device on a network, which chrome users emulate,
+// i.e. a mid-range phone on slow 3G. Explicit values override those of the presets.
+type InstructorEmulation struct {
+	// Name under which results of the users are reported.
+	Name string `json:"name"`
+
+	// Weight of the emulation, defaults to 1. Users are assigned
+	// an emulation by weight, i.e. to mirror the share of mobile traffic.
+	Weight int `json:"weight,omitempty"`
+
+	// Device preset, one of the Device constants.
+	Device string `json:"device,omitempty"`
+
+	// Network preset, one of the Network constants.
+	Network string `json:"network,omitempty"`
+
+	// Viewport in CSS pixels.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+
+	// ScaleFactor is the ratio of device pixels to CSS pixels.
+	ScaleFactor float64 `json:"scale_factor,omitempty"`
+
+	Mobile    bool   `json:"mobile,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+
+	// CPUThrottling slows down the CPU by this factor.
+	CPUThrottling float64 `json:"cpu_throttling,omitempty"`
+
+	// Latency added to every request.
+	Latency time.Duration `json:"latency,omitempty"`
+
+	// Download and Upload limit the bandwidth in kbit/s.
+	Download int `json:"download,omitempty"`
+	Upload   int `json:"upload,omitempty"`
+}
+
+// Defaults of artifacts.
+const (
+	// DefaultArtifactsMax is the default amount of artifacts per worker.
+	DefaultArtifactsMax = 20
+
+	// DefaultArtifactsMaxSize is the default size limit of screenshots and HTML in bytes.
+	DefaultArtifactsMaxSize = 512 * 1024
+
+	// MaxArtifactsSize is the highest supported size limit, which keeps
+	// artifacts within the message size limit of gRPC.
+	MaxArtifactsSize = 1024 * 1024
+)
+
+// InstructorArtifacts configures capturing a screenshot and optionally the HTML of
+// the page of failed chrome requests, i.e. a HTTP status of 400 or above or a failed step.
+type InstructorArtifacts struct {
+	// HTML enables capturing the HTML of the page besides a screenshot.
+	HTML bool `json:"html"`
+
+	// Max limits the amount of artifacts per worker, defaults to DefaultArtifactsMax.
+	Max int `json:"max,omitempty"`
+
+	// MaxSize limits the size of screenshots and HTML in bytes, defaults to
+	// DefaultArtifactsMaxSize. Larger screenshots are dropped, larger HTML is truncated.
+	MaxSize int `json:"max_size,omitempty"`
+}
+
+// Resolve returns a copy of a with defaults for unset limits.
+func (a *InstructorArtifacts) Resolve() *InstructorArtifacts {
+	r := *a
+
+	if r.Max == 0 {
+		r.Max = DefaultArtifactsMax
+	}
+	if r.MaxSize == 0 {
+		r.MaxSize = DefaultArtifactsMaxSize
+	}
+
+	return &r
+}
+
+// DefaultHARMax is the default amount of HARs per worker.
+const DefaultHARMax = 100
+
+// InstructorHAR configures sampling page loads of endpoints by chrome users as HAR 1.2,
+// which are the first loads of every endpoint and every slow load.
+type InstructorHAR struct {
+	// First is the amount of page loads per endpoint and worker, which are sampled.
+	First int `json:"first,omitempty"`
+
+	// Slower samples every page load, which took at least this long until
+	// the load event, i.e. "3s". Zero disables sampling slow page loads.
+	Slower time.Duration `json:"slower,omitempty"`
+
+	// Max limits the amount of HARs per worker, defaults to DefaultHARMax.
+	Max int `json:"max,omitempty"`
+}
+
+// Resolve returns a copy of h with defaults for unset limits.
+func (h *InstructorHAR) Resolve() *InstructorHAR {
+	r := *h
+
+	if r.Max == 0 {
+		r.Max = DefaultHARMax
+	}
+
+	return &r
+}
+
+// Cache modes of users.
+const (
+	// CacheWarm keeps the cache and cookies of a user for its whole life, which is the default.
+	CacheWarm = "warm"
+
+	// CacheCold clears the cache and cookies before every visit,
+	// so every visit is the one of a first-time visitor.
+	CacheCold = "cold"
+
+	// CacheMixed clears the cache and cookies before a share of ColdVisits percent of the visits.
+	CacheMixed = "mixed"
+)
+
+// Ramps of a load profile stage.
+const (
+	// RampLinear changes the amount of users linearly over the duration of a stage.
+	RampLinear = "linear"
+
+	// RampStep changes the amount of users at the beginning of a stage.
+	RampStep = "step"
+)
+
+// InstructorStage is a single stage of a load profile.
+type InstructorStage struct {
+	// Duration of the stage, i.e. "2m".
+	Duration time.Duration `json:"duration"`
+
+	// Target is the amount of users per worker at the end of the stage.
+	Target int `json:"target"`
+
+	// Ramp controls how the amount of users changes towards the target,
+	// either RampLinear (default) or RampStep.
+	Ramp string `json:"ramp"`
 }
 
 // InstructorConfig represents the configuration structure for
@@ -31,19 +298,203 @@ type InstructorConfig struct {
 
 	// Workers is a list of worker targets a Loago instance in instructor mode
 	// should reach out to for requesting load tests
-	Workers []*InstructorWorkerConfig
+	Workers []*InstructorWorkerConfig `json:"workers"`
 
 	// Endpoints called by workers.
-	Endpoints []*InstructorEndpoint
+	Endpoints []*InstructorEndpoint `json:"endpoints"`
+
+	// Journeys performed by workers alongside endpoints.
+	Journeys []*InstructorJourney `json:"journeys"`
+
+	// Setup steps are performed once by every user before
+	// any endpoint or journey, i.e. to log in.
+	Setup []*InstructorStep `json:"setup"`
+
+	// ChromeProcesses hosts chrome users as isolated browser contexts in this many
+	// shared chrome processes per worker, which allows simulating several times
+	// more users per worker. Zero starts a chrome process per user.
+	ChromeProcesses int `json:"chrome_processes,omitempty"`
+
+	// Cache mode of users, one of the Cache constants.
+	Cache string `json:"cache"`
+
+	// ColdVisits is the percentage of visits by first-time visitors in CacheMixed mode.
+	ColdVisits int `json:"cold_visits,omitempty"`
+
+	// Emulations are device and network profiles assigned to chrome users by weight.
+	Emulations []*InstructorEmulation `json:"emulations"`
+
+	// Artifacts enables capturing the page of failed chrome requests. They are
+	// stored next to the result file.
+	Artifacts *InstructorArtifacts `json:"artifacts,omitempty"`
+
+	// HAR enables sampling page loads of endpoints by chrome users as HAR.
+	// They are stored next to the result file.
+	HAR *InstructorHAR `json:"har,omitempty"`
+
+	// UserData is the path of a CSV file with a row of values per user,
+	// i.e. credentials. The first line names the columns, which steps
+	// reference as ${column}. Rows are split between workers, so every
+	// user has its own row, as long as there are enough.
+	UserData string `json:"user_data"`
 
 	// Amount of users to simulate per worker
-	Amount int
+	Amount int `json:"amount"`
 
 	// Minimum time to wait in milliseconds for the next request per worker
-	MinWait int
+	MinWait int `json:"min_wait"`
 
 	// Maximum time to wait in milliseconds for the next request per worker
-	MaxWait int
+	MaxWait int `json:"max_wait"`
+
+	// Duration of the loadtest, i.e. "10m". Workers stop on their own
+	// once it elapsed. Zero runs the loadtest until it is interrupted.
+	Duration time.Duration `json:"duration"`
+
+	// Timeout of loading a page, i.e. "30s". Requests taking longer are
+	// reported as timeout and the user continues. Zero waits until the
+	// page loaded, however long it takes.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// Stages form a load profile, which replaces Amount.
+	// The loadtest ends after the last stage.
+	Stages []*InstructorStage `json:"stages"`
+
+	// Thresholds are pass/fail criteria over all results,
+	// i.e. "p95_ttfb < 800ms" or "error_rate < 1%".
+	Thresholds []string `json:"thresholds"`
+
+	// AbortOnThreshold stops the loadtest early, once a
+	// threshold failed and can't pass anymore.
+	AbortOnThreshold bool `json:"abort_on_threshold"`
+
+	// Subresources enables reporting every resource loaded by a page,
+	// i.e. scripts, stylesheets, images, fonts and XHR/fetch requests.
+	Subresources bool `json:"subresources"`
+
+	// Browser simulating users, either BrowserChrome (default) or BrowserHTTP.
+	Browser string `json:"browser"`
+
+	// Cookies enables keeping cookies between requests of a user
+	// of the HTTP browser. Real browsers always keep cookies.
+	Cookies bool `json:"cookies"`
+}
+
+// RunDuration returns the duration after which workers stop the loadtest,
+// which is either Duration, if set, or the total duration of all stages.
+func (c *InstructorConfig) RunDuration() time.Duration {
+	if c.Duration > 0 || len(c.Stages) == 0 {
+		return c.Duration
+	}
+
+	var d time.Duration
+	for _, s := range c.Stages {
+		d += s.Duration
+	}
+
+	return d
+}
+
+// MaxUsers returns the highest amount of users per worker,
+// which is either Amount or the highest target of all stages.
+func (c *InstructorConfig) MaxUsers() int {
+	if len(c.Stages) == 0 {
+		return c.Amount
+	}
+
+	var m int
+	for _, s := range c.Stages {
+		if s.Target > m {
+			m = s.Target
+		}
+	}
+
+	return m
+}
+
+// ColdVisitsPercent returns the percentage of visits with a cold cache of the cache mode.
+func (c *InstructorConfig) ColdVisitsPercent() int {
+	switch c.Cache {
+	case CacheCold:
+		return 100
+	case CacheMixed:
+		return c.ColdVisits
+	}
+
+	return 0
+}
+
+// ForWorker returns the config of the loadtest as run by the worker
+// with the given address and port, with its overrides applied.
+func (c *InstructorConfig) ForWorker(adress string, port int) *InstructorConfig {
+	for _, w := range c.Workers {
+		if w.Adress != adress || w.Port != port {
+			continue
+		}
+
+		cfg := *c
+		if w.Browser != "" {
+			cfg.Browser = w.Browser
+		}
+
+		if w.Amount > 0 {
+			cfg.Amount = w.Amount
+		}
+
+		if w.ChromeProcesses > 0 {
+			cfg.ChromeProcesses = w.ChromeProcesses
+		}
+
+		return &cfg
+	}
+
+	return c
+}
+
+// RedactedValue replaces secrets of a redacted config.
+const RedactedValue = "[redacted]"
+
+// Redacted returns a copy of the config, which can be written into result files.
+// Header values of endpoints and values of steps may contain secrets, i.e.
+// tokens or typed passwords, so they are replaced by RedactedValue.
+func (c *InstructorConfig) Redacted() *InstructorConfig {
+	cfg := *c
+
+	cfg.Endpoints = nil
+	for _, e := range c.Endpoints {
+		endpoint := *e
+		if e.Headers != nil {
+			endpoint.Headers = make(map[string]string, len(e.Headers))
+			for k := range e.Headers {
+				endpoint.Headers[k] = RedactedValue
+			}
+		}
+		cfg.Endpoints = append(cfg.Endpoints, &endpoint)
+	}
+
+	cfg.Journeys = nil
+	for _, j := range c.Journeys {
+		journey := *j
+		journey.Steps = redactSteps(j.Steps)
+		cfg.Journeys = append(cfg.Journeys, &journey)
+	}
+
+	cfg.Setup = redactSteps(c.Setup)
+
+	return &cfg
+}
+
+func redactSteps(steps []*InstructorStep) []*InstructorStep {
+	var redacted []*InstructorStep
+	for _, s := range steps {
+		step := *s
+		if step.Value != "" {
+			step.Value = RedactedValue
+		}
+		redacted = append(redacted, &step)
+	}
+
+	return redacted
 }
 
 func NewInstructorConfig(v *viper.Viper) (*InstructorConfig, error) {
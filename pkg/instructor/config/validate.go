@@ -3,14 +3,141 @@ package config
 import (
 	"errors"
 	"fmt"
+	"math"
+	"time"
 )
 
+// MaxDuration is the longest supported loadtest duration.
+const MaxDuration = time.Duration(math.MaxUint32) * time.Millisecond
+
 // ValidateInstructorConfig validates the instructor sub config
 func ValidateInstructorConfig(cfg *InstructorConfig) error {
 	if cfg == nil {
 		return errors.New("missing instructor config")
 	}
 
+	if cfg.Duration < 0 || cfg.Duration > MaxDuration {
+		return fmt.Errorf("invalid duration '%s'", cfg.Duration)
+	}
+
+	if cfg.Timeout < 0 || cfg.Timeout > MaxDuration {
+		return fmt.Errorf("invalid timeout '%s'", cfg.Timeout)
+	}
+
+	for i, v := range cfg.Stages {
+		if v.Duration <= 0 {
+			return fmt.Errorf("invalid duration '%s' of stage %d", v.Duration, i)
+		}
+
+		if v.Target < 0 {
+			return fmt.Errorf("invalid target '%d' of stage %d", v.Target, i)
+		}
+
+		if v.Ramp != "" && v.Ramp != RampLinear && v.Ramp != RampStep {
+			return fmt.Errorf("invalid ramp '%s' of stage %d", v.Ramp, i)
+		}
+	}
+
+	if len(cfg.Endpoints) == 0 && len(cfg.Journeys) == 0 {
+		return errors.New("no endpoints or journeys configured")
+	}
+
+	for _, e := range cfg.Endpoints {
+		if e.BasicAuth != nil && e.BasicAuth.Username == "" {
+			return fmt.Errorf("missing basic auth username of endpoint '%s'", e.Url)
+		}
+
+		if e.Timeout < 0 || e.Timeout > MaxDuration {
+			return fmt.Errorf("invalid timeout '%s' of endpoint '%s'", e.Timeout, e.Url)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, j := range cfg.Journeys {
+		if j.Name == "" || names[j.Name] {
+			return fmt.Errorf("invalid or duplicate name '%s' of journey %d", j.Name, i)
+		}
+		names[j.Name] = true
+
+		if j.Weight <= 0 {
+			return fmt.Errorf("invalid weight '%d' of journey '%s'", j.Weight, j.Name)
+		}
+
+		if len(j.Steps) == 0 {
+			return fmt.Errorf("no steps in journey '%s'", j.Name)
+		}
+
+		for k, s := range j.Steps {
+			if err := validateStep(s); err != nil {
+				return fmt.Errorf("invalid step %d of journey '%s': %v", k, j.Name, err)
+			}
+		}
+	}
+
+	for k, s := range cfg.Setup {
+		if err := validateStep(s); err != nil {
+			return fmt.Errorf("invalid setup step %d: %v", k, err)
+		}
+	}
+
+	if cfg.ChromeProcesses < 0 {
+		return fmt.Errorf("invalid chrome processes '%d'", cfg.ChromeProcesses)
+	}
+
+	switch cfg.Cache {
+	case "", CacheWarm, CacheCold:
+	case CacheMixed:
+		if cfg.ColdVisits < 0 || cfg.ColdVisits > 100 {
+			return fmt.Errorf("invalid cold visits '%d', must be a percentage", cfg.ColdVisits)
+		}
+	default:
+		return fmt.Errorf("invalid cache '%s'", cfg.Cache)
+	}
+
+	emulations := make(map[string]bool)
+	for i, e := range cfg.Emulations {
+		if e.Name == "" || emulations[e.Name] {
+			return fmt.Errorf("invalid or duplicate name '%s' of emulation %d", e.Name, i)
+		}
+		emulations[e.Name] = true
+
+		if err := validateEmulation(e); err != nil {
+			return fmt.Errorf("invalid emulation '%s': %v", e.Name, err)
+		}
+	}
+
+	if a := cfg.Artifacts; a != nil {
+		if a.Max < 0 || a.Max >= 10000 {
+			return fmt.Errorf("invalid artifacts max '%d'", a.Max)
+		}
+
+		if a.MaxSize < 0 || a.MaxSize > MaxArtifactsSize {
+			return fmt.Errorf("invalid artifacts max size '%d', must be at most %d bytes", a.MaxSize, MaxArtifactsSize)
+		}
+	}
+
+	if h := cfg.HAR; h != nil {
+		if h.First < 0 || h.First >= 10000 {
+			return fmt.Errorf("invalid har first '%d'", h.First)
+		}
+
+		if h.Slower < 0 || h.Slower > MaxDuration {
+			return fmt.Errorf("invalid har slower '%s'", h.Slower)
+		}
+
+		if h.Max < 0 || h.Max >= 10000 {
+			return fmt.Errorf("invalid har max '%d'", h.Max)
+		}
+
+		if h.First == 0 && h.Slower == 0 {
+			return errors.New("har samples no page loads, set first or slower")
+		}
+	}
+
+	if !validBrowser(cfg.Browser) {
+		return fmt.Errorf("invalid browser '%s'", cfg.Browser)
+	}
+
 	if len(cfg.Workers) == 0 {
 		return errors.New("no worker targets configured")
 	}
@@ -29,6 +156,83 @@ func ValidateInstructorConfig(cfg *InstructorConfig) error {
 		if v.Port == 0 {
 			return fmt.Errorf("invalid port '%d'", v.Port)
 		}
+
+		if !validBrowser(v.Browser) {
+			return fmt.Errorf("invalid browser '%s' of worker '%s'", v.Browser, v.Alias)
+		}
+
+		if v.Amount < 0 {
+			return fmt.Errorf("invalid amount '%d' of worker '%s'", v.Amount, v.Alias)
+		}
+
+		if v.ChromeProcesses < 0 {
+			return fmt.Errorf("invalid chrome processes '%d' of worker '%s'", v.ChromeProcesses, v.Alias)
+		}
+	}
+
+	return nil
+}
+
+func validateStep(s *InstructorStep) error {
+	switch s.Action {
+	case ActionNavigate:
+		if s.URL == "" {
+			return errors.New("missing url")
+		}
+	case ActionClick, ActionType, ActionSubmit, ActionWait:
+		if s.Selector == "" {
+			return errors.New("missing selector")
+		}
+	case ActionSleep:
+		if s.Duration <= 0 || s.Duration > MaxDuration {
+			return fmt.Errorf("invalid duration '%s'", s.Duration)
+		}
+	case ActionAssertText:
+		if s.Value == "" {
+			return errors.New("missing value")
+		}
+	case ActionSetCookie:
+		if s.URL == "" || s.Key == "" {
+			return errors.New("missing url or key")
+		}
+	case ActionSetLocalStorage:
+		if s.Key == "" {
+			return errors.New("missing key")
+		}
+	default:
+		return fmt.Errorf("invalid action '%s'", s.Action)
+	}
+
+	if s.Timeout < 0 || s.Timeout > MaxDuration {
+		return fmt.Errorf("invalid timeout '%s'", s.Timeout)
+	}
+
+	return nil
+}
+
+func validBrowser(b string) bool {
+	return b == "" || b == BrowserChrome || b == BrowserHTTP
+}
+
+func validateEmulation(e *InstructorEmulation) error {
+	if _, ok := devices[e.Device]; e.Device != "" && !ok {
+		return fmt.Errorf("invalid device '%s'", e.Device)
+	}
+
+	if _, ok := networks[e.Network]; e.Network != "" && !ok {
+		return fmt.Errorf("invalid network '%s'", e.Network)
+	}
+
+	if e.Weight < 0 {
+		return fmt.Errorf("invalid weight '%d'", e.Weight)
+	}
+
+	if e.Width < 0 || e.Height < 0 || e.ScaleFactor < 0 || e.CPUThrottling < 0 {
+		return errors.New("negative viewport, scale factor or cpu throttling")
+	}
+
+	if e.Latency < 0 || e.Latency > MaxDuration || e.Download < 0 || e.Upload < 0 {
+		return errors.New("invalid latency or bandwidth")
 	}
 
 	return nil
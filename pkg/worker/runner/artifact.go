@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrUnsupportedCapture is an error indicating that a runner can't capture pages.
+var ErrUnsupportedCapture = errors.New("capture not supported by runner")
+
+const (
+	// captureTimeout limits how long capturing a page may take,
+	// since a page of a failed request may be unresponsive.
+	captureTimeout = 10 * time.Second
+
+	// screenshotQuality is the JPEG quality of screenshots, which keeps them small.
+	screenshotQuality = 60
+)
+
+// An Artifact shows what the page of a runner looked like, i.e. after a failed request.
+type Artifact struct {
+	// URL of the page.
+	URL string
+
+	// Screenshot of the viewport as JPEG.
+	Screenshot []byte
+
+	// HTML of the page, if captured.
+	HTML string
+}
+
+// Capture takes a screenshot of the current page of the runner and, if html is true,
+// reads the HTML of the page.
+// ctx must be a valid runner context created with WithContext method of a runner instance.
+// Only the chrome runner supports captures.
+func Capture(ctx context.Context, html bool) (*Artifact, error) {
+	var r *ChromeRunner
+
+	switch v := FromContext(ctx).(type) {
+	case *ChromeRunner:
+		r = v
+	case nil:
+		return nil, ErrInvalidContext
+	default:
+		return nil, ErrUnsupportedCapture
+	}
+
+	a := &Artifact{}
+
+	actions := []chromedp.Action{
+		chromedp.Location(&a.URL),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			a.Screenshot, err = page.CaptureScreenshot().
+				WithFormat(page.CaptureScreenshotFormatJpeg).
+				WithQuality(screenshotQuality).
+				Do(ctx)
+			return err
+		}),
+	}
+
+	if html {
+		actions = append(actions, chromedp.Evaluate("document.documentElement.outerHTML", &a.HTML))
+	}
+
+	captureCtx, cancel := context.WithTimeout(ctx, captureTimeout)
+	defer cancel()
+
+	if err := r.Executor.Run(captureCtx, actions...); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chromedp/chromedp"
+	"github.com/dkorittki/loago/internal/pkg/testing/browser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapture_ChromeRunner(t *testing.T) {
+	e := browser.NewTestExecutor()
+	e.On("ListenTarget",
+		mock.MatchedBy(isChromeDPContext),
+		mock.AnythingOfType("func(interface {})")).
+		Once()
+
+	// location and screenshot, plus the HTML if requested
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(func(a []chromedp.Action) bool { return len(a) == 2 })).
+		Return(nil).
+		Once()
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(func(a []chromedp.Action) bool { return len(a) == 3 })).
+		Return(nil).
+		Once()
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), TestingKey{}, TestingVal))
+	defer cancel()
+	ctx = NewChromeRunner(1, e).WithContext(ctx)
+
+	a, err := Capture(ctx, false)
+	require.NoError(t, err)
+	assert.NotNil(t, a)
+
+	a, err = Capture(ctx, true)
+	require.NoError(t, err)
+	assert.NotNil(t, a)
+
+	e.AssertExpectations(t)
+}
+
+func TestCapture_Unsupported(t *testing.T) {
+	a, err := Capture(NewHTTPRunner(1, false).WithContext(context.Background()), true)
+	assert.Equal(t, ErrUnsupportedCapture, err)
+	assert.Nil(t, a)
+
+	a, err = Capture(context.Background(), true)
+	assert.Equal(t, ErrInvalidContext, err)
+	assert.Nil(t, a)
+}
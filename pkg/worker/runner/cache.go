@@ -0,0 +1,29 @@
+package runner
+
+import (
+	"context"
+	"net/http/cookiejar"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// ClearCache clears the cache and cookies of the runner, so its next
+// request is the one of a first-time visitor.
+// ctx must be a valid runner context created with WithContext method of a runner instance.
+// The HTTP runner has no cache, it only drops its cookies.
+func ClearCache(ctx context.Context) error {
+	switch r := FromContext(ctx).(type) {
+	case *ChromeRunner:
+		return r.Executor.Run(ctx, network.ClearBrowserCache(), network.ClearBrowserCookies())
+	case *HTTPRunner:
+		if r.Cookies {
+			// cookiejar.New never returns an error without options
+			r.client.Jar, _ = cookiejar.New(nil)
+		}
+		return nil
+	case *FakeRunner:
+		return nil
+	}
+
+	return ErrInvalidContext
+}
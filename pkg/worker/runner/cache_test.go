@@ -0,0 +1,59 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/dkorittki/loago/internal/pkg/testing/browser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClearCache_ChromeRunner(t *testing.T) {
+	e := browser.NewTestExecutor()
+	e.On("ListenTarget",
+		mock.MatchedBy(isChromeDPContext),
+		mock.AnythingOfType("func(interface {})")).
+		Once()
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(func(a []chromedp.Action) bool {
+			if len(a) != 2 {
+				return false
+			}
+
+			_, cache := a[0].(*network.ClearBrowserCacheParams)
+			_, cookies := a[1].(*network.ClearBrowserCookiesParams)
+			return cache && cookies
+		})).
+		Return(nil).
+		Once()
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), TestingKey{}, TestingVal))
+	defer cancel()
+	ctx = NewChromeRunner(1, e).WithContext(ctx)
+
+	require.NoError(t, ClearCache(ctx))
+	e.AssertExpectations(t)
+}
+
+func TestClearCache_HTTPRunner(t *testing.T) {
+	s := newTestHTTPServer(t)
+	ctx := NewHTTPRunner(1, true).WithContext(context.Background())
+
+	_, err := Call(ctx, s.URL+"/login")
+	require.NoError(t, err)
+
+	require.NoError(t, ClearCache(ctx))
+
+	res, err := Call(ctx, s.URL+"/account")
+	require.NoError(t, err)
+	assert.Equal(t, 401, res.StatusCode)
+}
+
+func TestClearCache_InvalidContext(t *testing.T) {
+	assert.Equal(t, ErrInvalidContext, ClearCache(context.Background()))
+}
@@ -4,9 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"time"
 
+	"github.com/chromedp/cdproto/fetch"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 	"github.com/dkorittki/loago/internal/pkg/worker/executor/browser"
@@ -34,8 +34,62 @@ type ChromeRunner struct {
 	// Executor interface for interacting with a browser communication library.
 	Executor browser.Executor
 
+	// Subresources enables collecting every resource loaded by a page.
+	// It must be set before WithContext is called.
+	Subresources bool
+
+	// HAR enables recording every page load as HTTP Archive.
+	// It must be set before WithContext is called.
+	HAR bool
+
+	// Emulation makes the browser emulate a device and network, if set.
+	// It is applied on the first call.
+	Emulation *Emulation
+
+	// Config configures how the runner launches chrome, if set.
+	// It must be set before WithContext is called.
+	Config *ChromeConfig
+
+	// BrowserContext hosts the runner in a shared chrome process of a ChromePool,
+	// if set. Otherwise the runner starts its own chrome process.
+	// It must be set before WithContext is called.
+	BrowserContext *BrowserContext
+
+	// Restarts is the amount of runners with the same ID, which this runner replaces,
+	// since their browser broke. Every replacement gets a fresh cache dir.
+	// It must be set before WithContext is called.
+	Restarts int
+
 	// Buffer for storing network events received from devtools protocols.
 	networkEventChan chan *network.EventResponseReceived
+
+	// Collector of subresources loaded during the current call.
+	subresources *subresourceCollector
+
+	// Recorder of the requests of the current call as HTTP Archive.
+	har *harRecorder
+
+	// Interceptor adding the extra headers of the current call
+	// to the requests to the origin of the page.
+	interceptor *requestInterceptor
+
+	// intercepting is true, while requests are intercepted by interceptor.
+	intercepting bool
+
+	// Collector of JavaScript errors of the current call.
+	jsErrors *jsErrorCollector
+
+	// Collector of the failed page load of the current call.
+	navigation *navigationCollector
+
+	// emulated is true, once the device of Emulation is emulated.
+	emulated bool
+
+	// User agent override of the browser, empty if it uses its default.
+	userAgent string
+
+	// Default user agent of the browser, read before it is overridden the first time.
+	defaultUserAgent string
 }
 
 // NewChromeRunner creates a new chrome runner instance.
@@ -44,6 +98,11 @@ func NewChromeRunner(id int, e browser.Executor) *ChromeRunner {
 		ID:               id,
 		Executor:         e,
 		networkEventChan: make(chan *network.EventResponseReceived, networkEventChanSize),
+		subresources:     newSubresourceCollector(),
+		har:              newHARRecorder(),
+		interceptor:      newRequestInterceptor(),
+		jsErrors:         newJSErrorCollector(),
+		navigation:       newNavigationCollector(),
 	}
 
 	return r
@@ -52,18 +111,27 @@ func NewChromeRunner(id int, e browser.Executor) *ChromeRunner {
 // WithContext derives a new context from ctx associated with both a runner and
 // chromedp configuration. This context can be used as a context to call the Run() method.
 // It also creates a new goroutine in background waiting for the context to be closed to
-// clean up ressources such as the cache dir.
+// clean up ressources such as the cache dir or the browser context.
 func (r *ChromeRunner) WithContext(ctx context.Context) context.Context {
-	cachedir := filepath.Join(os.TempDir(), CacheDirName, fmt.Sprintf("%d", r.ID))
+	var chromedpCtx context.Context
+
+	if r.BrowserContext != nil {
+		// browser contexts keep their cache in memory
+		chromedpCtx = r.BrowserContext.newContext(ctx)
+	} else {
+		name := fmt.Sprintf("%d", r.ID)
+		if r.Restarts > 0 {
+			name = fmt.Sprintf("%d-%d", r.ID, r.Restarts)
+		}
 
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.DisableGPU,
-		chromedp.UserDataDir(cachedir),
-	)
-	allocCtx, _ := chromedp.NewExecAllocator(ctx, opts...)
-	chromedpCtx, _ := chromedp.NewContext(allocCtx)
+		cachedir := r.Config.cacheDir(name)
+
+		allocCtx, _ := chromedp.NewExecAllocator(ctx, r.Config.allocatorOptions(cachedir)...)
+		chromedpCtx, _ = chromedp.NewContext(allocCtx)
+
+		r.CacheDir = cachedir
+	}
 
-	r.CacheDir = cachedir
 	runnerCtx := context.WithValue(chromedpCtx, contextKey{}, r)
 
 	// Watch context and clean up browser cache once it's canceled
@@ -78,6 +146,36 @@ func (r *ChromeRunner) WithContext(ctx context.Context) context.Context {
 				r.networkEventChan <- netEv
 			}
 		}
+
+		if r.Subresources {
+			r.subresources.handle(ev)
+		}
+
+		if r.HAR {
+			r.har.handle(ev)
+		}
+
+		r.jsErrors.handle(ev)
+		r.navigation.handle(ev)
+
+		// paused requests must be continued outside of the listener,
+		// which would block the event loop of chromedp otherwise.
+		// Requests, which can't be continued, are failed, so the page
+		// doesn't wait for them until it times out.
+		if a := r.interceptor.handle(ev); a != nil {
+			id := ev.(*fetch.EventRequestPaused).RequestID
+			go func() {
+				if err := r.Executor.Run(chromedpCtx, a); err != nil {
+					log.Debug().
+						Str("component", "runner").
+						Int("id", r.ID).
+						Err(err).
+						Msg("cannot continue intercepted request")
+
+					_ = r.Executor.Run(chromedpCtx, fetch.FailRequest(id, network.ErrorReasonFailed))
+				}
+			}()
+		}
 	})
 
 	return runnerCtx
@@ -92,6 +190,11 @@ func cancel(ctx context.Context) func() {
 		// close network event buffer.
 		close(r.networkEventChan)
 
+		if r.BrowserContext != nil {
+			r.BrowserContext.dispose()
+			return
+		}
+
 		log.Debug().
 			Str("component", "runner").
 			Int("id", r.ID).
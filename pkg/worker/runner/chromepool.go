@@ -0,0 +1,318 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrEmptyChromePool is an error indicating that a chrome pool has no chrome process.
+	ErrEmptyChromePool = errors.New("chrome pool without chrome processes")
+
+	// errNoBrowser is an error indicating that a chrome process of a pool failed to relaunch.
+	errNoBrowser = errors.New("chrome process of pool isn't running")
+)
+
+// A ChromePool hosts chrome runners in a few shared chrome processes instead of
+// a chrome process per runner. Every runner gets its own browser context, which
+// has its own in-memory cache and cookies like an incognito window, so it still
+// acts as an independent user, but needs only a fraction of the memory.
+// The chrome processes are either started by the pool or already running remotely.
+type ChromePool struct {
+	mu sync.Mutex
+
+	// ctx is the context, which the chrome processes run with.
+	ctx context.Context
+
+	// browsers contains the chromedp contexts of the chrome processes.
+	browsers []context.Context
+	cancels  []context.CancelFunc
+
+	// allocators create the chromedp allocators of the chrome processes,
+	// so a chrome process, which crashed or hangs, can be relaunched.
+	allocators []allocator
+
+	// next is the index of the browser hosting the next browser context.
+	next int
+
+	// cacheDirs contains the cache dirs of the chrome processes.
+	cacheDirs []string
+}
+
+// allocator creates a chromedp allocator, which starts
+// or connects to a chrome process, derived from ctx.
+type allocator func(ctx context.Context) (context.Context, context.CancelFunc)
+
+// NewChromePool starts size chrome processes launched according to cfg,
+// which run until Close is called or ctx is canceled. A nil cfg launches
+// chrome with the default configuration.
+func NewChromePool(ctx context.Context, size int, cfg *ChromeConfig) (*ChromePool, error) {
+	if size <= 0 {
+		return nil, ErrEmptyChromePool
+	}
+
+	p := &ChromePool{ctx: ctx}
+
+	for i := 0; i < size; i++ {
+		cacheDir := cfg.cacheDir(fmt.Sprintf("pool-%d", i))
+		p.cacheDirs = append(p.cacheDirs, cacheDir)
+
+		alloc := func(ctx context.Context) (context.Context, context.CancelFunc) {
+			return chromedp.NewExecAllocator(ctx, cfg.allocatorOptions(cacheDir)...)
+		}
+		if err := p.add(alloc); err != nil {
+			p.Close()
+			return nil, err
+		}
+	}
+
+	log.Info().
+		Str("component", "runner").
+		Int("size", size).
+		Msg("started chrome pool")
+
+	return p, nil
+}
+
+// NewRemoteChromePool attaches to chrome processes, which are already running,
+// i.e. in separate containers, via their DevTools WebSocket URLs like
+// "ws://10.0.0.5:9222/devtools/browser/<id>". Close disconnects from them,
+// but leaves them running.
+func NewRemoteChromePool(ctx context.Context, urls []string) (*ChromePool, error) {
+	if len(urls) == 0 {
+		return nil, ErrEmptyChromePool
+	}
+
+	p := &ChromePool{ctx: ctx}
+
+	for _, url := range urls {
+		url := url
+		alloc := func(ctx context.Context) (context.Context, context.CancelFunc) {
+			return chromedp.NewRemoteAllocator(ctx, url)
+		}
+		if err := p.add(alloc); err != nil {
+			p.Close()
+			return nil, fmt.Errorf("can't attach to chrome at %s: %v", url, err)
+		}
+	}
+
+	log.Info().
+		Str("component", "runner").
+		Strs("urls", urls).
+		Msg("attached to remote chrome pool")
+
+	return p, nil
+}
+
+// add adds the browser of alloc to the pool and starts or connects to it.
+func (p *ChromePool) add(alloc allocator) error {
+	browserCtx, cancel, err := p.launch(alloc)
+
+	p.browsers = append(p.browsers, browserCtx)
+	p.cancels = append(p.cancels, cancel)
+	p.allocators = append(p.allocators, alloc)
+
+	return err
+}
+
+// launch starts or connects to the browser of alloc.
+// The returned cancel function stops or disconnects from it.
+func (p *ChromePool) launch(alloc allocator) (context.Context, context.CancelFunc, error) {
+	allocCtx, cancelAlloc := alloc(p.ctx)
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+
+	cancel := func() {
+		cancelBrowser()
+		cancelAlloc()
+	}
+
+	// the first run starts the chrome process or connects to it
+	return browserCtx, cancel, chromedp.Run(browserCtx)
+}
+
+// relaunch replaces the i-th chrome process of the pool, if its browser is
+// still broken, and returns the browser replacing it. Runners hosted by
+// the broken browser fail and are restarted in the new one.
+func (p *ChromePool) relaunch(i int, broken context.Context) (context.Context, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancels == nil {
+		return nil, ErrEmptyChromePool
+	}
+
+	// another runner relaunched it already
+	if p.browsers[i] != broken {
+		return p.browsers[i], nil
+	}
+
+	log.Warn().
+		Str("component", "runner").
+		Int("index", i).
+		Msg("relaunch chrome process of pool")
+
+	p.cancels[i]()
+	if i < len(p.cacheDirs) {
+		_ = os.RemoveAll(p.cacheDirs[i])
+	}
+
+	browserCtx, cancel, err := p.launch(p.allocators[i])
+	p.browsers[i] = browserCtx
+	p.cancels[i] = cancel
+	if err != nil {
+		return nil, err
+	}
+
+	return browserCtx, nil
+}
+
+// Close stops every chrome process of the pool and deletes their cache dirs.
+func (p *ChromePool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, cancel := range p.cancels {
+		cancel()
+	}
+	p.cancels = nil
+
+	for _, dir := range p.cacheDirs {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warn().
+				Str("component", "runner").
+				Str("cachedir", dir).
+				Err(err).
+				Msg("can't delete cache")
+		}
+	}
+	p.cacheDirs = nil
+}
+
+// NewBrowserContext creates a browser context with a blank page.
+// Browser contexts are spread evenly across the chrome processes of the pool.
+// If the chrome process doesn't create it, since it crashed or hangs,
+// the chrome process is relaunched once.
+func (p *ChromePool) NewBrowserContext(ctx context.Context) (*BrowserContext, error) {
+	p.mu.Lock()
+	if p.cancels == nil {
+		p.mu.Unlock()
+		return nil, ErrEmptyChromePool
+	}
+	i := p.next % len(p.browsers)
+	host := p.browsers[i]
+	p.next++
+	p.mu.Unlock()
+
+	bc, err := newBrowserContext(ctx, host)
+	if err == nil || ctx.Err() != nil {
+		return bc, err
+	}
+
+	log.Warn().
+		Str("component", "runner").
+		Int("index", i).
+		Err(err).
+		Msg("can't create browser context")
+
+	host, err = p.relaunch(i, host)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBrowserContext(ctx, host)
+}
+
+// newBrowserContext creates a browser context with a blank page in the browser of host.
+// It fails with ErrBrowserUnresponsive, if the browser doesn't respond within responseTimeout.
+func newBrowserContext(ctx, host context.Context) (*BrowserContext, error) {
+	createCtx, cancel := context.WithTimeout(ctx, responseTimeout)
+	defer cancel()
+
+	bc, err := createBrowserContext(createCtx, host)
+	if err != nil && ctx.Err() == nil && createCtx.Err() == context.DeadlineExceeded {
+		return nil, ErrBrowserUnresponsive
+	}
+
+	return bc, err
+}
+
+func createBrowserContext(ctx, host context.Context) (*BrowserContext, error) {
+	browser := chromedp.FromContext(host).Browser
+	if browser == nil {
+		return nil, errNoBrowser
+	}
+	execCtx := cdp.WithExecutor(ctx, browser)
+
+	id, err := target.CreateBrowserContext().Do(execCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	bc := &BrowserContext{host: host, browser: browser, id: id}
+
+	bc.target, err = target.CreateTarget("about:blank").WithBrowserContextID(id).Do(execCtx)
+	if err != nil {
+		bc.dispose()
+		return nil, err
+	}
+
+	return bc, nil
+}
+
+// A BrowserContext is an isolated browser context of a chrome process of a ChromePool.
+type BrowserContext struct {
+	host    context.Context
+	browser *chromedp.Browser
+	id      cdp.BrowserContextID
+
+	// target is the page of the browser context.
+	target target.ID
+}
+
+// newContext returns a chromedp context of the page of the browser context,
+// which is canceled with ctx.
+func (bc *BrowserContext) newContext(ctx context.Context) context.Context {
+	chromedpCtx, _ := chromedp.NewContext(&hostedContext{Context: ctx, host: bc.host}, chromedp.WithTargetID(bc.target))
+	return chromedpCtx
+}
+
+// dispose closes the browser context including its page and drops its cache and cookies.
+func (bc *BrowserContext) dispose() {
+	// the context of the runner is canceled already
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := target.DisposeBrowserContext(bc.id).Do(cdp.WithExecutor(ctx, bc.browser))
+	if err != nil {
+		log.Warn().
+			Str("component", "runner").
+			Str("browser_context", string(bc.id)).
+			Err(err).
+			Msg("can't dispose browser context")
+	}
+}
+
+// hostedContext is canceled with its own parent, but inherits the
+// chromedp browser of host, so chromedp contexts derived from it
+// attach to the browser of host instead of starting a new one.
+type hostedContext struct {
+	context.Context
+	host context.Context
+}
+
+func (c *hostedContext) Value(key interface{}) interface{} {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+
+	return c.host.Value(key)
+}
@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type hostKey struct{}
+
+func TestNewChromePool_Empty(t *testing.T) {
+	p, err := NewChromePool(context.Background(), 0, nil)
+	assert.Equal(t, ErrEmptyChromePool, err)
+	assert.Nil(t, p)
+
+	p, err = NewRemoteChromePool(context.Background(), nil)
+	assert.Equal(t, ErrEmptyChromePool, err)
+	assert.Nil(t, p)
+}
+
+func TestNewRemoteChromePool_Unreachable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	p, err := NewRemoteChromePool(ctx, []string{"ws://127.0.0.1:1/devtools/browser/none"})
+	assert.Error(t, err)
+	assert.Nil(t, p)
+}
+
+func TestChromePool_Relaunch(t *testing.T) {
+	broken, current := context.Background(), context.WithValue(context.Background(), hostKey{}, "current")
+
+	p := &ChromePool{
+		browsers: []context.Context{current},
+		cancels:  []context.CancelFunc{func() { t.Fatal("relaunched twice") }},
+	}
+
+	// another runner relaunched the broken chrome process already
+	host, err := p.relaunch(0, broken)
+	assert.NoError(t, err)
+	assert.Equal(t, current, host)
+
+	// closed pools neither relaunch nor create browser contexts
+	p.cancels = nil
+	_, err = p.relaunch(0, current)
+	assert.Equal(t, ErrEmptyChromePool, err)
+
+	_, err = p.NewBrowserContext(context.Background())
+	assert.Equal(t, ErrEmptyChromePool, err)
+}
+
+func TestHostedContext(t *testing.T) {
+	host, cancelHost := context.WithCancel(context.WithValue(context.Background(), hostKey{}, "host"))
+	defer cancelHost()
+
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), TestingKey{}, TestingVal))
+	ctx, cancelChild := context.WithCancel(&hostedContext{Context: parent, host: host})
+	defer cancelChild()
+
+	// values of the parent take precedence over values of the host
+	assert.Equal(t, "host", ctx.Value(hostKey{}))
+	assert.Equal(t, TestingVal, ctx.Value(TestingKey{}))
+
+	// only the parent cancels the context
+	cancelHost()
+	assert.NoError(t, ctx.Err())
+
+	cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
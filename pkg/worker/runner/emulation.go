@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// Emulation makes a chrome browser emulate a device on a network,
+// i.e. a mid-range phone on a slow 3G connection.
+type Emulation struct {
+	// Name of the emulation profile, under which results are reported.
+	Name string
+
+	// Width and Height of the viewport in CSS pixels.
+	// Zero keeps the size of the browser window.
+	Width  int64
+	Height int64
+
+	// DeviceScaleFactor is the ratio of device pixels to CSS pixels.
+	// Zero keeps the factor of the browser.
+	DeviceScaleFactor float64
+
+	// Mobile emulates a mobile device, including touch events.
+	Mobile bool
+
+	// UserAgent overrides the user agent of the browser, if not empty.
+	// It is overridden by the user agent of request options.
+	UserAgent string
+
+	// CPUThrottling slows down the CPU by this factor, i.e. 4 for a mid-range phone.
+	// Factors of one or less disable throttling.
+	CPUThrottling float64
+
+	// Latency is added to every request.
+	Latency time.Duration
+
+	// DownloadThroughput and UploadThroughput limit the bandwidth
+	// in bytes per second. Zero disables the limit.
+	DownloadThroughput int64
+	UploadThroughput   int64
+}
+
+// device returns the actions emulating the device, which last for the life of the browser.
+func (e *Emulation) device() []chromedp.Action {
+	actions := []chromedp.Action{
+		emulation.SetDeviceMetricsOverride(e.Width, e.Height, e.DeviceScaleFactor, e.Mobile),
+		emulation.SetTouchEmulationEnabled(e.Mobile),
+	}
+
+	if e.CPUThrottling > 1 {
+		actions = append(actions, emulation.SetCPUThrottlingRate(e.CPUThrottling))
+	}
+
+	return actions
+}
+
+// throttled returns true, if the network is throttled.
+func (e *Emulation) throttled() bool {
+	return e.Latency > 0 || e.DownloadThroughput > 0 || e.UploadThroughput > 0
+}
+
+// network returns the action throttling the network.
+// Like extra headers, it is dropped once the network domain is disabled.
+func (e *Emulation) network() chromedp.Action {
+	// chrome disables throughput limits of -1
+	throughput := func(t int64) float64 {
+		if t <= 0 {
+			return -1
+		}
+		return float64(t)
+	}
+
+	return network.EmulateNetworkConditions(
+		false,
+		float64(e.Latency)/float64(time.Millisecond),
+		throughput(e.DownloadThroughput),
+		throughput(e.UploadThroughput),
+	)
+}
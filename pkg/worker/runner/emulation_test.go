@@ -0,0 +1,80 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/dkorittki/loago/internal/pkg/testing/browser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmulation_Network(t *testing.T) {
+	e := &Emulation{Latency: 2 * time.Second, DownloadThroughput: 50000}
+	assert.True(t, e.throttled())
+	assert.Equal(t, network.EmulateNetworkConditions(false, 2000, 50000, -1), e.network())
+
+	assert.False(t, (&Emulation{Mobile: true}).throttled())
+}
+
+func TestApplyChrome_Emulation(t *testing.T) {
+	// the device is emulated once, the network on every call
+	e := browser.NewTestExecutor()
+	e.On("Run",
+		mock.Anything,
+		mock.MatchedBy(func(a []chromedp.Action) bool {
+			if len(a) != 4 {
+				return false
+			}
+
+			d, ok := a[0].(*emulation.SetDeviceMetricsOverrideParams)
+			if !ok || d.Width != 360 || d.Height != 640 || d.DeviceScaleFactor != 3 || !d.Mobile {
+				return false
+			}
+
+			c, ok := a[2].(*emulation.SetCPUThrottlingRateParams)
+			if !ok || c.Rate != 4 {
+				return false
+			}
+
+			_, ok = a[3].(*network.EmulateNetworkConditionsParams)
+			return ok
+		})).
+		Return(nil).
+		Once()
+	e.On("Run",
+		mock.Anything,
+		mock.MatchedBy(func(a []chromedp.Action) bool {
+			if len(a) != 1 {
+				return false
+			}
+
+			_, ok := a[0].(*network.EmulateNetworkConditionsParams)
+			return ok
+		})).
+		Return(nil).
+		Once()
+
+	r := NewChromeRunner(1, e)
+	r.Emulation = &Emulation{
+		Name:               "moto g4 on slow 3g",
+		Width:              360,
+		Height:             640,
+		DeviceScaleFactor:  3,
+		Mobile:             true,
+		CPUThrottling:      4,
+		Latency:            2 * time.Second,
+		DownloadThroughput: 50000,
+		UploadThroughput:   50000,
+	}
+
+	require.NoError(t, applyChrome(context.Background(), r, "", nil))
+	require.NoError(t, applyChrome(context.Background(), r, "", nil))
+
+	e.AssertExpectations(t)
+}
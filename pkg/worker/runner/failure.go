@@ -0,0 +1,179 @@
+package runner
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// ErrorClass classifies why a request failed.
+type ErrorClass string
+
+const (
+	// ErrorClassDNS indicates that the host name could not be resolved.
+	ErrorClassDNS ErrorClass = "dns"
+
+	// ErrorClassConnect indicates that no connection could be established
+	// or that it was reset.
+	ErrorClassConnect ErrorClass = "connect"
+
+	// ErrorClassTLS indicates a failed TLS handshake or an invalid certificate.
+	ErrorClassTLS ErrorClass = "tls"
+
+	// ErrorClassTimeout indicates that the request timed out.
+	ErrorClassTimeout ErrorClass = "timeout"
+
+	// ErrorClassNavigation indicates any other failure to load the page.
+	ErrorClassNavigation ErrorClass = "navigation"
+
+	// ErrorClassNoResponse indicates that the server closed the connection
+	// without a response or that the browser reported no response.
+	ErrorClassNoResponse ErrorClass = "no-response"
+
+	// ErrorClassAssertion indicates that a journey step asserting the text
+	// of a page failed.
+	ErrorClassAssertion ErrorClass = "assertion"
+)
+
+// A NavigationError is an error indicating that the browser failed to load a page.
+type NavigationError struct {
+	// URL of the document, which failed to load.
+	URL string
+
+	// Text is the network error reported by the browser, i.e. "net::ERR_NAME_NOT_RESOLVED".
+	Text string
+}
+
+func (e *NavigationError) Error() string {
+	return fmt.Sprintf("can't load %s: %s", e.URL, e.Text)
+}
+
+// ClassifyError returns the class of err, if err is a failure of the request
+// like an unresolvable host or a refused connection, or a failed journey step
+// like a timed out wait or assertion, which a loadtest measures. It returns
+// an empty class for every other error, i.e. a canceled context or a broken
+// browser, after which the runner can't perform requests anymore.
+func ClassifyError(err error) ErrorClass {
+	var (
+		navErr  *NavigationError
+		dnsErr  *net.DNSError
+		opErr   *net.OpError
+		urlErr  *url.Error
+		netErr  net.Error
+		certErr x509.CertificateInvalidError
+		hostErr x509.HostnameError
+		authErr x509.UnknownAuthorityError
+		recErr  tls.RecordHeaderError
+	)
+
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+		return ""
+	case errors.As(err, &navErr):
+		return classifyNetError(navErr.Text)
+	case errors.Is(err, ErrNoNetworkEventFound):
+		return ErrorClassNoResponse
+	case errors.Is(err, ErrRequestTimeout), errors.Is(err, ErrStepTimeout), errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, ErrAssertionFailed):
+		return ErrorClassAssertion
+	case errors.As(err, &dnsErr):
+		return ErrorClassDNS
+	case errors.As(err, &certErr), errors.As(err, &hostErr), errors.As(err, &authErr), errors.As(err, &recErr):
+		return ErrorClassTLS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return ErrorClassConnect
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorClassNoResponse
+	case errors.As(err, &opErr):
+		return ErrorClassConnect
+	case errors.As(err, &urlErr):
+		// every other error of a HTTP client is a failed request as well
+		return ErrorClassNavigation
+	}
+
+	return ""
+}
+
+// classifyNetError returns the class of a network error of chrome.
+// See: https://source.chromium.org/chromium/chromium/src/+/master:net/base/net_error_list.h
+func classifyNetError(text string) ErrorClass {
+	code := strings.TrimPrefix(text, "net::ERR_")
+
+	switch {
+	case code == "NAME_NOT_RESOLVED", code == "NAME_RESOLUTION_FAILED":
+		return ErrorClassDNS
+	case code == "TIMED_OUT", code == "CONNECTION_TIMED_OUT":
+		return ErrorClassTimeout
+	case code == "EMPTY_RESPONSE", code == "CONNECTION_CLOSED":
+		return ErrorClassNoResponse
+	case strings.HasPrefix(code, "CERT_"), strings.HasPrefix(code, "SSL_"):
+		return ErrorClassTLS
+	case strings.HasPrefix(code, "CONNECTION_"), strings.HasPrefix(code, "ADDRESS_"),
+		code == "INTERNET_DISCONNECTED", code == "PROXY_CONNECTION_FAILED":
+		return ErrorClassConnect
+	}
+
+	return ErrorClassNavigation
+}
+
+// navigationCollector collects the first failed document load of a call from network events.
+// Events are delivered by the browser connection while a call is in progress,
+// so access is synchronized.
+type navigationCollector struct {
+	mu        sync.Mutex
+	documents map[network.RequestID]string
+	err       *NavigationError
+}
+
+func newNavigationCollector() *navigationCollector {
+	return &navigationCollector{documents: make(map[network.RequestID]string)}
+}
+
+// handle records the failure of a document load in ev, if it's the first one.
+func (c *navigationCollector) handle(ev interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		if ev.Type == network.ResourceTypeDocument {
+			c.documents[ev.RequestID] = ev.Request.URL
+		}
+	case *network.EventLoadingFailed:
+		url, ok := c.documents[ev.RequestID]
+		if !ok || ev.Canceled || c.err != nil {
+			return
+		}
+
+		c.err = &NavigationError{URL: url, Text: ev.ErrorText}
+	}
+}
+
+// take returns the first failed document load, if any, and resets the collector.
+func (c *navigationCollector) take() *NavigationError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	err := c.err
+	c.documents = make(map[network.RequestID]string)
+	c.err = nil
+
+	return err
+}
+
+// reset drops every collected document load.
+func (c *navigationCollector) reset() {
+	c.take()
+}
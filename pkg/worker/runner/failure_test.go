@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err   error
+		class ErrorClass
+	}{
+		{nil, ""},
+		{context.Canceled, ""},
+		{errors.New("websocket: close 1006"), ""},
+		{&NavigationError{URL: "http://foo.bar", Text: "net::ERR_NAME_NOT_RESOLVED"}, ErrorClassDNS},
+		{&NavigationError{URL: "http://foo.bar", Text: "net::ERR_CONNECTION_REFUSED"}, ErrorClassConnect},
+		{&NavigationError{URL: "https://foo.bar", Text: "net::ERR_CERT_AUTHORITY_INVALID"}, ErrorClassTLS},
+		{&NavigationError{URL: "http://foo.bar", Text: "net::ERR_CONNECTION_TIMED_OUT"}, ErrorClassTimeout},
+		{&NavigationError{URL: "http://foo.bar", Text: "net::ERR_EMPTY_RESPONSE"}, ErrorClassNoResponse},
+		{&NavigationError{URL: "http://foo.bar", Text: "net::ERR_TOO_MANY_REDIRECTS"}, ErrorClassNavigation},
+		{fmt.Errorf("step: %w", ErrNoNetworkEventFound), ErrorClassNoResponse},
+		{ErrRequestTimeout, ErrorClassTimeout},
+		{ErrStepTimeout, ErrorClassTimeout},
+		{fmt.Errorf("step: %w", ErrAssertionFailed), ErrorClassAssertion},
+		{ErrUnsupportedStep, ""},
+		{context.DeadlineExceeded, ErrorClassTimeout},
+		{ErrBrowserUnresponsive, ""},
+		{&url.Error{Op: "Get", URL: "http://foo.bar", Err: &net.DNSError{Err: "no such host", Name: "foo.bar"}}, ErrorClassDNS},
+		{&url.Error{Op: "Get", URL: "http://foo.bar", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, ErrorClassConnect},
+		{&url.Error{Op: "Get", URL: "http://foo.bar", Err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}}, ErrorClassConnect},
+		{&url.Error{Op: "Get", URL: "https://foo.bar", Err: x509.UnknownAuthorityError{}}, ErrorClassTLS},
+		{&url.Error{Op: "Get", URL: "http://foo.bar", Err: io.EOF}, ErrorClassNoResponse},
+		{&url.Error{Op: "Get", URL: "foo.bar", Err: errors.New("unsupported protocol scheme")}, ErrorClassNavigation},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.class, ClassifyError(tt.err), "%v", tt.err)
+	}
+}
+
+func TestClassifyError_HTTPRunner(t *testing.T) {
+	// a closed server refuses connections
+	s := httptest.NewServer(nil)
+	s.Close()
+
+	r := NewHTTPRunner(1, false)
+	ctx := r.WithContext(context.Background())
+
+	res, err := Call(ctx, s.URL)
+
+	assert.Nil(t, res)
+	assert.Equal(t, ErrorClassConnect, ClassifyError(err))
+}
+
+func TestNavigationCollector(t *testing.T) {
+	c := newNavigationCollector()
+
+	events := []interface{}{
+		&network.EventRequestWillBeSent{RequestID: "1", Type: network.ResourceTypeDocument, Request: &network.Request{URL: "http://foo.bar"}},
+		&network.EventRequestWillBeSent{RequestID: "2", Type: network.ResourceTypeScript, Request: &network.Request{URL: "http://foo.bar/app.js"}},
+		// failed subresources are reported as subresources
+		&network.EventLoadingFailed{RequestID: "2", ErrorText: "net::ERR_CONNECTION_RESET"},
+		&network.EventLoadingFailed{RequestID: "1", ErrorText: "net::ERR_NAME_NOT_RESOLVED"},
+		&network.EventRequestWillBeSent{RequestID: "3", Type: network.ResourceTypeDocument, Request: &network.Request{URL: "http://foo.bar/frame"}},
+		&network.EventLoadingFailed{RequestID: "3", ErrorText: "net::ERR_ABORTED"},
+	}
+
+	for _, ev := range events {
+		c.handle(ev)
+	}
+
+	assert.Equal(t, &NavigationError{URL: "http://foo.bar", Text: "net::ERR_NAME_NOT_RESOLVED"}, c.take())
+	assert.Nil(t, c.take())
+
+	// loads canceled by the runner aren't failures
+	c.handle(&network.EventRequestWillBeSent{RequestID: "4", Type: network.ResourceTypeDocument, Request: &network.Request{URL: "http://foo.bar"}})
+	c.handle(&network.EventLoadingFailed{RequestID: "4", ErrorText: "net::ERR_ABORTED", Canceled: true})
+	assert.Nil(t, c.take())
+}
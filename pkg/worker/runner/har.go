@@ -0,0 +1,445 @@
+package runner
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+)
+
+// HAR is a page load in the HTTP Archive format 1.2, which browser devtools
+// and other tools import.
+// See: http://www.softwareishard.com/blog/har-12-spec/
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root of a HTTP Archive.
+type HARLog struct {
+	Version string      `json:"version"`
+	Creator HARCreator  `json:"creator"`
+	Pages   []*HARPage  `json:"pages"`
+	Entries []*HAREntry `json:"entries"`
+}
+
+// HARCreator names the application, which created a HTTP Archive.
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HARPage is a page of a HTTP Archive.
+type HARPage struct {
+	StartedDateTime time.Time      `json:"startedDateTime"`
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	PageTimings     HARPageTimings `json:"pageTimings"`
+}
+
+// HARPageTimings contains the page load timings in milliseconds
+// since the start of the page load, -1 if absent.
+type HARPageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+// HAREntry is a single request of a page.
+type HAREntry struct {
+	Pageref         string      `json:"pageref"`
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           HARCache    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+
+	// ResourceType and Error are custom fields, named like the ones of chrome devtools.
+	ResourceType string `json:"_resourceType,omitempty"`
+	Error        string `json:"_error,omitempty"`
+}
+
+// HARRequest is the request of an entry.
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+}
+
+// HARResponse is the response of an entry.
+// Its status is zero, if the request failed.
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int64          `json:"headersSize"`
+	BodySize    int64          `json:"bodySize"`
+
+	// TransferSize is the amount of bytes received over the network, including headers.
+	TransferSize int64 `json:"_transferSize"`
+}
+
+// HARNameValue is a header, query parameter or cookie.
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARPostData is the body of a request.
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// HARContent describes the body of a response. The body itself isn't recorded.
+type HARContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+// HARCache is the cache usage of an entry, which isn't recorded.
+type HARCache struct{}
+
+// HARTimings contains the phases of a request in milliseconds, -1 if they didn't happen.
+// Connect includes SSL.
+type HARTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// harPageID is the ID of the only page of a recorded HTTP Archive.
+const harPageID = "page_1"
+
+// harRecord is an entry while its request is in progress.
+type harRecord struct {
+	entry *HAREntry
+
+	// received and finished are the monotonic times
+	// of the response and the end of loading.
+	received, finished time.Time
+
+	timing *network.ResourceTiming
+	done   bool
+}
+
+// harRecorder records every request of a page from network events.
+// Events are delivered by the browser connection while a call is in progress,
+// so access is synchronized.
+type harRecorder struct {
+	mu       sync.Mutex
+	records  []*harRecord
+	requests map[network.RequestID]*harRecord
+}
+
+func newHARRecorder() *harRecorder {
+	return &harRecorder{requests: make(map[network.RequestID]*harRecord)}
+}
+
+// handle updates the recorded requests with ev.
+func (h *harRecorder) handle(ev interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		// a redirect finishes the previous request with the same ID
+		if rec, ok := h.requests[ev.RequestID]; ok && ev.RedirectResponse != nil {
+			rec.respond(ev.RedirectResponse, monotonic(ev.Timestamp))
+			rec.entry.Response.RedirectURL = ev.Request.URL
+			rec.finished = rec.received
+			rec.done = true
+		}
+
+		rec := &harRecord{
+			entry: &HAREntry{
+				Pageref:      harPageID,
+				Request:      harRequest(ev.Request),
+				ResourceType: ev.Type.String(),
+				Response: HARResponse{
+					Cookies:     []HARNameValue{},
+					Headers:     []HARNameValue{},
+					HeadersSize: -1,
+					BodySize:    -1,
+				},
+			},
+		}
+
+		if ev.WallTime != nil {
+			rec.entry.StartedDateTime = ev.WallTime.Time()
+		}
+
+		h.records = append(h.records, rec)
+		h.requests[ev.RequestID] = rec
+	case *network.EventResponseReceived:
+		if rec, ok := h.requests[ev.RequestID]; ok {
+			rec.respond(ev.Response, monotonic(ev.Timestamp))
+		}
+	case *network.EventDataReceived:
+		if rec, ok := h.requests[ev.RequestID]; ok {
+			rec.entry.Response.Content.Size += ev.DataLength
+		}
+	case *network.EventLoadingFinished:
+		if rec, ok := h.requests[ev.RequestID]; ok {
+			rec.entry.Response.TransferSize = int64(ev.EncodedDataLength)
+			rec.finished = monotonic(ev.Timestamp)
+			rec.done = true
+		}
+	case *network.EventLoadingFailed:
+		if rec, ok := h.requests[ev.RequestID]; ok {
+			rec.entry.Error = ev.ErrorText
+			rec.finished = monotonic(ev.Timestamp)
+			rec.done = true
+		}
+	}
+}
+
+// take returns the recorded requests as HTTP Archive of the page at url, which loaded
+// with timing, and resets the recorder. Requests still in flight are dropped.
+func (h *harRecorder) take(url string, timing PageTiming) *HAR {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	page := &HARPage{
+		ID:    harPageID,
+		Title: url,
+		PageTimings: HARPageTimings{
+			OnContentLoad: harDuration(timing.DOMContentLoaded),
+			OnLoad:        harDuration(timing.Load),
+		},
+	}
+
+	entries := []*HAREntry{}
+	for _, rec := range h.records {
+		if !rec.done {
+			continue
+		}
+
+		rec.entry.Timings = harTimings(rec.timing, receiveTime(rec))
+		rec.entry.Time = rec.entry.Timings.total()
+		entries = append(entries, rec.entry)
+	}
+
+	if len(entries) > 0 {
+		page.StartedDateTime = entries[0].StartedDateTime
+	}
+
+	h.records = nil
+	h.requests = make(map[network.RequestID]*harRecord)
+
+	return &HAR{
+		Log: HARLog{
+			Version: "1.2",
+			Creator: HARCreator{Name: "loago"},
+			Pages:   []*HARPage{page},
+			Entries: entries,
+		},
+	}
+}
+
+// reset drops every recorded request.
+func (h *harRecorder) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records = nil
+	h.requests = make(map[network.RequestID]*harRecord)
+}
+
+// respond records the response r of the request, received at t.
+func (rec *harRecord) respond(r *network.Response, t time.Time) {
+	headers := r.RequestHeaders
+	if len(headers) > 0 {
+		// the headers actually sent replace the ones known before sending
+		rec.entry.Request.Headers = harHeaders(headers)
+	}
+
+	res := &rec.entry.Response
+	res.Status = int(r.Status)
+	res.StatusText = r.StatusText
+	res.HTTPVersion = r.Protocol
+	res.Headers = harHeaders(r.Headers)
+	res.Content.MimeType = r.MimeType
+
+	rec.entry.Request.HTTPVersion = r.Protocol
+	rec.entry.ServerIPAddress = r.RemoteIPAddress
+	rec.received = t
+
+	if !isCached(r) {
+		rec.timing = r.Timing
+	}
+}
+
+func harRequest(r *network.Request) HARRequest {
+	req := HARRequest{
+		Method:      r.Method,
+		URL:         r.URL + r.URLFragment,
+		Cookies:     []HARNameValue{},
+		Headers:     harHeaders(r.Headers),
+		QueryString: []HARNameValue{},
+		HeadersSize: -1,
+		BodySize:    int64(len(r.PostData)),
+	}
+
+	if u, err := url.Parse(r.URL); err == nil {
+		for name, values := range u.Query() {
+			for _, v := range values {
+				req.QueryString = append(req.QueryString, HARNameValue{Name: name, Value: v})
+			}
+		}
+
+		sort.SliceStable(req.QueryString, func(i, j int) bool {
+			return req.QueryString[i].Name < req.QueryString[j].Name
+		})
+	}
+
+	if r.HasPostData {
+		req.PostData = &HARPostData{Text: r.PostData}
+
+		for _, h := range req.Headers {
+			if strings.EqualFold(h.Name, "Content-Type") {
+				req.PostData.MimeType = h.Value
+			}
+		}
+	}
+
+	return req
+}
+
+// harHeaders returns headers ordered by name.
+func harHeaders(headers network.Headers) []HARNameValue {
+	h := make([]HARNameValue, 0, len(headers))
+	for name, v := range headers {
+		value := fmt.Sprint(v)
+		if sensitiveHeaders[strings.ToLower(name)] {
+			value = harMasked
+		}
+
+		h = append(h, HARNameValue{Name: name, Value: value})
+	}
+
+	sort.Slice(h, func(i, j int) bool {
+		return h[i].Name < h[j].Name
+	})
+
+	return h
+}
+
+// sensitiveHeaders contains the lower case names of headers, whose values
+// are masked in HARs, since they contain credentials or session cookies.
+var sensitiveHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+}
+
+// harMasked replaces the values of sensitive headers.
+const harMasked = "[masked]"
+
+// harTimings returns the phases of a request from its resource timing.
+// Timing values are milliseconds relative to the start of the request, -1 if absent.
+// Requests served from cache have no resource timing.
+func harTimings(t *network.ResourceTiming, receive float64) HARTimings {
+	if t == nil {
+		return HARTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Receive: receive}
+	}
+
+	blocked := float64(-1)
+	for _, start := range []float64{t.DNSStart, t.ConnectStart, t.SendStart} {
+		if start >= 0 {
+			blocked = start
+			break
+		}
+	}
+
+	return HARTimings{
+		Blocked: blocked,
+		DNS:     span(t.DNSStart, t.DNSEnd),
+		Connect: span(t.ConnectStart, t.ConnectEnd),
+		SSL:     span(t.SslStart, t.SslEnd),
+		Send:    nonNegative(t.SendEnd - t.SendStart),
+		Wait:    nonNegative(t.ReceiveHeadersEnd - t.SendEnd),
+		Receive: receive,
+	}
+}
+
+// total returns the time of the whole request, which is
+// the sum of its phases. SSL is part of Connect.
+func (t HARTimings) total() float64 {
+	var sum float64
+	for _, v := range []float64{t.Blocked, t.DNS, t.Connect, t.Send, t.Wait, t.Receive} {
+		if v > 0 {
+			sum += v
+		}
+	}
+
+	return sum
+}
+
+// receiveTime returns the milliseconds between the response and the end of loading.
+func receiveTime(rec *harRecord) float64 {
+	if rec.received.IsZero() || rec.finished.IsZero() {
+		return 0
+	}
+
+	return nonNegative(toMilliseconds(rec.finished.Sub(rec.received)))
+}
+
+// span returns the milliseconds between start and end, -1 if either is absent.
+func span(start, end float64) float64 {
+	if start < 0 || end < 0 {
+		return -1
+	}
+
+	return nonNegative(end - start)
+}
+
+func nonNegative(ms float64) float64 {
+	if ms < 0 {
+		return 0
+	}
+
+	return ms
+}
+
+// harDuration returns d in milliseconds, -1 if it is zero.
+func harDuration(d time.Duration) float64 {
+	if d == 0 {
+		return -1
+	}
+
+	return toMilliseconds(d)
+}
+
+func toMilliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// monotonic returns the time of t, if known.
+func monotonic(t *cdp.MonotonicTime) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return t.Time()
+}
@@ -0,0 +1,189 @@
+package runner
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func wallTime(offset time.Duration) *cdp.TimeSinceEpoch {
+	t := cdp.TimeSinceEpoch(time.Date(2020, 11, 30, 12, 0, 0, 0, time.UTC).Add(offset))
+	return &t
+}
+
+func TestHARRecorder(t *testing.T) {
+	h := newHARRecorder()
+
+	events := []interface{}{
+		&network.EventRequestWillBeSent{
+			RequestID: "doc",
+			Request:   &network.Request{URL: "http://foo.bar", Method: "GET"},
+			Timestamp: timestamp(0),
+			WallTime:  wallTime(0),
+			Type:      network.ResourceTypeDocument,
+		},
+		// the document redirects to https
+		&network.EventRequestWillBeSent{
+			RequestID: "doc",
+			Request:   &network.Request{URL: "https://foo.bar/?lang=de&a=1", Method: "GET"},
+			Timestamp: timestamp(100 * time.Millisecond),
+			WallTime:  wallTime(100 * time.Millisecond),
+			Type:      network.ResourceTypeDocument,
+			RedirectResponse: &network.Response{
+				Status:     301,
+				StatusText: "Moved Permanently",
+				Protocol:   "http/1.1",
+				Headers:    network.Headers{"Location": "https://foo.bar/?lang=de&a=1"},
+				Timing:     &network.ResourceTiming{DNSStart: 1, DNSEnd: 11, ConnectStart: 11, ConnectEnd: 41, SslStart: -1, SslEnd: -1, SendStart: 41, SendEnd: 42, ReceiveHeadersEnd: 92},
+			},
+		},
+		&network.EventResponseReceived{
+			RequestID: "doc",
+			Timestamp: timestamp(300 * time.Millisecond),
+			Type:      network.ResourceTypeDocument,
+			Response: &network.Response{
+				Status:          200,
+				StatusText:      "OK",
+				Protocol:        "h2",
+				MimeType:        "text/html",
+				RemoteIPAddress: "10.0.0.1",
+				Headers:         network.Headers{"Content-Type": "text/html", "Cache-Control": "no-cache", "Set-Cookie": "session=1"},
+				RequestHeaders:  network.Headers{":method": "GET", "Accept": "text/html", "authorization": "Basic dXNlcjpzZWNyZXQ="},
+				Timing:          &network.ResourceTiming{DNSStart: -1, DNSEnd: -1, ConnectStart: 2, ConnectEnd: 80, SslStart: 30, SslEnd: 80, SendStart: 80, SendEnd: 81, ReceiveHeadersEnd: 200},
+			},
+		},
+		&network.EventDataReceived{RequestID: "doc", DataLength: 4096},
+		&network.EventDataReceived{RequestID: "doc", DataLength: 1024},
+		&network.EventLoadingFinished{RequestID: "doc", Timestamp: timestamp(350 * time.Millisecond), EncodedDataLength: 2048},
+		&network.EventRequestWillBeSent{
+			RequestID: "js",
+			Request:   &network.Request{URL: "https://foo.bar/app.js", Method: "GET"},
+			Timestamp: timestamp(400 * time.Millisecond),
+			WallTime:  wallTime(400 * time.Millisecond),
+			Type:      network.ResourceTypeScript,
+		},
+		// served from cache
+		&network.EventResponseReceived{
+			RequestID: "js",
+			Timestamp: timestamp(410 * time.Millisecond),
+			Response:  &network.Response{Status: 200, StatusText: "OK", FromDiskCache: true},
+		},
+		&network.EventLoadingFinished{RequestID: "js", Timestamp: timestamp(415 * time.Millisecond)},
+		&network.EventRequestWillBeSent{
+			RequestID: "api",
+			Request: &network.Request{
+				URL:         "https://foo.bar/api",
+				Method:      "POST",
+				Headers:     network.Headers{"Content-Type": "application/json"},
+				PostData:    `{"q":1}`,
+				HasPostData: true,
+			},
+			Timestamp: timestamp(420 * time.Millisecond),
+			WallTime:  wallTime(420 * time.Millisecond),
+			Type:      network.ResourceTypeXHR,
+		},
+		&network.EventLoadingFailed{RequestID: "api", Timestamp: timestamp(500 * time.Millisecond), ErrorText: "net::ERR_CONNECTION_RESET"},
+		// still in flight
+		&network.EventRequestWillBeSent{
+			RequestID: "font",
+			Request:   &network.Request{URL: "https://foo.bar/font.woff2", Method: "GET"},
+			Timestamp: timestamp(450 * time.Millisecond),
+			WallTime:  wallTime(450 * time.Millisecond),
+			Type:      network.ResourceTypeFont,
+		},
+	}
+
+	for _, ev := range events {
+		h.handle(ev)
+	}
+
+	har := h.take("http://foo.bar", PageTiming{DOMContentLoaded: 250 * time.Millisecond, Load: 600 * time.Millisecond})
+
+	assert.Equal(t, "1.2", har.Log.Version)
+	require.Len(t, har.Log.Pages, 1)
+	assert.Equal(t, &HARPage{
+		StartedDateTime: wallTime(0).Time(),
+		ID:              "page_1",
+		Title:           "http://foo.bar",
+		PageTimings:     HARPageTimings{OnContentLoad: 250, OnLoad: 600},
+	}, har.Log.Pages[0])
+
+	require.Len(t, har.Log.Entries, 4)
+
+	redirect := har.Log.Entries[0]
+	assert.Equal(t, "http://foo.bar", redirect.Request.URL)
+	assert.Equal(t, 301, redirect.Response.Status)
+	assert.Equal(t, "https://foo.bar/?lang=de&a=1", redirect.Response.RedirectURL)
+	assert.Equal(t, HARTimings{Blocked: 1, DNS: 10, Connect: 30, Send: 1, Wait: 50, Receive: 0, SSL: -1}, redirect.Timings)
+	assert.Equal(t, float64(92), redirect.Time)
+
+	doc := har.Log.Entries[1]
+	assert.Equal(t, "page_1", doc.Pageref)
+	assert.Equal(t, wallTime(100*time.Millisecond).Time(), doc.StartedDateTime)
+	assert.Equal(t, "Document", doc.ResourceType)
+	assert.Equal(t, []HARNameValue{{Name: "a", Value: "1"}, {Name: "lang", Value: "de"}}, doc.Request.QueryString)
+	// credentials and cookies are masked
+	assert.Equal(t, []HARNameValue{
+		{Name: ":method", Value: "GET"},
+		{Name: "Accept", Value: "text/html"},
+		{Name: "authorization", Value: "[masked]"},
+	}, doc.Request.Headers)
+	assert.Equal(t, "h2", doc.Request.HTTPVersion)
+	assert.Equal(t, 200, doc.Response.Status)
+	assert.Equal(t, "h2", doc.Response.HTTPVersion)
+	assert.Equal(t, []HARNameValue{
+		{Name: "Cache-Control", Value: "no-cache"},
+		{Name: "Content-Type", Value: "text/html"},
+		{Name: "Set-Cookie", Value: "[masked]"},
+	}, doc.Response.Headers)
+	assert.Equal(t, HARContent{Size: 5120, MimeType: "text/html"}, doc.Response.Content)
+	assert.Equal(t, int64(2048), doc.Response.TransferSize)
+	assert.Equal(t, "10.0.0.1", doc.ServerIPAddress)
+	assert.Equal(t, HARTimings{Blocked: 2, DNS: -1, Connect: 78, Send: 1, Wait: 119, Receive: 50, SSL: 50}, doc.Timings)
+	assert.Equal(t, float64(250), doc.Time)
+
+	cached := har.Log.Entries[2]
+	assert.Equal(t, "https://foo.bar/app.js", cached.Request.URL)
+	assert.Equal(t, HARTimings{Blocked: -1, DNS: -1, Connect: -1, Receive: 5, SSL: -1}, cached.Timings)
+
+	failed := har.Log.Entries[3]
+	assert.Equal(t, "POST", failed.Request.Method)
+	assert.Equal(t, &HARPostData{MimeType: "application/json", Text: `{"q":1}`}, failed.Request.PostData)
+	assert.Equal(t, int64(7), failed.Request.BodySize)
+	assert.Equal(t, "net::ERR_CONNECTION_RESET", failed.Error)
+	assert.Zero(t, failed.Response.Status)
+
+	// the recorder is reset
+	assert.Empty(t, h.take("http://foo.bar", PageTiming{}).Log.Entries)
+}
+
+func TestHAR_JSON(t *testing.T) {
+	h := newHARRecorder()
+	h.handle(&network.EventRequestWillBeSent{
+		RequestID: "doc",
+		Request:   &network.Request{URL: "http://foo.bar", Method: "GET"},
+		WallTime:  wallTime(0),
+		Type:      network.ResourceTypeDocument,
+	})
+	h.handle(&network.EventLoadingFailed{RequestID: "doc", ErrorText: "net::ERR_NAME_NOT_RESOLVED"})
+
+	b, err := json.Marshal(h.take("http://foo.bar", PageTiming{}))
+	require.NoError(t, err)
+
+	var v map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &v))
+	assert.Equal(t, "1.2", v["log"]["version"])
+
+	// HAR requires arrays instead of null, even if they are empty
+	entry := v["log"]["entries"].([]interface{})[0].(map[string]interface{})
+	response := entry["response"].(map[string]interface{})
+	assert.Equal(t, []interface{}{}, response["headers"])
+	assert.Equal(t, []interface{}{}, response["cookies"])
+	assert.Equal(t, "2020-11-30T12:00:00Z", entry["startedDateTime"])
+	assert.Equal(t, float64(-1), v["log"]["pages"].([]interface{})[0].(map[string]interface{})["pageTimings"].(map[string]interface{})["onLoad"])
+}
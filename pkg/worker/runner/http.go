@@ -0,0 +1,89 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"time"
+)
+
+// An HTTPRunner implements the runner interface.
+// It performs plain HTTP requests with the net/http package instead of
+// loading pages in a browser, so it doesn't load any subresources, doesn't
+// execute scripts and has no cache. In return it needs only a fraction of the
+// resources of a browser, which allows simulating far more users per worker.
+type HTTPRunner struct {
+	// ID of this runner.
+	ID int
+
+	// Cookies enables keeping cookies between requests of this runner.
+	Cookies bool
+
+	// client performs the requests. Every runner has its own connection
+	// pool, just like every browser of a ChromeRunner.
+	client *http.Client
+}
+
+// NewHTTPRunner creates a new HTTP runner instance.
+func NewHTTPRunner(id int, cookies bool) *HTTPRunner {
+	return &HTTPRunner{
+		ID:      id,
+		Cookies: cookies,
+	}
+}
+
+// WithContext derives a new runner context from ctx.
+// It creates the HTTP client of the runner, whose idle connections
+// are closed once the context is canceled.
+func (r *HTTPRunner) WithContext(ctx context.Context) context.Context {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	r.client = &http.Client{Transport: transport}
+
+	if r.Cookies {
+		// cookiejar.New never returns an error without options
+		r.client.Jar, _ = cookiejar.New(nil)
+	}
+
+	runnerCtx := context.WithValue(ctx, contextKey{}, r)
+
+	go func() {
+		<-runnerCtx.Done()
+		transport.CloseIdleConnections()
+	}()
+
+	return runnerCtx
+}
+
+// httpTiming records the timestamps of a request via httptrace.
+// Redirects are followed with the same trace, so every new request
+// resets the timestamps and only the last request is measured.
+type httpTiming struct {
+	start, dnsStart, dnsDone, connectStart, connectDone time.Time
+	tlsStart, tlsDone, gotConn, wroteRequest, firstByte time.Time
+}
+
+// ttfb returns the time between the start of the request and its first response byte.
+func (t *httpTiming) ttfb() time.Duration {
+	return between(t.start, t.firstByte)
+}
+
+// network returns the network phases of the request.
+func (t *httpTiming) network() NetworkTiming {
+	return NetworkTiming{
+		DNS:     between(t.dnsStart, t.dnsDone),
+		Connect: between(t.connectStart, t.connectDone),
+		SSL:     between(t.tlsStart, t.tlsDone),
+		Send:    between(t.gotConn, t.wroteRequest),
+		Wait:    between(t.wroteRequest, t.firstByte),
+	}
+}
+
+// between returns the duration between start and end,
+// or zero if either of them didn't happen.
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.Before(start) {
+		return 0
+	}
+
+	return end.Sub(start)
+}
@@ -0,0 +1,122 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHTTPServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("hello"))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/missing", http.StatusFound)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+	})
+	mux.HandleFunc("/account", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	s := httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestCall_HTTPRunner(t *testing.T) {
+	s := newTestHTTPServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctx = NewHTTPRunner(1, false).WithContext(ctx)
+
+	res, err := Call(ctx, s.URL)
+	require.NoError(t, err)
+
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "OK", res.StatusMessage)
+	assert.False(t, res.Cached)
+	assert.GreaterOrEqual(t, int64(res.TTFB), int64(20*time.Millisecond))
+	assert.Greater(t, int64(res.Network.Connect), int64(0))
+	assert.GreaterOrEqual(t, int64(res.Network.Wait), int64(20*time.Millisecond))
+	assert.Equal(t, time.Duration(0), res.Network.SSL)
+
+	// the second request reuses the connection
+	res, err = Call(ctx, s.URL)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), res.Network.Connect)
+	assert.GreaterOrEqual(t, int64(res.TTFB), int64(20*time.Millisecond))
+}
+
+func TestCall_HTTPRunner_Redirect(t *testing.T) {
+	s := newTestHTTPServer(t)
+	ctx := NewHTTPRunner(1, false).WithContext(context.Background())
+
+	res, err := Call(ctx, s.URL+"/redirect")
+	require.NoError(t, err)
+
+	assert.Equal(t, 404, res.StatusCode)
+	assert.Equal(t, "Not Found", res.StatusMessage)
+}
+
+func TestCall_HTTPRunner_Cookies(t *testing.T) {
+	s := newTestHTTPServer(t)
+
+	for _, cookies := range []bool{true, false} {
+		ctx := NewHTTPRunner(1, cookies).WithContext(context.Background())
+
+		_, err := Call(ctx, s.URL+"/login")
+		require.NoError(t, err)
+
+		res, err := Call(ctx, s.URL+"/account")
+		require.NoError(t, err)
+
+		if cookies {
+			assert.Equal(t, 200, res.StatusCode)
+		} else {
+			assert.Equal(t, 401, res.StatusCode)
+		}
+	}
+}
+
+func TestCall_HTTPRunner_Canceled(t *testing.T) {
+	s := newTestHTTPServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = NewHTTPRunner(1, false).WithContext(ctx)
+	cancel()
+
+	res, err := Call(ctx, s.URL)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, res)
+}
+
+func TestCall_HTTPRunner_Timeout(t *testing.T) {
+	s := newTestHTTPServer(t)
+	ctx := NewHTTPRunner(1, false).WithContext(context.Background())
+
+	// the server takes 20ms to respond
+	res, err := CallWithOptions(ctx, s.URL, &RequestOptions{Timeout: 5 * time.Millisecond})
+	assert.Equal(t, ErrRequestTimeout, err)
+	assert.Nil(t, res)
+
+	res, err = CallWithOptions(ctx, s.URL, &RequestOptions{Timeout: time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/chromedp"
+)
+
+// requestInterceptor adds the extra headers of a call to the requests of the
+// page, which go to the origin of the called URL. Unlike extra headers of the
+// network domain, they aren't sent to third-party origins of subresources,
+// so credentials don't leak to i.e. CDNs or analytics.
+type requestInterceptor struct {
+	mu      sync.Mutex
+	origin  string
+	headers map[string]string
+}
+
+func newRequestInterceptor() *requestInterceptor {
+	return &requestInterceptor{}
+}
+
+// enable returns the action intercepting requests to the origin of rawurl,
+// which get headers added, or nil if rawurl has no origin.
+func (i *requestInterceptor) enable(rawurl string, headers map[string]string) chromedp.Action {
+	o := origin(rawurl)
+	if o == "" {
+		return nil
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.origin = o
+	i.headers = headers
+
+	return fetch.Enable().WithPatterns([]*fetch.RequestPattern{
+		{URLPattern: o + "/*", RequestStage: fetch.RequestStageRequest},
+	})
+}
+
+// handle returns the action continuing a paused request of ev, or nil if ev
+// isn't a paused request. Requests to the origin get the headers added, others
+// continue untouched, since the pattern may match more than the origin.
+func (i *requestInterceptor) handle(ev interface{}) chromedp.Action {
+	paused, ok := ev.(*fetch.EventRequestPaused)
+	if !ok {
+		return nil
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	continueRequest := fetch.ContinueRequest(paused.RequestID)
+	if paused.Request == nil || i.origin == "" || origin(paused.Request.URL) != i.origin {
+		return continueRequest
+	}
+
+	headers := make(map[string]string, len(paused.Request.Headers)+len(i.headers))
+	names := make(map[string]string, len(paused.Request.Headers)+len(i.headers))
+	for k, v := range paused.Request.Headers {
+		if s, ok := v.(string); ok {
+			headers[k] = s
+			names[strings.ToLower(k)] = k
+		}
+	}
+
+	// extra headers replace headers of the browser, regardless of their case
+	for k, v := range i.headers {
+		if name, ok := names[strings.ToLower(k)]; ok {
+			delete(headers, name)
+		}
+		headers[k] = v
+	}
+
+	entries := make([]*fetch.HeaderEntry, 0, len(headers))
+	for k, v := range headers {
+		entries = append(entries, &fetch.HeaderEntry{Name: k, Value: v})
+	}
+	sort.Slice(entries, func(a, b int) bool { return entries[a].Name < entries[b].Name })
+
+	return continueRequest.WithHeaders(entries)
+}
+
+// origin returns the scheme and host of rawurl, or an empty string
+// if rawurl isn't an absolute URL.
+func origin(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return ""
+	}
+
+	scheme, host := strings.ToLower(u.Scheme), strings.ToLower(u.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+
+	// chrome omits default ports from URLs
+	if port := u.Port(); port != "" && !(scheme == "http" && port == "80") && !(scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+
+	return scheme + "://" + host
+}
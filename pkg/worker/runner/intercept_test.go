@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"testing"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestInterceptor(t *testing.T) {
+	i := newRequestInterceptor()
+
+	// nothing to intercept yet
+	assert.Nil(t, i.handle(&network.EventLoadingFinished{}))
+	assert.Equal(t, fetch.ContinueRequest("1"), i.handle(&fetch.EventRequestPaused{
+		RequestID: "1",
+		Request:   &network.Request{URL: "https://foo.bar/"},
+	}))
+
+	a := i.enable("https://foo.bar:443/shop", map[string]string{
+		"X-Loadtest":    "1",
+		"Authorization": "Basic dXNlcjpzZWNyZXQ=",
+	})
+	assert.Equal(t, fetch.Enable().WithPatterns([]*fetch.RequestPattern{
+		{URLPattern: "https://foo.bar/*", RequestStage: fetch.RequestStageRequest},
+	}), a)
+
+	// requests to the origin get the headers added
+	a = i.handle(&fetch.EventRequestPaused{
+		RequestID: "2",
+		Request: &network.Request{
+			URL:     "https://foo.bar/app.js",
+			Headers: network.Headers{"Accept": "*/*", "authorization": "Bearer foo"},
+		},
+	})
+	require.IsType(t, &fetch.ContinueRequestParams{}, a)
+	assert.Equal(t, []*fetch.HeaderEntry{
+		{Name: "Accept", Value: "*/*"},
+		{Name: "Authorization", Value: "Basic dXNlcjpzZWNyZXQ="},
+		{Name: "X-Loadtest", Value: "1"},
+	}, a.(*fetch.ContinueRequestParams).Headers)
+
+	// requests to other origins continue untouched
+	assert.Equal(t, fetch.ContinueRequest("3"), i.handle(&fetch.EventRequestPaused{
+		RequestID: "3",
+		Request:   &network.Request{URL: "https://foo.bar.cdn.com/app.js"},
+	}))
+
+	// urls without an origin can't be intercepted
+	assert.Nil(t, i.enable("foo.bar", map[string]string{"X-Loadtest": "1"}))
+}
+
+func TestOrigin(t *testing.T) {
+	for rawurl, o := range map[string]string{
+		"https://foo.bar":           "https://foo.bar",
+		"HTTPS://Foo.Bar:443/a?b=c": "https://foo.bar",
+		"http://foo.bar:80/":        "http://foo.bar",
+		"http://foo.bar:8080/":      "http://foo.bar:8080",
+		"https://user@foo.bar/":     "https://foo.bar",
+		"http://[::1]:8080/":        "http://[::1]:8080",
+		"foo.bar":                   "",
+		"":                          "",
+	} {
+		assert.Equal(t, o, origin(rawurl), rawurl)
+	}
+}
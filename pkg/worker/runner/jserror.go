@@ -0,0 +1,153 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	cdplog "github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/runtime"
+)
+
+const (
+	// maxJSErrorMessages limits the amount of distinct messages reported per page.
+	maxJSErrorMessages = 20
+
+	// maxJSErrorMessageLength limits the length of a reported message in bytes.
+	maxJSErrorMessageLength = 500
+)
+
+// jsErrorCollector collects the JavaScript errors of a page, which are uncaught
+// exceptions, console.error and failed console.assert calls and errors logged
+// by the browser, i.e. failed requests. Events are delivered by the browser
+// connection while a call is in progress, so access is synchronized.
+type jsErrorCollector struct {
+	mu       sync.Mutex
+	count    int
+	messages []string
+	seen     map[string]bool
+}
+
+func newJSErrorCollector() *jsErrorCollector {
+	return &jsErrorCollector{seen: make(map[string]bool)}
+}
+
+// handle adds ev to the collected errors, if it is one.
+func (c *jsErrorCollector) handle(ev interface{}) {
+	var msg string
+
+	switch ev := ev.(type) {
+	case *runtime.EventExceptionThrown:
+		msg = exceptionMessage(ev.ExceptionDetails)
+	case *runtime.EventConsoleAPICalled:
+		if ev.Type != runtime.APITypeError && ev.Type != runtime.APITypeAssert {
+			return
+		}
+
+		args := make([]string, 0, len(ev.Args))
+		for _, a := range ev.Args {
+			args = append(args, remoteObjectString(a))
+		}
+		msg = strings.Join(args, " ")
+	case *cdplog.EventEntryAdded:
+		if ev.Entry == nil || ev.Entry.Level != cdplog.LevelError {
+			return
+		}
+
+		msg = ev.Entry.Text
+		if ev.Entry.URL != "" && !strings.Contains(msg, ev.Entry.URL) {
+			msg += " " + ev.Entry.URL
+		}
+	default:
+		return
+	}
+
+	msg = truncate(msg, maxJSErrorMessageLength)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.count++
+
+	if !c.seen[msg] && len(c.messages) < maxJSErrorMessages {
+		c.seen[msg] = true
+		c.messages = append(c.messages, msg)
+	}
+}
+
+// take returns the amount of collected errors and their distinct
+// messages in the order they first occurred and resets the collector.
+func (c *jsErrorCollector) take() (int, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count, messages := c.count, c.messages
+
+	c.count, c.messages = 0, nil
+	c.seen = make(map[string]bool)
+
+	return count, messages
+}
+
+// reset drops every collected error.
+func (c *jsErrorCollector) reset() {
+	c.take()
+}
+
+// exceptionMessage returns the message of an uncaught exception,
+// i.e. "Uncaught TypeError: a is undefined", without its stack trace.
+func exceptionMessage(d *runtime.ExceptionDetails) string {
+	if d == nil {
+		return ""
+	}
+
+	if d.Exception == nil || d.Exception.Description == "" {
+		return d.Text
+	}
+
+	desc := d.Exception.Description
+	if i := strings.IndexByte(desc, '\n'); i >= 0 {
+		desc = desc[:i]
+	}
+
+	return strings.TrimSpace(d.Text + " " + desc)
+}
+
+// remoteObjectString returns o as printed by the console of the browser.
+func remoteObjectString(o *runtime.RemoteObject) string {
+	if o == nil {
+		return ""
+	}
+
+	if len(o.Value) > 0 {
+		var v interface{}
+		if err := json.Unmarshal(o.Value, &v); err == nil {
+			if s, ok := v.(string); ok {
+				return s
+			}
+			return fmt.Sprint(v)
+		}
+	}
+
+	if o.UnserializableValue != "" {
+		return string(o.UnserializableValue)
+	}
+
+	return o.Description
+}
+
+// truncate returns the longest prefix of s with at most n bytes,
+// which doesn't split a UTF-8 encoded rune.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+
+	return s[:n]
+}
@@ -0,0 +1,98 @@
+package runner
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSErrorCollector(t *testing.T) {
+	c := newJSErrorCollector()
+
+	exception := &runtime.EventExceptionThrown{
+		ExceptionDetails: &runtime.ExceptionDetails{
+			Text: "Uncaught",
+			Exception: &runtime.RemoteObject{
+				Description: "TypeError: cart is undefined\n    at checkout (http://foo.bar/app.js:1:42)",
+			},
+		},
+	}
+
+	events := []interface{}{
+		exception,
+		exception,
+		&runtime.EventConsoleAPICalled{
+			Type: runtime.APITypeError,
+			Args: []*runtime.RemoteObject{
+				{Type: runtime.TypeString, Value: []byte(`"request failed:"`)},
+				{Type: runtime.TypeNumber, Value: []byte(`500`)},
+			},
+		},
+		// neither logs nor warnings are errors
+		&runtime.EventConsoleAPICalled{
+			Type: runtime.APITypeLog,
+			Args: []*runtime.RemoteObject{{Type: runtime.TypeString, Value: []byte(`"hello"`)}},
+		},
+		&log.EventEntryAdded{Entry: &log.Entry{Level: log.LevelWarning, Text: "deprecated"}},
+		&log.EventEntryAdded{Entry: &log.Entry{
+			Source: log.SourceNetwork,
+			Level:  log.LevelError,
+			Text:   "Failed to load resource: the server responded with a status of 500 ()",
+			URL:    "http://foo.bar/api/cart",
+		}},
+	}
+
+	for _, ev := range events {
+		c.handle(ev)
+	}
+
+	count, messages := c.take()
+	assert.Equal(t, 4, count)
+	assert.Equal(t, []string{
+		"Uncaught TypeError: cart is undefined",
+		"request failed: 500",
+		"Failed to load resource: the server responded with a status of 500 () http://foo.bar/api/cart",
+	}, messages)
+
+	count, messages = c.take()
+	assert.Equal(t, 0, count)
+	assert.Empty(t, messages)
+}
+
+func TestJSErrorCollector_Limits(t *testing.T) {
+	c := newJSErrorCollector()
+
+	for i := 0; i < maxJSErrorMessages+5; i++ {
+		c.handle(&runtime.EventExceptionThrown{
+			ExceptionDetails: &runtime.ExceptionDetails{Text: strconv.Itoa(i)},
+		})
+	}
+
+	count, messages := c.take()
+	assert.Equal(t, maxJSErrorMessages+5, count)
+	assert.Len(t, messages, maxJSErrorMessages)
+
+	// long messages are truncated, which deduplicates them
+	for i := 0; i < 2; i++ {
+		c.handle(&runtime.EventExceptionThrown{
+			ExceptionDetails: &runtime.ExceptionDetails{Text: strings.Repeat("x", maxJSErrorMessageLength+i)},
+		})
+	}
+
+	count, messages = c.take()
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{strings.Repeat("x", maxJSErrorMessageLength)}, messages)
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "foo", truncate("foo", 5))
+	assert.Equal(t, "fo", truncate("foo", 2))
+
+	// runes are never split
+	assert.Equal(t, "a", truncate("aä", 2))
+	assert.Equal(t, "aä", truncate("aä", 3))
+}
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ChromeConfig configures how chrome processes are launched or attached to.
+// The zero value launches a headless chrome found in the PATH
+// with its cache dirs in the temporary directory of the system.
+type ChromeConfig struct {
+	// ExecPath is the path of the chrome executable.
+	// If empty, chrome is searched in the default locations.
+	ExecPath string
+
+	// Flags are additional command line flags without leading dashes,
+	// either "name" or "name=value", i.e. "no-sandbox" or "lang=de".
+	// They override the default flags.
+	Flags []string
+
+	// Headful runs chrome with a visible window.
+	Headful bool
+
+	// CacheRoot is the directory, in which the cache dirs of chrome processes
+	// are created. It defaults to the temporary directory of the system.
+	CacheRoot string
+
+	// WindowWidth and WindowHeight set the size of the browser window.
+	// Zero keeps the default size of chrome.
+	WindowWidth  int
+	WindowHeight int
+
+	// IgnoreCertErrors makes chrome accept invalid TLS certificates,
+	// i.e. self-signed certificates of a staging system.
+	IgnoreCertErrors bool
+
+	// RemoteURLs are DevTools WebSocket URLs of chrome processes, which are
+	// already running. If given, runners are hosted in them instead of
+	// launching chrome, and the other settings are ignored.
+	RemoteURLs []string
+}
+
+// allocatorOptions returns the options of an exec allocator
+// launching chrome with its cache in userDataDir.
+func (c *ChromeConfig) allocatorOptions(userDataDir string) []chromedp.ExecAllocatorOption {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.DisableGPU,
+		chromedp.UserDataDir(userDataDir),
+	)
+
+	if c == nil {
+		return opts
+	}
+
+	if c.ExecPath != "" {
+		opts = append(opts, chromedp.ExecPath(c.ExecPath))
+	}
+
+	if c.Headful {
+		opts = append(opts, chromedp.Flag("headless", false))
+	}
+
+	if c.WindowWidth > 0 && c.WindowHeight > 0 {
+		opts = append(opts, chromedp.WindowSize(c.WindowWidth, c.WindowHeight))
+	}
+
+	if c.IgnoreCertErrors {
+		opts = append(opts, chromedp.Flag("ignore-certificate-errors", true))
+	}
+
+	for _, f := range c.Flags {
+		f = strings.TrimLeft(f, "-")
+
+		if i := strings.IndexByte(f, '='); i >= 0 {
+			opts = append(opts, chromedp.Flag(f[:i], f[i+1:]))
+		} else {
+			opts = append(opts, chromedp.Flag(f, true))
+		}
+	}
+
+	return opts
+}
+
+// cacheDir returns the path of the cache dir named name.
+func (c *ChromeConfig) cacheDir(name string) string {
+	root := os.TempDir()
+	if c != nil && c.CacheRoot != "" {
+		root = c.CacheRoot
+	}
+
+	return filepath.Join(root, CacheDirName, name)
+}
@@ -0,0 +1,37 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChromeConfig_CacheDir(t *testing.T) {
+	var c *ChromeConfig
+	assert.Equal(t, filepath.Join(os.TempDir(), CacheDirName, "1"), c.cacheDir("1"))
+
+	c = &ChromeConfig{CacheRoot: "/var/cache/loago"}
+	assert.Equal(t, filepath.Join("/var/cache/loago", CacheDirName, "pool-0"), c.cacheDir("pool-0"))
+}
+
+func TestChromeConfig_AllocatorOptions(t *testing.T) {
+	var c *ChromeConfig
+	defaults := len(c.allocatorOptions("/tmp/1"))
+	assert.Equal(t, defaults, len((&ChromeConfig{}).allocatorOptions("/tmp/1")))
+
+	// a window size needs both width and height
+	c = &ChromeConfig{WindowWidth: 1920}
+	assert.Equal(t, defaults, len(c.allocatorOptions("/tmp/1")))
+
+	c = &ChromeConfig{
+		ExecPath:         "/opt/chrome/chrome",
+		Flags:            []string{"no-sandbox", "--lang=de"},
+		Headful:          true,
+		WindowWidth:      1920,
+		WindowHeight:     1080,
+		IgnoreCertErrors: true,
+	}
+	assert.Equal(t, defaults+6, len(c.allocatorOptions("/tmp/1")))
+}
@@ -0,0 +1,174 @@
+package runner
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// RequestOptions customize the requests of a call.
+type RequestOptions struct {
+	// Headers are sent in addition to the headers of the runner.
+	Headers map[string]string
+
+	// UserAgent overrides the user agent of the runner, if not empty.
+	UserAgent string
+
+	// Username and Password are sent as basic auth credentials, if not empty.
+	Username string
+	Password string
+
+	// OriginOnly sends headers and credentials only with requests to the
+	// origin of the called URL instead of every request of the page.
+	// Chrome runners intercept these requests to add them, which delays
+	// each of them by a round trip to the browser and skews their timings.
+	OriginOnly bool
+
+	// Timeout limits how long loading the page may take. Requests taking longer
+	// fail with ErrRequestTimeout. Zero waits until the page loaded.
+	Timeout time.Duration
+}
+
+// headers returns the extra headers of o including the basic auth header.
+func (o *RequestOptions) headers() map[string]string {
+	if o == nil {
+		return nil
+	}
+
+	h := make(map[string]string, len(o.Headers)+1)
+	for k, v := range o.Headers {
+		h[k] = v
+	}
+
+	if o.Username != "" || o.Password != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte(o.Username + ":" + o.Password))
+		h["Authorization"] = "Basic " + credentials
+	}
+
+	return h
+}
+
+func (o *RequestOptions) originOnly() bool {
+	return o != nil && o.OriginOnly
+}
+
+func (o *RequestOptions) userAgent() string {
+	if o == nil {
+		return ""
+	}
+
+	return o.UserAgent
+}
+
+// withTimeout returns a context of ctx, which ends once the timeout of o elapsed.
+func (o *RequestOptions) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o == nil || o.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, o.Timeout)
+}
+
+// applyChrome sets the extra headers and user agent of opts for the following
+// requests of the browser, including every subresource, and emulates the device
+// and network of the runners emulation profile.
+// Extra headers and network conditions are dropped by chrome when the network
+// domain is disabled, so they must be applied after enabling it and only apply
+// to the current call. With opts.OriginOnly extra headers are instead added by
+// intercepting the requests to the origin of url, until the next call. The user
+// agent stays overridden until the next call without a user agent restores the
+// user agent of the emulation profile or the browser.
+func applyChrome(ctx context.Context, r *ChromeRunner, url string, opts *RequestOptions) error {
+	var actions []chromedp.Action
+
+	ua := opts.userAgent()
+
+	if e := r.Emulation; e != nil {
+		if !r.emulated {
+			actions = append(actions, e.device()...)
+		}
+
+		if e.throttled() {
+			actions = append(actions, e.network())
+		}
+
+		if ua == "" {
+			ua = e.UserAgent
+		}
+	}
+
+	var intercept chromedp.Action
+	if h := opts.headers(); len(h) > 0 {
+		if opts.originOnly() {
+			intercept = r.interceptor.enable(url, h)
+		} else {
+			headers := make(network.Headers, len(h))
+			for k, v := range h {
+				headers[k] = v
+			}
+			actions = append(actions, network.SetExtraHTTPHeaders(headers))
+		}
+	}
+
+	if intercept != nil {
+		actions = append(actions, intercept)
+	} else if r.intercepting {
+		actions = append(actions, fetch.Disable())
+	}
+
+	if ua != r.userAgent {
+		actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+			if r.defaultUserAgent == "" {
+				_, _, _, defaultUserAgent, _, err := browser.GetVersion().Do(ctx)
+				if err != nil {
+					return err
+				}
+				r.defaultUserAgent = defaultUserAgent
+			}
+
+			override := ua
+			if override == "" {
+				override = r.defaultUserAgent
+			}
+
+			err := emulation.SetUserAgentOverride(override).Do(ctx)
+			if err != nil {
+				return err
+			}
+
+			r.userAgent = ua
+			return nil
+		}))
+	}
+
+	if len(actions) == 0 {
+		return nil
+	}
+
+	err := r.Executor.Run(ctx, actions...)
+	if err != nil {
+		return err
+	}
+
+	r.emulated = r.Emulation != nil
+	r.intercepting = intercept != nil
+	return nil
+}
+
+// applyHTTP sets the extra headers and user agent of opts on req.
+func applyHTTP(req *http.Request, opts *RequestOptions) {
+	for k, v := range opts.headers() {
+		req.Header.Set(k, v)
+	}
+
+	if ua := opts.userAgent(); ua != "" {
+		req.Header.Set("User-Agent", ua)
+	}
+}
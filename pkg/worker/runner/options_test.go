@@ -0,0 +1,144 @@
+package runner
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/dkorittki/loago/internal/pkg/testing/browser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestOptions_Headers(t *testing.T) {
+	var opts *RequestOptions
+	assert.Nil(t, opts.headers())
+	assert.Equal(t, "", opts.userAgent())
+	assert.False(t, opts.originOnly())
+
+	opts = &RequestOptions{
+		Headers:  map[string]string{"X-Loadtest": "1"},
+		Username: "user",
+		Password: "secret",
+	}
+	assert.Equal(t, map[string]string{
+		"X-Loadtest":    "1",
+		"Authorization": "Basic dXNlcjpzZWNyZXQ=",
+	}, opts.headers())
+
+	// the headers of the options stay untouched
+	assert.Len(t, opts.Headers, 1)
+}
+
+func TestApplyChrome(t *testing.T) {
+	e := browser.NewTestExecutor()
+	e.On("Run",
+		mock.Anything,
+		mock.MatchedBy(func(a []chromedp.Action) bool {
+			if len(a) != 1 {
+				return false
+			}
+
+			p, ok := a[0].(*network.SetExtraHTTPHeadersParams)
+			return ok && p.Headers["X-Loadtest"] == "1" && p.Headers["Authorization"] == "Basic dXNlcjpzZWNyZXQ="
+		})).
+		Return(nil).
+		Once()
+
+	r := NewChromeRunner(1, e)
+
+	// neither headers nor a user agent to restore
+	err := applyChrome(context.Background(), r, "https://foo.bar", nil)
+	require.NoError(t, err)
+
+	err = applyChrome(context.Background(), r, "https://foo.bar", &RequestOptions{
+		Headers:  map[string]string{"X-Loadtest": "1"},
+		Username: "user",
+		Password: "secret",
+	})
+	require.NoError(t, err)
+	assert.False(t, r.intercepting)
+
+	e.AssertExpectations(t)
+}
+
+func TestApplyChrome_OriginOnly(t *testing.T) {
+	e := browser.NewTestExecutor()
+	e.On("Run",
+		mock.Anything,
+		mock.MatchedBy(func(a []chromedp.Action) bool {
+			if len(a) != 1 {
+				return false
+			}
+
+			p, ok := a[0].(*fetch.EnableParams)
+			return ok && len(p.Patterns) == 1 && p.Patterns[0].URLPattern == "https://foo.bar/*"
+		})).
+		Return(nil).
+		Once()
+	e.On("Run",
+		mock.Anything,
+		mock.MatchedBy(func(a []chromedp.Action) bool {
+			if len(a) != 1 {
+				return false
+			}
+
+			_, ok := a[0].(*fetch.DisableParams)
+			return ok
+		})).
+		Return(nil).
+		Once()
+
+	r := NewChromeRunner(1, e)
+
+	err := applyChrome(context.Background(), r, "https://foo.bar/shop?a=1", &RequestOptions{
+		Headers:    map[string]string{"X-Loadtest": "1"},
+		Username:   "user",
+		Password:   "secret",
+		OriginOnly: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, r.intercepting)
+
+	// the next call without headers stops intercepting requests
+	err = applyChrome(context.Background(), r, "https://foo.bar", nil)
+	require.NoError(t, err)
+	assert.False(t, r.intercepting)
+
+	e.AssertExpectations(t)
+}
+
+func TestCall_HTTPRunner_Options(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get("X-Loadtest") != "1" || r.UserAgent() != "loago" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	ctx := NewHTTPRunner(1, false).WithContext(context.Background())
+
+	res, err := Call(ctx, s.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 401, res.StatusCode)
+
+	res, err = CallWithOptions(ctx, s.URL, &RequestOptions{
+		Headers:   map[string]string{"X-Loadtest": "1"},
+		UserAgent: "loago",
+		Username:  "user",
+		Password:  "secret",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+}
@@ -0,0 +1,186 @@
+package runner
+
+import (
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// Result is the outcome of a single call of an url.
+type Result struct {
+	// TTFB is the time-to-first-byte of the document response,
+	// measured from the start of the request until the response headers were received.
+	TTFB time.Duration
+
+	// StatusCode is the HTTP status code of the document response.
+	StatusCode int
+
+	// StatusMessage is the HTTP status message of the document response.
+	StatusMessage string
+
+	// Cached indicates if the document was served from the browser cache.
+	Cached bool
+
+	// Timing contains the page load timings measured by the browser.
+	Timing PageTiming
+
+	// Network contains the network phases of the document request.
+	Network NetworkTiming
+
+	// Subresources contains every resource loaded by the page,
+	// if the runner collects them.
+	Subresources []*Subresource
+
+	// JSErrors is the amount of JavaScript errors of the page, which are
+	// uncaught exceptions, console errors and errors logged by the browser.
+	// Pages often break this way, while their document loads successfully.
+	JSErrors int
+
+	// JSErrorMessages contains the distinct messages of the JavaScript errors.
+	JSErrorMessages []string
+
+	// HAR contains every request of the page as HTTP Archive,
+	// if the runner records them.
+	HAR *HAR
+
+	// URL of the page once a journey step finished.
+	// It is only set by Perform.
+	URL string
+
+	// Duration is how long a journey step took.
+	// It is only set by Perform.
+	Duration time.Duration
+}
+
+// NetworkTiming contains the phases of a request as measured by the browser.
+// A phase is zero, if it didn't happen, i.e. DNS and Connect of a reused
+// connection or SSL of a plain HTTP request.
+type NetworkTiming struct {
+	// DNS is the duration of the DNS lookup.
+	DNS time.Duration
+
+	// Connect is the duration of the TCP connection setup, without SSL.
+	Connect time.Duration
+
+	// SSL is the duration of the SSL handshake.
+	SSL time.Duration
+
+	// Send is the duration of sending the request.
+	Send time.Duration
+
+	// Wait is the duration between sending the request and receiving
+	// the response headers, i.e. the server think time.
+	Wait time.Duration
+}
+
+// networkTiming returns the phases of a request from its resource timing.
+// Timing values are milliseconds relative to the start of the request, -1 if absent.
+func networkTiming(t *network.ResourceTiming) NetworkTiming {
+	connectEnd := t.ConnectEnd
+	if t.SslStart >= 0 {
+		connectEnd = t.SslStart
+	}
+
+	return NetworkTiming{
+		DNS:     phase(t.DNSStart, t.DNSEnd),
+		Connect: phase(t.ConnectStart, connectEnd),
+		SSL:     phase(t.SslStart, t.SslEnd),
+		Send:    phase(t.SendStart, t.SendEnd),
+		Wait:    phase(t.SendEnd, t.ReceiveHeadersEnd),
+	}
+}
+
+// phase returns the duration between start and end,
+// or zero if either of them is absent.
+func phase(start, end float64) time.Duration {
+	if start < 0 || end < start {
+		return 0
+	}
+
+	return fromMilliseconds(end - start)
+}
+
+// PageTiming contains page load timings as measured by the browser via the
+// Navigation Timing, Paint Timing, Largest Contentful Paint and Layout Instability APIs.
+// Durations are relative to the start of the navigation. Each value is zero,
+// if the browser didn't report it, i.e. a page without any content is never painted.
+type PageTiming struct {
+	// DOMContentLoaded is the end of the DOMContentLoaded event.
+	DOMContentLoaded time.Duration
+
+	// Load is the end of the load event.
+	Load time.Duration
+
+	// FirstPaint is the time of the first paint.
+	FirstPaint time.Duration
+
+	// FirstContentfulPaint is the time of the first paint of any content.
+	FirstContentfulPaint time.Duration
+
+	// LargestContentfulPaint is the render time of the largest content element.
+	LargestContentfulPaint time.Duration
+
+	// CumulativeLayoutShift is the sum of all unexpected layout shift scores.
+	CumulativeLayoutShift float64
+}
+
+// pageTimingScript collects the page timings in the browser. Largest contentful paint
+// and layout shifts are only exposed via performance observers, whose buffered
+// entries are taken synchronously, so the script doesn't need to wait for callbacks.
+const pageTimingScript = `(() => {
+	const entries = (type) => {
+		try {
+			const o = new PerformanceObserver(() => {});
+			o.observe({type: type, buffered: true});
+			const r = o.takeRecords();
+			o.disconnect();
+			return r;
+		} catch (e) {
+			return [];
+		}
+	};
+
+	const nav = performance.getEntriesByType('navigation')[0] || {};
+	const paint = {};
+	performance.getEntriesByType('paint').forEach((e) => { paint[e.name] = e.startTime; });
+
+	let lcp = 0;
+	entries('largest-contentful-paint').forEach((e) => { lcp = e.renderTime || e.loadTime || e.startTime; });
+
+	let cls = 0;
+	entries('layout-shift').forEach((e) => { if (!e.hadRecentInput) { cls += e.value; } });
+
+	return {
+		domContentLoaded: nav.domContentLoadedEventEnd || 0,
+		load: nav.loadEventEnd || 0,
+		firstPaint: paint['first-paint'] || 0,
+		firstContentfulPaint: paint['first-contentful-paint'] || 0,
+		largestContentfulPaint: lcp,
+		cumulativeLayoutShift: cls,
+	};
+})()`
+
+// pageTimingValues is the result of pageTimingScript with timings in milliseconds.
+type pageTimingValues struct {
+	DOMContentLoaded       float64 `json:"domContentLoaded"`
+	Load                   float64 `json:"load"`
+	FirstPaint             float64 `json:"firstPaint"`
+	FirstContentfulPaint   float64 `json:"firstContentfulPaint"`
+	LargestContentfulPaint float64 `json:"largestContentfulPaint"`
+	CumulativeLayoutShift  float64 `json:"cumulativeLayoutShift"`
+}
+
+func (v *pageTimingValues) pageTiming() PageTiming {
+	return PageTiming{
+		DOMContentLoaded:       fromMilliseconds(v.DOMContentLoaded),
+		Load:                   fromMilliseconds(v.Load),
+		FirstPaint:             fromMilliseconds(v.FirstPaint),
+		FirstContentfulPaint:   fromMilliseconds(v.FirstContentfulPaint),
+		LargestContentfulPaint: fromMilliseconds(v.LargestContentfulPaint),
+		CumulativeLayoutShift:  v.CumulativeLayoutShift,
+	}
+}
+
+func fromMilliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
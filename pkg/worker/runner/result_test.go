@@ -0,0 +1,33 @@
+package runner
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageTimingValues_PageTiming(t *testing.T) {
+	// as returned by pageTimingScript
+	raw := `{
+		"domContentLoaded": 120.5,
+		"load": 310.25,
+		"firstPaint": 80,
+		"firstContentfulPaint": 95.5,
+		"largestContentfulPaint": 0,
+		"cumulativeLayoutShift": 0.042
+	}`
+
+	var v pageTimingValues
+	require.NoError(t, json.Unmarshal([]byte(raw), &v))
+
+	assert.Equal(t, PageTiming{
+		DOMContentLoaded:      120500 * time.Microsecond,
+		Load:                  310250 * time.Microsecond,
+		FirstPaint:            80 * time.Millisecond,
+		FirstContentfulPaint:  95500 * time.Microsecond,
+		CumulativeLayoutShift: 0.042,
+	}, v.pageTiming())
+}
@@ -2,8 +2,15 @@ package runner
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/chromedp/cdproto/network"
@@ -13,27 +20,36 @@ import (
 
 // Call executes an request on url using the runner context.
 // ctx must be a valid runner context created with WithContext method of a runner instance.
-// It returns the response time, HTTP response code, the HTTP response message an
-// a boolean indicating if the content comes from a browser cache.
+// It returns the result of the request, i.e. the response time, the HTTP response code and
+// message, whether the content comes from a browser cache and the page load timings.
 //
-// If an error occurred while performing the request an error is returned with zero values
-// on all other return values.
-func Call(ctx context.Context, url string) (time.Duration, int, string, bool, error) {
+// If an error occurred while performing the request an error is returned with a nil result.
+// ClassifyError tells, whether the request failed or the runner can't perform requests anymore.
+func Call(ctx context.Context, url string) (*Result, error) {
+	return CallWithOptions(ctx, url, nil)
+}
+
+// CallWithOptions executes an request on url like Call, but applies opts to the request.
+// The chrome runner applies them to every subresource of the page as well.
+// The fake runner ignores them.
+func CallWithOptions(ctx context.Context, url string, opts *RequestOptions) (*Result, error) {
 	v := FromContext(ctx)
 
 	url = strings.TrimSuffix(url, "/")
 
 	switch v.(type) {
 	case *ChromeRunner:
-		return runChrome(ctx, url)
+		return runChrome(ctx, url, opts)
+	case *HTTPRunner:
+		return runHTTP(ctx, url, opts)
 	case *FakeRunner:
 		return runFake(ctx, url)
 	}
 
-	return 0, 0, "", false, ErrInvalidContext
+	return nil, ErrInvalidContext
 }
 
-func runChrome(ctx context.Context, url string) (time.Duration, int, string, bool, error) {
+func runChrome(ctx context.Context, url string, opts *RequestOptions) (*Result, error) {
 	r := FromContext(ctx).(*ChromeRunner)
 
 	log.Debug().
@@ -43,36 +59,65 @@ func runChrome(ctx context.Context, url string) (time.Duration, int, string, boo
 		Str("url", url).
 		Msg("call url")
 
+	r.subresources.reset()
+	r.har.reset()
+	r.jsErrors.reset()
+	r.navigation.reset()
+
 	err := r.Executor.Run(ctx, network.Enable())
 	if err != nil {
-		return 0, 0, "", false, err
+		return nil, err
+	}
+
+	err = applyChrome(ctx, r, url, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	err = r.Executor.Run(ctx,
+	var timing pageTimingValues
+
+	navCtx, cancel := opts.withTimeout(ctx)
+	defer cancel()
+
+	err = r.Executor.Run(navCtx,
 		chromedp.Navigate(url),
 		chromedp.Stop(),
+		chromedp.Evaluate(pageTimingScript, &timing),
 	)
 
 	if err != nil {
-		return 0, 0, "", false, err
+		if ctx.Err() == nil && navCtx.Err() == context.DeadlineExceeded {
+			return nil, stopChrome(ctx, r)
+		}
+
+		return nil, err
 	}
 
 	err = r.Executor.Run(ctx, network.Disable())
 	if err != nil {
-		return 0, 0, "", false, err
+		return nil, err
 	}
 
-	var (
-		ttfb   time.Duration
-		code   int
-		msg    string
-		cached bool
-	)
+	res := &Result{Timing: timing.pageTiming()}
+
+	if r.Subresources {
+		res.Subresources = r.subresources.take()
+	}
+
+	if r.HAR {
+		res.HAR = r.har.take(url, res.Timing)
+	}
+
+	res.JSErrors, res.JSErrorMessages = r.jsErrors.take()
 
 	// Read received network events from runner buffer,
 	// read network stats and parse ttfb.
+	// Without a response, the browser may know why the page failed to load.
 	if len(r.networkEventChan) == 0 {
-		return 0, 0, "", false, ErrNoNetworkEventFound
+		if err := r.navigation.take(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNoNetworkEventFound
 	}
 
 	func() {
@@ -86,15 +131,15 @@ func runChrome(ctx context.Context, url string) (time.Duration, int, string, boo
 						Interface("ev", ev.Response.Timing).
 						Msg("received base url network event")
 
-					code = int(ev.Response.Status)
-					msg = ev.Response.StatusText
+					res.StatusCode = int(ev.Response.Status)
+					res.StatusMessage = ev.Response.StatusText
 
-					if ev.Response.Timing.ConnectStart == -1 {
-						ttfb = 0
-						cached = true
+					if isCached(ev.Response) {
+						res.TTFB = 0
+						res.Cached = true
 					} else {
-						ttfb = time.Duration(ev.Response.Timing.ReceiveHeadersEnd-
-							ev.Response.Timing.ConnectStart) * time.Millisecond
+						res.TTFB = fromMilliseconds(ev.Response.Timing.ReceiveHeadersEnd)
+						res.Network = networkTiming(ev.Response.Timing)
 					}
 				}
 			default:
@@ -103,10 +148,123 @@ func runChrome(ctx context.Context, url string) (time.Duration, int, string, boo
 		}
 	}()
 
-	return ttfb, code, msg, cached, nil
+	return res, nil
+}
+
+// responseTimeout limits how long a browser may take to respond
+// to commands, which usually take milliseconds.
+const responseTimeout = 10 * time.Second
+
+// stopChrome stops loading the page of a timed out call, so it doesn't send
+// events during the next call, and returns ErrRequestTimeout. If the browser
+// doesn't respond within responseTimeout, it's wedged and ErrBrowserUnresponsive
+// is returned.
+func stopChrome(ctx context.Context, r *ChromeRunner) error {
+	stopCtx, cancel := context.WithTimeout(ctx, responseTimeout)
+	defer cancel()
+
+	err := r.Executor.Run(stopCtx, chromedp.Stop(), network.Disable())
+	if err != nil {
+		if ctx.Err() == nil && stopCtx.Err() == context.DeadlineExceeded {
+			return ErrBrowserUnresponsive
+		}
+
+		return err
+	}
+
+	drainNetworkEvents(r)
+	return ErrRequestTimeout
+}
+
+// isCached returns true, if r was served from a browser cache without sending a request.
+// Connect timings can't tell, since they are absent for reused connections as well,
+// but every sent request has started sending after the request started.
+func isCached(r *network.Response) bool {
+	return r.FromDiskCache || r.FromPrefetchCache || r.Timing == nil || r.Timing.SendStart <= 0
+}
+
+func runHTTP(ctx context.Context, url string, opts *RequestOptions) (*Result, error) {
+	r := FromContext(ctx).(*HTTPRunner)
+
+	log.Debug().
+		Str("component", "runner").
+		Int("id", r.ID).
+		Str("type", fmt.Sprintf("%T", r)).
+		Str("url", url).
+		Msg("call url")
+
+	var (
+		mu sync.Mutex
+		t  httpTiming
+	)
+
+	now := func(ts *time.Time) {
+		mu.Lock()
+		*ts = time.Now()
+		mu.Unlock()
+	}
+
+	trace := &httptrace.ClientTrace{
+		GetConn: func(string) {
+			mu.Lock()
+			t = httpTiming{start: time.Now()}
+			mu.Unlock()
+		},
+		DNSStart:             func(httptrace.DNSStartInfo) { now(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { now(&t.dnsDone) },
+		ConnectStart:         func(string, string) { now(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { now(&t.connectDone) },
+		TLSHandshakeStart:    func() { now(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { now(&t.tlsDone) },
+		GotConn:              func(httptrace.GotConnInfo) { now(&t.gotConn) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { now(&t.wroteRequest) },
+		GotFirstResponseByte: func() { now(&t.firstByte) },
+	}
+
+	reqCtx, cancel := opts.withTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(reqCtx, trace), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	applyHTTP(req, opts)
+
+	// errors caused by the end of a context are reported as such
+	failed := func(err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if reqCtx.Err() == context.DeadlineExceeded {
+			return ErrRequestTimeout
+		}
+		return err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, failed(err)
+	}
+	defer resp.Body.Close()
+
+	// read the whole body like a browser does, which also allows reusing the connection
+	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+		return nil, failed(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return &Result{
+		TTFB:          t.ttfb(),
+		StatusCode:    resp.StatusCode,
+		StatusMessage: strings.TrimPrefix(resp.Status, strconv.Itoa(resp.StatusCode)+" "),
+		Network:       t.network(),
+	}, nil
 }
 
-func runFake(ctx context.Context, url string) (time.Duration, int, string, bool, error) {
+func runFake(ctx context.Context, url string) (*Result, error) {
 	r := FromContext(ctx).(*FakeRunner)
 
 	log.Debug().
@@ -118,8 +276,8 @@ func runFake(ctx context.Context, url string) (time.Duration, int, string, bool,
 
 	select {
 	case <-time.After(50 * time.Millisecond):
-		return 50 * time.Millisecond, 200, "OK", false, nil
+		return &Result{TTFB: 50 * time.Millisecond, StatusCode: 200, StatusMessage: "OK"}, nil
 	case <-ctx.Done():
-		return 0, 0, "", false, context.Canceled
+		return nil, context.Canceled
 	}
 }
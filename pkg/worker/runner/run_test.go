@@ -17,7 +17,7 @@ import (
 )
 
 func isNavigateAction(a []chromedp.Action) bool {
-	if len(a) != 2 {
+	if len(a) != 3 {
 		return false
 	}
 
@@ -29,6 +29,10 @@ func isNavigateAction(a []chromedp.Action) bool {
 		return false
 	}
 
+	if reflect.TypeOf(chromedp.Evaluate("", &struct{}{})) != reflect.TypeOf(a[2]) {
+		return false
+	}
+
 	return true
 }
 
@@ -48,13 +52,14 @@ func TestCall_FakeRunner(t *testing.T) {
 	r := NewFakeRunner(1)
 	ctx := r.WithContext(context.Background())
 
-	ttfb, code, msg, cached, err := Call(ctx, "http://foo.bar")
+	res, err := Call(ctx, "http://foo.bar")
 
-	assert.NoError(t, err)
-	assert.Equal(t, 50*time.Millisecond, ttfb)
-	assert.Equal(t, 200, code)
-	assert.Equal(t, "OK", msg)
-	assert.False(t, cached)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 50*time.Millisecond, res.TTFB)
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, "OK", res.StatusMessage)
+		assert.False(t, res.Cached)
+	}
 }
 
 func TestCall_ChromeRunner(t *testing.T) {
@@ -82,14 +87,22 @@ func TestCall_ChromeRunner(t *testing.T) {
 	r := NewChromeRunner(1, e)
 	ctx := r.WithContext(context.WithValue(context.Background(), TestingKey{}, TestingVal))
 
-	ttfb, code, msg, cached, err := Call(ctx, "http://foo.bar")
+	res, err := Call(ctx, "http://foo.bar")
 
 	e.AssertExpectations(t)
-	assert.NoError(t, err)
-	assert.Equal(t, time.Duration(browser.ReceiveHeadersEnd-browser.ConnectStart)*time.Millisecond, ttfb)
-	assert.Equal(t, int(browser.Status), code)
-	assert.Equal(t, browser.StatusText, msg)
-	assert.False(t, cached)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Duration(browser.ReceiveHeadersEnd)*time.Millisecond, res.TTFB)
+		assert.Equal(t, NetworkTiming{
+			DNS:     10 * time.Millisecond,
+			Connect: 20 * time.Millisecond,
+			SSL:     25 * time.Millisecond,
+			Send:    time.Millisecond,
+			Wait:    38 * time.Millisecond,
+		}, res.Network)
+		assert.Equal(t, int(browser.Status), res.StatusCode)
+		assert.Equal(t, browser.StatusText, res.StatusMessage)
+		assert.False(t, res.Cached)
+	}
 }
 
 func TestCall_ChromeRunner_ErrorOnNetworkEnable(t *testing.T) {
@@ -107,16 +120,13 @@ func TestCall_ChromeRunner_ErrorOnNetworkEnable(t *testing.T) {
 	r := NewChromeRunner(1, e)
 	ctx := r.WithContext(context.WithValue(context.Background(), TestingKey{}, TestingVal))
 
-	ttfb, code, msg, cached, err := Call(ctx, "http://foo.bar")
+	res, err := Call(ctx, "http://foo.bar")
 
 	e.AssertExpectations(t)
 	if assert.Error(t, err) {
 		assert.Equal(t, "test network enable error", err.Error())
 	}
-	assert.Equal(t, time.Duration(0), ttfb)
-	assert.Equal(t, 0, code)
-	assert.Equal(t, "", msg)
-	assert.False(t, cached)
+	assert.Nil(t, res)
 }
 
 func TestCall_ChromeRunner_ErrorOnNavigateAction(t *testing.T) {
@@ -139,16 +149,57 @@ func TestCall_ChromeRunner_ErrorOnNavigateAction(t *testing.T) {
 	r := NewChromeRunner(1, e)
 	ctx := r.WithContext(context.WithValue(context.Background(), TestingKey{}, TestingVal))
 
-	ttfb, code, msg, cached, err := Call(ctx, "http://foo.bar")
+	res, err := Call(ctx, "http://foo.bar")
 
 	e.AssertExpectations(t)
 	if assert.Error(t, err) {
 		assert.Equal(t, "test navigate error", err.Error())
 	}
-	assert.Equal(t, time.Duration(0), ttfb)
-	assert.Equal(t, 0, code)
-	assert.Equal(t, "", msg)
-	assert.False(t, cached)
+	assert.Nil(t, res)
+}
+
+func TestCall_ChromeRunner_Timeout(t *testing.T) {
+	e := browser.NewEventTestExecutor()
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(isNetworkEnableAction)).
+		Return(nil).
+		Once()
+	e.On("ListenTarget",
+		mock.MatchedBy(isChromeDPContext),
+		mock.AnythingOfType("func(interface {})")).
+		Once()
+	// the page never loads
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(isNavigateAction)).
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(context.DeadlineExceeded).
+		Once()
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(func(a []chromedp.Action) bool {
+			if len(a) != 2 {
+				return false
+			}
+
+			_, stop := a[0].(*page.StopLoadingParams)
+			_, disable := a[1].(*network.DisableParams)
+			return stop && disable
+		})).
+		Return(nil).
+		Once()
+
+	r := NewChromeRunner(1, e)
+	ctx := r.WithContext(context.WithValue(context.Background(), TestingKey{}, TestingVal))
+
+	res, err := CallWithOptions(ctx, "http://foo.bar", &RequestOptions{Timeout: 10 * time.Millisecond})
+
+	e.AssertExpectations(t)
+	assert.Equal(t, ErrRequestTimeout, err)
+	assert.Nil(t, res)
 }
 
 func TestCall_ChromeRunner_ErrorOnNetworkDisable(t *testing.T) {
@@ -176,16 +227,13 @@ func TestCall_ChromeRunner_ErrorOnNetworkDisable(t *testing.T) {
 	r := NewChromeRunner(1, e)
 	ctx := r.WithContext(context.WithValue(context.Background(), TestingKey{}, TestingVal))
 
-	ttfb, code, msg, cached, err := Call(ctx, "http://foo.bar")
+	res, err := Call(ctx, "http://foo.bar")
 
 	e.AssertExpectations(t)
 	if assert.Error(t, err) {
 		assert.Equal(t, "test network disable error", err.Error())
 	}
-	assert.Equal(t, time.Duration(0), ttfb)
-	assert.Equal(t, 0, code)
-	assert.Equal(t, "", msg)
-	assert.False(t, cached)
+	assert.Nil(t, res)
 }
 
 func TestCall_ChromeRunner_EmptyNetworkEventBuffer(t *testing.T) {
@@ -213,16 +261,13 @@ func TestCall_ChromeRunner_EmptyNetworkEventBuffer(t *testing.T) {
 	r := NewChromeRunner(1, e)
 	ctx := r.WithContext(context.WithValue(context.Background(), TestingKey{}, TestingVal))
 
-	ttfb, code, msg, cached, err := Call(ctx, "http://foo.bar")
+	res, err := Call(ctx, "http://foo.bar")
 
 	e.AssertExpectations(t)
 	if assert.Error(t, err) {
 		assert.Equal(t, ErrNoNetworkEventFound, err)
 	}
-	assert.Equal(t, time.Duration(0), ttfb)
-	assert.Equal(t, 0, code)
-	assert.Equal(t, "", msg)
-	assert.False(t, cached)
+	assert.Nil(t, res)
 
 }
 
@@ -251,22 +296,79 @@ func TestCall_ChromeRunner_CachedHTTPResponse(t *testing.T) {
 	r := NewChromeRunner(1, e)
 	ctx := r.WithContext(context.WithValue(context.Background(), TestingKey{}, TestingVal))
 
-	ttfb, code, msg, cached, err := Call(ctx, "http://foo.bar")
+	res, err := Call(ctx, "http://foo.bar")
 
 	e.AssertExpectations(t)
-	assert.NoError(t, err)
-	assert.Equal(t, time.Duration(0), ttfb)
-	assert.Equal(t, int(browser.Status), code)
-	assert.Equal(t, browser.StatusText, msg)
-	assert.True(t, cached)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Duration(0), res.TTFB)
+		assert.Equal(t, int(browser.Status), res.StatusCode)
+		assert.Equal(t, browser.StatusText, res.StatusMessage)
+		assert.True(t, res.Cached)
+	}
+}
+
+func TestCall_ChromeRunner_CachedResponses(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *network.Response
+	}{
+		{"disk cache", &network.Response{FromDiskCache: true, Timing: &network.ResourceTiming{ConnectStart: -1, SendStart: -1}}},
+		{"prefetch cache", &network.Response{FromPrefetchCache: true}},
+		{"no timing", &network.Response{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.response.URL = browser.URL
+			tt.response.Status = browser.Status
+			tt.response.StatusText = browser.StatusText
+
+			e := browser.NewResponseTestExecutor(tt.response)
+			e.On("Run", mock.Anything, mock.Anything).Return(nil)
+			e.On("ListenTarget",
+				mock.MatchedBy(isChromeDPContext),
+				mock.AnythingOfType("func(interface {})")).
+				Once()
+
+			r := NewChromeRunner(1, e)
+			ctx := r.WithContext(context.WithValue(context.Background(), TestingKey{}, TestingVal))
+
+			res, err := Call(ctx, "http://foo.bar")
+
+			e.AssertExpectations(t)
+			if assert.NoError(t, err) {
+				assert.Equal(t, time.Duration(0), res.TTFB)
+				assert.Equal(t, int(browser.Status), res.StatusCode)
+				assert.True(t, res.Cached)
+				assert.Equal(t, NetworkTiming{}, res.Network)
+			}
+		})
+	}
 }
 
 func TestCall_InvalidRunner(t *testing.T) {
-	ttfb, code, msg, cached, err := Call(context.Background(), "http://foo.bar")
+	res, err := Call(context.Background(), "http://foo.bar")
 
 	assert.Error(t, err)
-	assert.Equal(t, time.Duration(0), ttfb)
-	assert.Equal(t, 0, code)
-	assert.Equal(t, "", msg)
-	assert.False(t, cached)
+	assert.Nil(t, res)
+}
+
+func TestIsCached(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *network.Response
+		cached   bool
+	}{
+		{"new connection", &network.Response{Timing: &network.ResourceTiming{ConnectStart: 10, SendStart: 20}}, false},
+		{"reused connection", &network.Response{Timing: &network.ResourceTiming{ConnectStart: -1, SendStart: 1}}, false},
+		{"disk cache", &network.Response{FromDiskCache: true, Timing: &network.ResourceTiming{SendStart: -1}}, true},
+		{"prefetch cache", &network.Response{FromPrefetchCache: true}, true},
+		{"no request sent", &network.Response{Timing: &network.ResourceTiming{ConnectStart: -1, SendStart: -1}}, true},
+		{"no send timing", &network.Response{Timing: &network.ResourceTiming{ConnectStart: -1}}, true},
+		{"no timing", &network.Response{}, true},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.cached, isCached(tt.response), tt.name)
+	}
 }
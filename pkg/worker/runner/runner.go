@@ -14,6 +14,14 @@ var (
 
 	// ErrNoNetworkEventFound is an error indicating that no network event was found.
 	ErrNoNetworkEventFound = errors.New("no network event for base url found")
+
+	// ErrRequestTimeout is an error indicating that a page didn't load within
+	// the timeout of its request options.
+	ErrRequestTimeout = errors.New("request timed out")
+
+	// ErrBrowserUnresponsive is an error indicating that a browser
+	// doesn't respond to commands anymore.
+	ErrBrowserUnresponsive = errors.New("browser doesn't respond")
 )
 
 type contextKey struct{}
@@ -0,0 +1,326 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrUnsupportedStep is an error indicating that a runner can't perform an action.
+	ErrUnsupportedStep = errors.New("step action not supported by runner")
+
+	// ErrStepTimeout is an error indicating that a step didn't finish within its timeout,
+	// i.e. because the element it waits for never appeared.
+	ErrStepTimeout = errors.New("step timed out")
+
+	// ErrAssertionFailed is an error indicating that a page doesn't contain an expected text.
+	ErrAssertionFailed = errors.New("assertion failed")
+)
+
+// DefaultStepTimeout is the timeout of steps which don't specify one.
+const DefaultStepTimeout = 30 * time.Second
+
+// StepAction is the action performed by a step of a journey.
+type StepAction int
+
+const (
+	// StepNavigate loads the page at URL.
+	StepNavigate StepAction = iota
+
+	// StepClick clicks the element matching Selector.
+	StepClick
+
+	// StepType types Value into the field matching Selector.
+	StepType
+
+	// StepSubmit submits the form of the element matching Selector.
+	StepSubmit
+
+	// StepWait waits until the element matching Selector is visible.
+	StepWait
+
+	// StepSleep pauses for Duration, i.e. to simulate a user reading a page.
+	StepSleep
+
+	// StepAssertText fails the step, unless the text of the element matching
+	// Selector (or the whole page, if empty) contains Value.
+	StepAssertText
+
+	// StepSetCookie sets the cookie Key to Value for URL.
+	StepSetCookie
+
+	// StepSetLocalStorage sets the local storage item Key to Value
+	// for the origin of the current page.
+	StepSetLocalStorage
+)
+
+var stepActionNames = map[StepAction]string{
+	StepNavigate:        "navigate",
+	StepClick:           "click",
+	StepType:            "type",
+	StepSubmit:          "submit",
+	StepWait:            "wait",
+	StepSleep:           "sleep",
+	StepAssertText:      "assert_text",
+	StepSetCookie:       "set_cookie",
+	StepSetLocalStorage: "set_local_storage",
+}
+
+func (a StepAction) String() string {
+	if s, ok := stepActionNames[a]; ok {
+		return s
+	}
+
+	return fmt.Sprintf("StepAction(%d)", int(a))
+}
+
+// A Step is a single action of a user journey.
+// Selectors are CSS selectors.
+type Step struct {
+	// Action performed by the step.
+	Action StepAction
+
+	// URL to navigate to.
+	URL string
+
+	// Selector of the element the step interacts with.
+	Selector string
+
+	// Key is the name of a cookie or local storage item to set.
+	Key string
+
+	// Value to type, the text to assert or the value to set.
+	Value string
+
+	// Duration to sleep.
+	Duration time.Duration
+
+	// Timeout limits how long the step waits for its element.
+	// Zero uses DefaultStepTimeout. Navigation steps fail with
+	// ErrRequestTimeout, if loading the page takes longer,
+	// and wait until the page loaded, if it is zero.
+	Timeout time.Duration
+}
+
+func (s *Step) timeout() time.Duration {
+	if s.Timeout > 0 {
+		return s.Timeout
+	}
+
+	return DefaultStepTimeout
+}
+
+// Perform executes a step of a journey using the runner context.
+// ctx must be a valid runner context created with WithContext method of a runner instance.
+// Navigation steps return the same result as Call, every other step returns the
+// URL of the page once the step finished and, if the step caused a navigation
+// (i.e. by clicking a link), the status code and TTFB of the loaded document.
+// Duration always contains how long the step took.
+//
+// If the step fails, an error is returned with a nil result.
+// The HTTP runner only supports navigation, sleep and, if it keeps cookies, set cookie steps.
+func Perform(ctx context.Context, step *Step) (*Result, error) {
+	start := time.Now()
+
+	var (
+		res *Result
+		err error
+	)
+
+	switch {
+	case FromContext(ctx) == nil:
+		return nil, ErrInvalidContext
+	case step.Action == StepSleep:
+		res, err = sleep(ctx, step.Duration)
+	case step.Action == StepNavigate:
+		res, err = CallWithOptions(ctx, step.URL, &RequestOptions{Timeout: step.Timeout})
+		if err == nil {
+			res.URL = step.URL
+		}
+	default:
+		res, err = interact(ctx, step)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	res.Duration = time.Since(start)
+	return res, nil
+}
+
+func sleep(ctx context.Context, d time.Duration) (*Result, error) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return &Result{}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// interact performs a step interacting with the current page.
+func interact(ctx context.Context, step *Step) (*Result, error) {
+	switch r := FromContext(ctx).(type) {
+	case *ChromeRunner:
+		return interactChrome(ctx, r, step)
+	case *HTTPRunner:
+		return interactHTTP(r, step)
+	case *FakeRunner:
+		return &Result{}, nil
+	}
+
+	return nil, ErrUnsupportedStep
+}
+
+func interactChrome(ctx context.Context, r *ChromeRunner, step *Step) (*Result, error) {
+	log.Debug().
+		Str("component", "runner").
+		Int("id", r.ID).
+		Str("action", step.Action.String()).
+		Str("selector", step.Selector).
+		Msg("perform step")
+
+	var (
+		text     string
+		location string
+		actions  []chromedp.Action
+	)
+
+	switch step.Action {
+	case StepClick:
+		actions = append(actions, chromedp.Click(step.Selector, chromedp.ByQuery))
+	case StepType:
+		actions = append(actions, chromedp.SendKeys(step.Selector, step.Value, chromedp.ByQuery))
+	case StepSubmit:
+		actions = append(actions, chromedp.Submit(step.Selector, chromedp.ByQuery))
+	case StepWait:
+		actions = append(actions, chromedp.WaitVisible(step.Selector, chromedp.ByQuery))
+	case StepAssertText:
+		selector := step.Selector
+		if selector == "" {
+			selector = "body"
+		}
+		actions = append(actions, chromedp.Text(selector, &text, chromedp.ByQuery))
+	case StepSetCookie:
+		actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+			_, err := network.SetCookie(step.Key, step.Value).WithURL(step.URL).Do(ctx)
+			return err
+		}))
+	case StepSetLocalStorage:
+		// keys and values are quoted as JSON strings, which are valid javascript strings
+		key, _ := json.Marshal(step.Key)
+		value, _ := json.Marshal(step.Value)
+		script := fmt.Sprintf("localStorage.setItem(%s, %s)", key, value)
+		actions = append(actions, chromedp.Evaluate(script, &[]byte{}))
+	default:
+		return nil, ErrUnsupportedStep
+	}
+
+	actions = append(actions, chromedp.Location(&location))
+
+	r.subresources.reset()
+	r.jsErrors.reset()
+	drainNetworkEvents(r)
+
+	err := r.Executor.Run(ctx, network.Enable())
+	if err != nil {
+		return nil, err
+	}
+
+	// steps don't override the user agent, restore the default
+	// in case the previous call overrode it, and keep emulating the network
+	err = applyChrome(ctx, r, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	stepCtx, cancel := context.WithTimeout(ctx, step.timeout())
+	defer cancel()
+
+	err = r.Executor.Run(stepCtx, actions...)
+	if err != nil {
+		if ctx.Err() == nil && stepCtx.Err() == context.DeadlineExceeded {
+			return nil, ErrStepTimeout
+		}
+
+		return nil, err
+	}
+
+	err = r.Executor.Run(ctx, network.Disable())
+	if err != nil {
+		return nil, err
+	}
+
+	if step.Action == StepAssertText && !strings.Contains(text, step.Value) {
+		return nil, ErrAssertionFailed
+	}
+
+	res := &Result{URL: location}
+
+	if r.Subresources {
+		res.Subresources = r.subresources.take()
+	}
+
+	res.JSErrors, res.JSErrorMessages = r.jsErrors.take()
+
+	// the last document loaded during the step is the one the user sees
+	for _, ev := range drainNetworkEvents(r) {
+		res.StatusCode = int(ev.Response.Status)
+		res.StatusMessage = ev.Response.StatusText
+		res.Cached = isCached(ev.Response)
+
+		res.TTFB, res.Network = 0, NetworkTiming{}
+
+		if !res.Cached {
+			res.TTFB = fromMilliseconds(ev.Response.Timing.ReceiveHeadersEnd)
+			res.Network = networkTiming(ev.Response.Timing)
+		}
+	}
+
+	return res, nil
+}
+
+func interactHTTP(r *HTTPRunner, step *Step) (*Result, error) {
+	if step.Action != StepSetCookie || r.client.Jar == nil {
+		return nil, ErrUnsupportedStep
+	}
+
+	u, err := url.Parse(step.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	r.client.Jar.SetCookies(u, []*http.Cookie{{Name: step.Key, Value: step.Value}})
+	return &Result{URL: step.URL}, nil
+}
+
+// drainNetworkEvents removes every buffered document event
+// from the runner and returns them in the order they were received.
+func drainNetworkEvents(r *ChromeRunner) []*network.EventResponseReceived {
+	var events []*network.EventResponseReceived
+
+	for {
+		select {
+		case ev, ok := <-r.networkEventChan:
+			if !ok {
+				return events
+			}
+			events = append(events, ev)
+		default:
+			return events
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+	"github.com/dkorittki/loago/internal/pkg/testing/browser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// isStepAction checks, if a is a step action followed by reading the location.
+func isStepAction(a []chromedp.Action) bool {
+	return len(a) == 2
+}
+
+func newStepTestRunner(t *testing.T) (*ChromeRunner, *browser.TestExecutor, context.Context) {
+	e := browser.NewTestExecutor()
+	e.On("ListenTarget",
+		mock.MatchedBy(isChromeDPContext),
+		mock.AnythingOfType("func(interface {})")).
+		Once()
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(isNetworkEnableAction)).
+		Return(nil)
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(isNetworkDisableAction)).
+		Return(nil)
+
+	r := NewChromeRunner(1, e)
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), TestingKey{}, TestingVal))
+	t.Cleanup(cancel)
+
+	return r, e, r.WithContext(ctx)
+}
+
+func TestPerform_FakeRunner(t *testing.T) {
+	ctx := NewFakeRunner(1).WithContext(context.Background())
+
+	res, err := Perform(ctx, &Step{Action: StepNavigate, URL: "http://foo.bar"})
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "http://foo.bar", res.URL)
+	assert.GreaterOrEqual(t, int64(res.Duration), int64(50*time.Millisecond))
+
+	res, err = Perform(ctx, &Step{Action: StepClick, Selector: "#buy"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, res.StatusCode)
+
+	res, err = Perform(ctx, &Step{Action: StepSleep, Duration: 100 * time.Millisecond})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, int64(res.Duration), int64(100*time.Millisecond))
+}
+
+func TestPerform_InvalidContext(t *testing.T) {
+	res, err := Perform(context.Background(), &Step{Action: StepSleep})
+	assert.Equal(t, ErrInvalidContext, err)
+	assert.Nil(t, res)
+}
+
+func TestPerform_HTTPRunner_Unsupported(t *testing.T) {
+	ctx := NewHTTPRunner(1, false).WithContext(context.Background())
+
+	res, err := Perform(ctx, &Step{Action: StepClick, Selector: "#buy"})
+	assert.Equal(t, ErrUnsupportedStep, err)
+	assert.Nil(t, res)
+}
+
+func TestPerform_SleepCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = NewFakeRunner(1).WithContext(ctx)
+	cancel()
+
+	res, err := Perform(ctx, &Step{Action: StepSleep, Duration: time.Minute})
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, res)
+}
+
+func TestPerform_ChromeRunner_Click(t *testing.T) {
+	r, e, ctx := newStepTestRunner(t)
+
+	// clicking a link loads a new document
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(isStepAction)).
+		Run(func(mock.Arguments) {
+			r.networkEventChan <- &network.EventResponseReceived{
+				Type: network.ResourceTypeDocument,
+				Response: &network.Response{
+					Status:     200,
+					StatusText: "OK",
+					Timing:     &network.ResourceTiming{DNSStart: -1, ConnectStart: -1, SslStart: -1, SendStart: 1, SendEnd: 2, ReceiveHeadersEnd: 80},
+				},
+			}
+		}).
+		Return(nil).
+		Once()
+
+	res, err := Perform(ctx, &Step{Action: StepClick, Selector: "#checkout"})
+	require.NoError(t, err)
+
+	e.AssertExpectations(t)
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "OK", res.StatusMessage)
+	assert.Equal(t, 80*time.Millisecond, res.TTFB)
+	assert.Equal(t, 78*time.Millisecond, res.Network.Wait)
+	assert.Greater(t, int64(res.Duration), int64(0))
+}
+
+func TestPerform_ChromeRunner_AssertText(t *testing.T) {
+	_, e, ctx := newStepTestRunner(t)
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(isStepAction)).
+		Return(nil).
+		Once()
+
+	res, err := Perform(ctx, &Step{Action: StepAssertText, Value: "Thank you for your order"})
+	assert.Equal(t, ErrAssertionFailed, err)
+	assert.Nil(t, res)
+}
+
+func TestPerform_ChromeRunner_Timeout(t *testing.T) {
+	_, e, ctx := newStepTestRunner(t)
+	e.On("Run",
+		mock.MatchedBy(isChromeRunnerContext),
+		mock.MatchedBy(isStepAction)).
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(context.DeadlineExceeded).
+		Once()
+
+	res, err := Perform(ctx, &Step{Action: StepWait, Selector: "#never", Timeout: 50 * time.Millisecond})
+	assert.Equal(t, ErrStepTimeout, err)
+	assert.Nil(t, res)
+}
+
+func TestPerform_HTTPRunner_SetCookie(t *testing.T) {
+	s := newTestHTTPServer(t)
+
+	ctx := NewHTTPRunner(1, true).WithContext(context.Background())
+
+	_, err := Perform(ctx, &Step{Action: StepSetCookie, URL: s.URL, Key: "session", Value: "secret"})
+	require.NoError(t, err)
+
+	res, err := Perform(ctx, &Step{Action: StepNavigate, URL: s.URL + "/account"})
+	require.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+
+	// without cookies there is no jar to set the cookie in
+	ctx = NewHTTPRunner(1, false).WithContext(context.Background())
+
+	_, err = Perform(ctx, &Step{Action: StepSetCookie, URL: s.URL, Key: "session", Value: "secret"})
+	assert.Equal(t, ErrUnsupportedStep, err)
+}
@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+)
+
+// Subresource is a single resource loaded by a page, i.e. a script, stylesheet,
+// image, font or XHR/fetch request.
+type Subresource struct {
+	// URL of the resource.
+	URL string
+
+	// Type of the resource as reported by the browser, i.e. "Script" or "XHR".
+	Type string
+
+	// StatusCode is the HTTP status code of the response, zero if the request failed.
+	StatusCode int
+
+	// StatusMessage is the HTTP status message of the response.
+	StatusMessage string
+
+	// TTFB is the time-to-first-byte of the response.
+	TTFB time.Duration
+
+	// Size is the amount of bytes received over the network, including headers.
+	Size int64
+
+	// Cached indicates if the resource was served from the browser cache.
+	Cached bool
+
+	// Initiator is the type of what caused the request, i.e. "parser" or "script".
+	Initiator string
+
+	// InitiatorURL is the URL of the resource which caused the request, if known.
+	InitiatorURL string
+
+	// Error describes why the request failed, if it did.
+	Error string
+
+	// start is the time at which the request was sent, used for ordering.
+	start time.Time
+
+	// done is true, once the request finished or failed.
+	done bool
+}
+
+// subresourceCollector collects the subresources of a page from network events.
+// Events are delivered by the browser connection while a call is in progress,
+// so access is synchronized.
+type subresourceCollector struct {
+	mu        sync.Mutex
+	resources map[network.RequestID]*Subresource
+}
+
+func newSubresourceCollector() *subresourceCollector {
+	return &subresourceCollector{resources: make(map[network.RequestID]*Subresource)}
+}
+
+// handle updates the collected subresources with ev.
+func (c *subresourceCollector) handle(ev interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		if ev.Type == network.ResourceTypeDocument {
+			return
+		}
+
+		s := &Subresource{
+			URL:  ev.Request.URL,
+			Type: ev.Type.String(),
+		}
+
+		if ev.Timestamp != nil {
+			s.start = ev.Timestamp.Time()
+		}
+
+		if ev.Initiator != nil {
+			s.Initiator = ev.Initiator.Type.String()
+			s.InitiatorURL = initiatorURL(ev.Initiator)
+		}
+
+		c.resources[ev.RequestID] = s
+	case *network.EventResponseReceived:
+		s, ok := c.resources[ev.RequestID]
+		if !ok {
+			return
+		}
+
+		s.StatusCode = int(ev.Response.Status)
+		s.StatusMessage = ev.Response.StatusText
+		s.Cached = isCached(ev.Response)
+
+		if !s.Cached {
+			s.TTFB = fromMilliseconds(ev.Response.Timing.ReceiveHeadersEnd)
+		}
+	case *network.EventLoadingFinished:
+		if s, ok := c.resources[ev.RequestID]; ok {
+			s.Size = int64(ev.EncodedDataLength)
+			s.done = true
+		}
+	case *network.EventLoadingFailed:
+		if s, ok := c.resources[ev.RequestID]; ok {
+			s.Error = ev.ErrorText
+			s.done = true
+		}
+	}
+}
+
+// take returns every finished subresource in the order their requests were
+// sent and resets the collector. Requests still in flight are dropped.
+func (c *subresourceCollector) take() []*Subresource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var resources []*Subresource
+	for _, s := range c.resources {
+		if s.done {
+			resources = append(resources, s)
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].start.Before(resources[j].start)
+	})
+
+	c.resources = make(map[network.RequestID]*Subresource)
+	return resources
+}
+
+// initiatorURL returns the URL of the resource which caused a request.
+// Requests made by scripts carry it in the top frame of their stack trace.
+func initiatorURL(i *network.Initiator) string {
+	if i.URL != "" || i.Stack == nil || len(i.Stack.CallFrames) == 0 {
+		return i.URL
+	}
+
+	return i.Stack.CallFrames[0].URL
+}
+
+// reset drops every collected subresource.
+func (c *subresourceCollector) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.resources = make(map[network.RequestID]*Subresource)
+}
@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func timestamp(offset time.Duration) *cdp.MonotonicTime {
+	t := cdp.MonotonicTime(time.Unix(0, 0).Add(offset))
+	return &t
+}
+
+func TestSubresourceCollector(t *testing.T) {
+	c := newSubresourceCollector()
+
+	events := []interface{}{
+		// documents aren't subresources
+		&network.EventRequestWillBeSent{
+			RequestID: "doc",
+			Request:   &network.Request{URL: "http://foo.bar"},
+			Timestamp: timestamp(0),
+			Type:      network.ResourceTypeDocument,
+		},
+		&network.EventRequestWillBeSent{
+			RequestID: "api",
+			Request:   &network.Request{URL: "http://foo.bar/api"},
+			Timestamp: timestamp(2 * time.Second),
+			Type:      network.ResourceTypeXHR,
+			Initiator: &network.Initiator{
+				Type:  network.InitiatorTypeScript,
+				Stack: &runtime.StackTrace{CallFrames: []*runtime.CallFrame{{URL: "http://foo.bar/app.js"}}},
+			},
+		},
+		&network.EventRequestWillBeSent{
+			RequestID: "js",
+			Request:   &network.Request{URL: "http://foo.bar/app.js"},
+			Timestamp: timestamp(time.Second),
+			Type:      network.ResourceTypeScript,
+			Initiator: &network.Initiator{Type: network.InitiatorTypeParser, URL: "http://foo.bar"},
+		},
+		&network.EventRequestWillBeSent{
+			RequestID: "img",
+			Request:   &network.Request{URL: "http://foo.bar/img.png"},
+			Timestamp: timestamp(3 * time.Second),
+			Type:      network.ResourceTypeImage,
+		},
+		&network.EventRequestWillBeSent{
+			RequestID: "font",
+			Request:   &network.Request{URL: "http://foo.bar/font.woff2"},
+			Timestamp: timestamp(4 * time.Second),
+			Type:      network.ResourceTypeFont,
+		},
+		&network.EventResponseReceived{
+			RequestID: "js",
+			Response: &network.Response{
+				Status:        200,
+				StatusText:    "OK",
+				FromDiskCache: true,
+			},
+		},
+		&network.EventResponseReceived{
+			RequestID: "api",
+			Response: &network.Response{
+				Status:     503,
+				StatusText: "Service Unavailable",
+				Timing:     &network.ResourceTiming{ConnectStart: -1, SendStart: 1, ReceiveHeadersEnd: 250},
+			},
+		},
+		&network.EventLoadingFinished{RequestID: "js", EncodedDataLength: 1024},
+		&network.EventLoadingFinished{RequestID: "api", EncodedDataLength: 128},
+		&network.EventLoadingFailed{RequestID: "img", ErrorText: "net::ERR_CONNECTION_REFUSED"},
+		// still in flight: font
+	}
+
+	for _, ev := range events {
+		c.handle(ev)
+	}
+
+	res := c.take()
+	require.Len(t, res, 3)
+
+	assert.Equal(t, "http://foo.bar/app.js", res[0].URL)
+	assert.Equal(t, "Script", res[0].Type)
+	assert.True(t, res[0].Cached)
+	assert.Equal(t, time.Duration(0), res[0].TTFB)
+	assert.Equal(t, int64(1024), res[0].Size)
+	assert.Equal(t, "parser", res[0].Initiator)
+	assert.Equal(t, "http://foo.bar", res[0].InitiatorURL)
+
+	assert.Equal(t, "http://foo.bar/api", res[1].URL)
+	assert.Equal(t, "XHR", res[1].Type)
+	assert.Equal(t, 503, res[1].StatusCode)
+	assert.Equal(t, "Service Unavailable", res[1].StatusMessage)
+	assert.False(t, res[1].Cached)
+	assert.Equal(t, 250*time.Millisecond, res[1].TTFB)
+	assert.Equal(t, "script", res[1].Initiator)
+	assert.Equal(t, "http://foo.bar/app.js", res[1].InitiatorURL)
+
+	assert.Equal(t, "http://foo.bar/img.png", res[2].URL)
+	assert.Equal(t, 0, res[2].StatusCode)
+	assert.Equal(t, "net::ERR_CONNECTION_REFUSED", res[2].Error)
+
+	// taking resets the collector
+	assert.Empty(t, c.take())
+}